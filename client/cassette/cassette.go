@@ -0,0 +1,295 @@
+// Package cassette records the HTTP exchanges spacectl makes with Spacelift,
+// including presigned URL transfers, and replays them without network access.
+// This makes bug reports reproducible: a cassette recorded by the reporter can
+// be replayed by maintainers.
+//
+// Credentials are redacted before anything is written: authorization headers
+// and cookies, secrets, tokens and environment values in JSON bodies, and the
+// signatures of presigned URLs. The bodies transferred through presigned URLs,
+// like state files and logs, are only recorded as their size. The same
+// redaction is applied to requests when replaying, so they still match.
+package cassette
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"unicode/utf8"
+)
+
+const (
+	// EnvRecord is the name of the environment variable pointing to the
+	// directory to record exchanges into.
+	EnvRecord = "SPACECTL_RECORD"
+
+	// EnvReplay is the name of the environment variable pointing to the
+	// directory to replay exchanges from.
+	EnvReplay = "SPACECTL_REPLAY"
+
+	// Redacted replaces credentials in recorded exchanges.
+	Redacted = "REDACTED"
+)
+
+var (
+	errNoInteraction = errors.New("no recorded interaction matches the request")
+
+	sensitiveHeaders   = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Amz-Security-Token"}
+	sensitiveJSONKey   = regexp.MustCompile(`(?i)token|secret|password|jwt|credential`)
+	valueJSONKey       = regexp.MustCompile(`(?i)^value$`)
+	sensitiveURLParams = regexp.MustCompile(`(?i)signature|credential|token|^sig$`)
+)
+
+// Interaction is a recorded request and its response.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is a recorded request. Bodies that are not text, like workspace
+// archives, and bodies uploaded to presigned URLs are only recorded as their
+// size.
+type Request struct {
+	Method   string      `json:"method"`
+	URL      string      `json:"url"`
+	Headers  http.Header `json:"headers,omitempty"`
+	Body     string      `json:"body,omitempty"`
+	BodySize int         `json:"bodySize,omitempty"`
+}
+
+// Response is a recorded response. Bodies downloaded from presigned URLs are
+// only recorded as their size, and replayed empty.
+type Response struct {
+	StatusCode int         `json:"statusCode"`
+	Headers    http.Header `json:"headers,omitempty"`
+	Body       string      `json:"body,omitempty"`
+	BodyBase64 string      `json:"bodyBase64,omitempty"`
+	BodySize   int         `json:"bodySize,omitempty"`
+}
+
+// FromEnvironment wraps the transport with a recorder or a player if
+// SPACECTL_RECORD or SPACECTL_REPLAY is set, and returns it as is otherwise.
+func FromEnvironment(next http.RoundTripper) (http.RoundTripper, error) {
+	recordDir, record := os.LookupEnv(EnvRecord)
+	replayDir, replay := os.LookupEnv(EnvReplay)
+
+	switch {
+	case record && replay:
+		return nil, fmt.Errorf("%s and %s cannot be used together", EnvRecord, EnvReplay)
+	case record:
+		return NewRecorder(recordDir, next)
+	case replay:
+		return NewPlayer(replayDir)
+	default:
+		return next, nil
+	}
+}
+
+func newRequest(r *http.Request, body []byte) Request {
+	request := Request{
+		Method:  r.Method,
+		URL:     redactURL(r.URL.String()),
+		Headers: redactHeaders(r.Header),
+	}
+
+	if utf8.Valid(body) && !presigned(r.URL) {
+		request.Body = redactBody(body)
+	} else {
+		request.BodySize = len(body)
+	}
+
+	return request
+}
+
+func newResponse(req *http.Request, r *http.Response, body []byte) Response {
+	response := Response{
+		StatusCode: r.StatusCode,
+		Headers:    redactHeaders(r.Header),
+	}
+
+	switch {
+	case len(body) > 0 && presigned(req.URL):
+		response.BodySize = len(body)
+	case utf8.Valid(body):
+		response.Body = redactBody(body)
+	default:
+		response.BodyBase64 = base64.StdEncoding.EncodeToString(body)
+	}
+
+	return response
+}
+
+// presigned tells whether the URL is signed, as the ones used to transfer
+// state files, logs and workspaces are.
+func presigned(u *url.URL) bool {
+	for name := range u.Query() {
+		if sensitiveURLParams.MatchString(name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matches tells whether a recorded request matches a live one, ignoring the
+// scheme and host so that cassettes can be replayed against any endpoint.
+func (r Request) matches(other Request) bool {
+	return r.Method == other.Method && pathAndQuery(r.URL) == pathAndQuery(other.URL) && r.Body == other.Body
+}
+
+// sameOperation tells whether a recorded request runs the same GraphQL
+// operation as a live one, whatever its variables. This lets credentials other
+// than the recorded ones be exchanged for a token when replaying.
+func (r Request) sameOperation(other Request) bool {
+	if r.Method != other.Method || pathAndQuery(r.URL) != pathAndQuery(other.URL) {
+		return false
+	}
+
+	query, otherQuery := graphQLQuery(r.Body), graphQLQuery(other.Body)
+
+	return query != "" && query == otherQuery
+}
+
+func graphQLQuery(body string) string {
+	var request struct {
+		Query string `json:"query"`
+	}
+	if err := json.Unmarshal([]byte(body), &request); err != nil {
+		return ""
+	}
+
+	return request.Query
+}
+
+func (r Response) httpResponse(request *http.Request) (*http.Response, error) {
+	body := []byte(r.Body)
+	if r.BodyBase64 != "" {
+		var err error
+		if body, err = base64.StdEncoding.DecodeString(r.BodyBase64); err != nil {
+			return nil, fmt.Errorf("invalid recorded response body: %w", err)
+		}
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.StatusCode, http.StatusText(r.StatusCode)),
+		StatusCode:    r.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        r.Headers.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       request,
+	}, nil
+}
+
+// readBody reads the body and puts it back for the next reader.
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+
+	data, err := io.ReadAll(*body)
+	if closeErr := (*body).Close(); err == nil {
+		err = closeErr
+	}
+	*body = io.NopCloser(bytes.NewReader(data))
+
+	return data, err
+}
+
+func redactHeaders(headers http.Header) http.Header {
+	if len(headers) == 0 {
+		return nil
+	}
+
+	out := headers.Clone()
+	for _, name := range sensitiveHeaders {
+		if out.Get(name) != "" {
+			out.Set(name, Redacted)
+		}
+	}
+
+	return out
+}
+
+// redactBody redacts JSON bodies, which are kept compact and with their keys
+// sorted so that they compare equal. Other text is kept as is.
+func redactBody(body []byte) string {
+	var value any
+	if err := json.Unmarshal(body, &value); err != nil {
+		return string(body)
+	}
+
+	redacted, err := json.Marshal(redactJSON(value))
+	if err != nil {
+		return string(body)
+	}
+
+	return string(redacted)
+}
+
+// redactJSON redacts secrets and tokens, and every value field: environment
+// variables, mounted files and outputs all keep what they hold in one.
+func redactJSON(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for key, item := range v {
+			if _, ok := item.(string); ok && sensitiveJSONKey.MatchString(key) {
+				out[key] = Redacted
+				continue
+			}
+			if item != nil && valueJSONKey.MatchString(key) {
+				out[key] = Redacted
+				continue
+			}
+			out[key] = redactJSON(item)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = redactJSON(item)
+		}
+		return out
+	case string:
+		return redactURL(v)
+	default:
+		return value
+	}
+}
+
+// redactURL redacts the signatures and credentials of presigned URLs. Other
+// strings are returned as is.
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme == "" || u.Host == "" || u.RawQuery == "" {
+		return raw
+	}
+
+	query := u.Query()
+	for name := range query {
+		if sensitiveURLParams.MatchString(name) {
+			query.Set(name, Redacted)
+		}
+	}
+	u.RawQuery = query.Encode()
+
+	return u.String()
+}
+
+func pathAndQuery(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+
+	return u.RequestURI()
+}
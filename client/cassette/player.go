@@ -0,0 +1,98 @@
+package cassette
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// Player is an http.RoundTripper answering requests with the interactions
+// recorded in a directory, without any network access.
+//
+// Requests are matched on their method, path, query and body, ignoring the
+// scheme and host. Interactions with the same request are replayed in the
+// order they were recorded, and the last one keeps being replayed once they
+// have all been used, so polling ends the same way it did when recording.
+// GraphQL requests matching no recorded request fall back to the interactions
+// running the same operation with other variables.
+type Player struct {
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// NewPlayer returns a Player replaying the interactions recorded in dir.
+func NewPlayer(dir string) (*Player, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("could not list the recorded interactions: %w", err)
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no recorded interactions found in %s", dir)
+	}
+	sort.Strings(paths)
+
+	player := &Player{used: make([]bool, len(paths))}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("could not read the recorded interaction: %w", err)
+		}
+
+		var interaction Interaction
+		if err := json.Unmarshal(data, &interaction); err != nil {
+			return nil, fmt.Errorf("could not decode the recorded interaction %s: %w", path, err)
+		}
+		player.interactions = append(player.interactions, interaction)
+	}
+
+	return player, nil
+}
+
+// RoundTrip implements http.RoundTripper.
+func (p *Player) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+
+	body, err := readBody(&req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("could not read the request body: %w", err)
+	}
+	request := newRequest(req, body)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, matches := range []func(Request) bool{request.matches, request.sameOperation} {
+		if interaction, ok := p.next(matches); ok {
+			return interaction.Response.httpResponse(req)
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s %s", errNoInteraction, request.Method, pathAndQuery(request.URL))
+}
+
+// next returns the first unused matching interaction, or the last matching
+// one if they have all been used.
+func (p *Player) next(matches func(Request) bool) (Interaction, bool) {
+	last := -1
+	for i, interaction := range p.interactions {
+		if !matches(interaction.Request) {
+			continue
+		}
+		if !p.used[i] {
+			p.used[i] = true
+			return interaction, true
+		}
+		last = i
+	}
+
+	if last < 0 {
+		return Interaction{}, false
+	}
+
+	return p.interactions[last], true
+}
@@ -0,0 +1,89 @@
+package cassette
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// Recorder is an http.RoundTripper saving every exchange in a directory, one
+// numbered JSON file per interaction. Several recorders, even from separate
+// processes, can record into the same directory.
+type Recorder struct {
+	dir  string
+	next http.RoundTripper
+}
+
+// NewRecorder returns a Recorder saving the exchanges made through next in
+// dir, which is created if needed.
+func NewRecorder(dir string, next http.RoundTripper) (*Recorder, error) {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("could not create the cassette directory: %w", err)
+	}
+
+	return &Recorder{dir: dir, next: next}, nil
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+
+	requestBody, err := readBody(&req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("could not read the request body: %w", err)
+	}
+	interaction := Interaction{Request: newRequest(req, requestBody)}
+
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	responseBody, err := readBody(&resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("could not read the response body: %w", err)
+	}
+	interaction.Response = newResponse(req, resp, responseBody)
+
+	if err := r.save(interaction); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (r *Recorder) save(interaction Interaction) error {
+	data, err := json.MarshalIndent(interaction, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not encode the interaction: %w", err)
+	}
+
+	for i := 1; ; i++ {
+		path := filepath.Join(r.dir, fmt.Sprintf("%05d.json", i))
+
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+		if errors.Is(err, os.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("could not record the interaction: %w", err)
+		}
+
+		_, err = file.Write(append(data, '\n'))
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return fmt.Errorf("could not record the interaction: %w", err)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,159 @@
+package fakeapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+type graphQLError struct {
+	Message string `json:"message"`
+}
+
+type graphQLResponse struct {
+	Data   Object         `json:"data"`
+	Errors []graphQLError `json:"errors,omitempty"`
+}
+
+// anonymousFields can be queried without a bearer token, as they are used to
+// obtain one.
+var anonymousFields = map[string]bool{
+	"apiKeyUser": true,
+	"oauthUser":  true,
+}
+
+func (a *API) serveGraphQL(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Query     string         `json:"query"`
+		Variables map[string]any `json:"variables"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, fmt.Sprintf("could not decode request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	var response graphQLResponse
+	if data, err := a.execute(r, request.Query, request.Variables); err != nil {
+		response.Errors = []graphQLError{{Message: err.Error()}}
+	} else {
+		response.Data = data
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+func (a *API) execute(r *http.Request, query string, variables map[string]any) (Object, error) {
+	op, err := parseOperation(query, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+		for _, sel := range op.selections {
+			if !anonymousFields[sel.name] {
+				return nil, fmt.Errorf("unauthorized")
+			}
+		}
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	e := &executor{api: a, baseURL: baseURL(r)}
+
+	root := Object{"__typename": "Query", "viewer": a.data.Viewer, "debugInfo": a.data.DebugInfo}
+	if op.mutation {
+		root = Object{"__typename": "Mutation"}
+	}
+
+	data := Object{}
+	if err := e.resolve(root, op.selections, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func baseURL(r *http.Request) string {
+	if r.TLS != nil {
+		return "https://" + r.Host
+	}
+
+	return "http://" + r.Host
+}
+
+// executor resolves the selections of an operation against the fixtures.
+type executor struct {
+	api     *API
+	baseURL string
+}
+
+func (e *executor) resolve(parent Object, selections []selection, out Object) error {
+	typeName, _ := parent["__typename"].(string)
+
+	for _, sel := range selections {
+		if sel.fragment {
+			if sel.typeName != "" && sel.typeName != typeName {
+				continue
+			}
+			if err := e.resolve(parent, sel.selections, out); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if sel.name == "__typename" {
+			out[sel.key()] = typeName
+			continue
+		}
+
+		value, ok := parent[sel.name]
+		if resolve, found := resolvers[typeName+"."+sel.name]; found {
+			var err error
+			if value, err = resolve(e, parent, sel.args); err != nil {
+				return err
+			}
+		} else if !ok && (typeName == "Query" || typeName == "Mutation") {
+			return fmt.Errorf("the fake API does not implement %s.%s", typeName, sel.name)
+		}
+
+		projected, err := e.project(value, sel.selections)
+		if err != nil {
+			return err
+		}
+		out[sel.key()] = projected
+	}
+
+	return nil
+}
+
+// project only keeps the selected fields of the value.
+func (e *executor) project(value any, selections []selection) (any, error) {
+	if len(selections) == 0 {
+		return value, nil
+	}
+
+	switch v := value.(type) {
+	case Object:
+		out := Object{}
+		if err := e.resolve(v, selections, out); err != nil {
+			return nil, err
+		}
+		return out, nil
+	case []any:
+		out := make([]any, 0, len(v))
+		for _, item := range v {
+			projected, err := e.project(item, selections)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, projected)
+		}
+		return out, nil
+	default:
+		return value, nil
+	}
+}
@@ -0,0 +1,189 @@
+// Package fakeapi implements an in-memory fake of the Spacelift GraphQL API,
+// serving stacks, runs, policies, modules, spaces, blueprints and worker pools
+// from fixtures. It is meant for
+// tests and demos and only understands the queries and mutations spacectl
+// sends.
+//
+// Fixtures are plain JSON objects: any field they have can be queried, and a
+// few fields taking arguments (stack runs, run logs, module versions, searches)
+// and mutations (triggering, confirming and discarding runs, locking stacks,
+// local previews, state downloads) are implemented on top of them.
+package fakeapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spacelift-io/spacectl/client"
+	"github.com/spacelift-io/spacectl/client/session"
+)
+
+// Token is the bearer token used by the clients returned by Server.Client.
+// The fake API accepts any token.
+const Token = "fake-api-token" // #nosec G101
+
+// Object is a GraphQL object, as a map of field names to values.
+type Object = map[string]any
+
+// Fixtures are the entities served by the fake API.
+//
+// Stacks list their runs newest first under "runs". Runs list their state
+// transitions newest first under "history", and their logs by state under
+// "logs". Modules list their versions under "versions". Stacks managing their
+// state hold it under "stateFile".
+type Fixtures struct {
+	Viewer      Object   `json:"viewer"`
+	DebugInfo   Object   `json:"debugInfo"`
+	Stacks      []Object `json:"stacks"`
+	Policies    []Object `json:"policies"`
+	Modules     []Object `json:"modules"`
+	Spaces      []Object `json:"spaces,omitempty"`
+	Blueprints  []Object `json:"blueprints,omitempty"`
+	WorkerPools []Object `json:"workerPools,omitempty"`
+}
+
+// API is an http.Handler serving the fake Spacelift API on /graphql,
+// presigned uploads on /uploads/ and state downloads on /states/.
+type API struct {
+	mu      sync.Mutex
+	now     func() time.Time
+	data    Fixtures
+	uploads map[string][]byte
+	lastID  int
+}
+
+// New returns an API serving a copy of the fixtures.
+func New(fixtures Fixtures) (*API, error) {
+	data, err := json.Marshal(fixtures)
+	if err != nil {
+		return nil, fmt.Errorf("could not encode fixtures: %w", err)
+	}
+
+	api := &API{now: time.Now, uploads: make(map[string][]byte)}
+	if err := json.Unmarshal(data, &api.data); err != nil {
+		return nil, fmt.Errorf("could not decode fixtures: %w", err)
+	}
+	api.data.normalize()
+
+	return api, nil
+}
+
+// ServeHTTP implements http.Handler.
+func (a *API) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.URL.Path == "/graphql" && r.Method == http.MethodPost:
+		a.serveGraphQL(w, r)
+	case strings.HasPrefix(r.URL.Path, uploadsPath) && r.Method == http.MethodPut:
+		a.serveUpload(w, r)
+	case strings.HasPrefix(r.URL.Path, statesPath) && r.Method == http.MethodGet:
+		a.serveState(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// Upload returns the content uploaded to the presigned URL with the given ID,
+// for example the workspace of a local preview.
+func (a *API) Upload(id string) ([]byte, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	data, ok := a.uploads[id]
+	return data, ok && data != nil
+}
+
+func (a *API) serveUpload(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, uploadsPath)
+
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if _, ok := a.uploads[id]; !ok {
+		http.NotFound(w, r)
+		return
+	}
+	a.uploads[id] = data
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (a *API) serveState(w http.ResponseWriter, r *http.Request) {
+	a.mu.Lock()
+	stack, _ := find(a.data.Stacks, strings.TrimPrefix(r.URL.Path, statesPath)).(Object)
+	var state any
+	if stack != nil {
+		state = stack["stateFile"]
+	}
+	a.mu.Unlock()
+
+	if state == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(state)
+}
+
+// Server is a fake API listening on a local port.
+type Server struct {
+	*API
+
+	// URL is the base URL of the server, to use as the Spacelift endpoint.
+	URL string
+
+	server *httptest.Server
+}
+
+// NewServer starts a fake API serving the fixtures. Callers should Close it
+// when done.
+func NewServer(fixtures Fixtures) (*Server, error) {
+	api, err := New(fixtures)
+	if err != nil {
+		return nil, err
+	}
+
+	server := httptest.NewServer(api)
+
+	return &Server{API: api, URL: server.URL, server: server}, nil
+}
+
+// Client returns a Spacelift client authenticated against the server.
+func (s *Server) Client() client.Client {
+	return client.New(s.server.Client(), &staticSession{endpoint: s.URL + "/graphql", token: Token})
+}
+
+// Close shuts the server down.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+type staticSession struct {
+	endpoint string
+	token    string
+}
+
+func (s *staticSession) BearerToken(context.Context) (string, error) {
+	return s.token, nil
+}
+
+func (s *staticSession) Endpoint() string {
+	return s.endpoint
+}
+
+func (s *staticSession) Type() session.CredentialsType {
+	return session.CredentialsTypeAPIToken
+}
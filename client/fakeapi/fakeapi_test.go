@@ -0,0 +1,247 @@
+package fakeapi
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/shurcooL/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacelift-io/spacectl/client"
+	"github.com/spacelift-io/spacectl/client/session"
+	"github.com/spacelift-io/spacectl/client/structs"
+)
+
+func newTestServer(t *testing.T) *Server {
+	t.Helper()
+
+	server, err := NewServer(DefaultFixtures())
+	require.NoError(t, err)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestServerQueriesRunsAndLogs(t *testing.T) {
+	c := newTestServer(t).Client()
+
+	var query struct {
+		Stack *struct {
+			ID           string `graphql:"id"`
+			VendorConfig struct {
+				Typename  string `graphql:"__typename"`
+				Terraform struct {
+					Version string `graphql:"version"`
+				} `graphql:"... on StackConfigVendorTerraform"`
+				Pulumi struct {
+					LoginURL string `graphql:"loginURL"`
+				} `graphql:"... on StackConfigVendorPulumi"`
+			} `graphql:"vendorConfig"`
+			Runs []struct {
+				ID string `graphql:"id"`
+			} `graphql:"runs(before: $before)"`
+			Run *struct {
+				State   string                       `graphql:"state"`
+				History []structs.RunStateTransition `graphql:"history"`
+				Logs    struct {
+					Exists   bool `graphql:"exists"`
+					Finished bool `graphql:"finished"`
+					Messages []struct {
+						Body string `graphql:"message"`
+					} `graphql:"messages"`
+				} `graphql:"logs(state: $state, token: $token, stateVersion: $stateVersion)"`
+			} `graphql:"run(id: $run)"`
+		} `graphql:"stack(id: $stack)"`
+		Missing *struct {
+			ID string `graphql:"id"`
+		} `graphql:"missing: stack(id: \"nope\")"`
+	}
+
+	require.NoError(t, c.Query(t.Context(), &query, map[string]any{
+		"stack":        graphql.ID("app-servers"),
+		"run":          graphql.ID("01JXAPP0000000000000000001"),
+		"before":       (*graphql.ID)(nil),
+		"state":        structs.RunState("PLANNING"),
+		"token":        (*graphql.String)(nil),
+		"stateVersion": graphql.Int(1),
+	}))
+
+	require.NotNil(t, query.Stack)
+	assert.Nil(t, query.Missing)
+	assert.Equal(t, "app-servers", query.Stack.ID)
+	assert.Equal(t, "StackConfigVendorTerraform", query.Stack.VendorConfig.Typename)
+	assert.Equal(t, "1.5.7", query.Stack.VendorConfig.Terraform.Version)
+	assert.Len(t, query.Stack.Runs, 1)
+
+	run := query.Stack.Run
+	require.NotNil(t, run)
+	assert.Equal(t, "FAILED", run.State)
+	assert.True(t, run.History[0].Terminal)
+	assert.True(t, run.History[1].HasLogs, "hasLogs is derived from the logs")
+	assert.False(t, run.History[2].HasLogs)
+	assert.True(t, run.Logs.Exists)
+	assert.True(t, run.Logs.Finished)
+	assert.Contains(t, run.Logs.Messages[3].Body, "Error: creating EC2 Instance")
+}
+
+func TestServerSearchPagination(t *testing.T) {
+	c := newTestServer(t).Client()
+
+	var query struct {
+		SearchStacks struct {
+			Edges []struct {
+				Node struct {
+					ID string `graphql:"id"`
+				} `graphql:"node"`
+			} `graphql:"edges"`
+			PageInfo structs.PageInfo `graphql:"pageInfo"`
+		} `graphql:"searchStacks(input: $input)"`
+	}
+
+	input := structs.SearchInput{First: new(graphql.Int(1))}
+
+	var ids []string
+	for {
+		require.NoError(t, c.Query(t.Context(), &query, map[string]any{"input": input}))
+		for _, edge := range query.SearchStacks.Edges {
+			ids = append(ids, edge.Node.ID)
+		}
+		if !query.SearchStacks.PageInfo.HasNextPage {
+			break
+		}
+		input.After = new(graphql.String(query.SearchStacks.PageInfo.EndCursor))
+	}
+	assert.Equal(t, []string{"networking", "app-servers"}, ids)
+
+	input = structs.SearchInput{Predicates: &[]structs.QueryPredicate{{
+		Field:      "labels",
+		Constraint: structs.QueryFieldConstraint{StringMatches: &[]graphql.String{"team:app"}},
+	}}}
+	require.NoError(t, c.Query(t.Context(), &query, map[string]any{"input": input}))
+	require.Len(t, query.SearchStacks.Edges, 1)
+	assert.Equal(t, "app-servers", query.SearchStacks.Edges[0].Node.ID)
+}
+
+func TestServerRunLifecycle(t *testing.T) {
+	c := newTestServer(t).Client()
+
+	var trigger struct {
+		RunTrigger struct {
+			ID    string `graphql:"id"`
+			State string `graphql:"state"`
+		} `graphql:"runTrigger(stack: $stack, commitSha: $sha, runType: $type)"`
+	}
+	require.NoError(t, c.Mutate(t.Context(), &trigger, map[string]any{
+		"stack": graphql.ID("networking"),
+		"sha":   (*graphql.String)(nil),
+		"type":  structs.NewRunType("TRACKED"),
+	}))
+	assert.Equal(t, "UNCONFIRMED", trigger.RunTrigger.State)
+
+	var confirm struct {
+		RunConfirm struct {
+			State   string                       `graphql:"state"`
+			History []structs.RunStateTransition `graphql:"history"`
+		} `graphql:"runConfirm(stack: $stack, run: $run)"`
+	}
+	variables := map[string]any{"stack": graphql.ID("networking"), "run": graphql.ID(trigger.RunTrigger.ID)}
+	require.NoError(t, c.Mutate(t.Context(), &confirm, variables))
+	assert.Equal(t, "FINISHED", confirm.RunConfirm.State)
+	assert.True(t, confirm.RunConfirm.History[0].Terminal)
+
+	err := c.Mutate(t.Context(), &confirm, variables)
+	require.ErrorContains(t, err, "is not awaiting confirmation")
+}
+
+func TestServerLocalPreviewUpload(t *testing.T) {
+	server := newTestServer(t)
+	c := server.Client()
+
+	var upload struct {
+		UploadLocalWorkspace struct {
+			ID            string            `graphql:"id"`
+			UploadURL     string            `graphql:"uploadUrl"`
+			UploadHeaders structs.StringMap `graphql:"uploadHeaders"`
+		} `graphql:"uploadLocalWorkspace(stack: $stack)"`
+	}
+	require.NoError(t, c.Mutate(t.Context(), &upload, map[string]any{"stack": graphql.ID("networking")}))
+	assert.Equal(t, server.URL+"/uploads/"+upload.UploadLocalWorkspace.ID, upload.UploadLocalWorkspace.UploadURL)
+
+	var propose struct {
+		RunProposeLocalWorkspace struct {
+			ID string `graphql:"id"`
+		} `graphql:"runProposeLocalWorkspace(stack: $stack, workspace: $workspace)"`
+	}
+	variables := map[string]any{"stack": graphql.ID("networking"), "workspace": graphql.ID(upload.UploadLocalWorkspace.ID)}
+	require.ErrorContains(t, c.Mutate(t.Context(), &propose, variables), "has not been uploaded")
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodPut, upload.UploadLocalWorkspace.UploadURL, strings.NewReader("archive"))
+	require.NoError(t, err)
+	req.Header = upload.UploadLocalWorkspace.UploadHeaders.HTTPHeaders()
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	require.NoError(t, c.Mutate(t.Context(), &propose, variables))
+	assert.NotEmpty(t, propose.RunProposeLocalWorkspace.ID)
+
+	data, ok := server.Upload(upload.UploadLocalWorkspace.ID)
+	assert.True(t, ok)
+	assert.Equal(t, "archive", string(data))
+}
+
+func TestServerAuthentication(t *testing.T) {
+	server := newTestServer(t)
+
+	resp, err := http.Post(server.URL+"/graphql", "application/json", bytes.NewBufferString(`{"query": "{viewer{id}}"}`))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var buf bytes.Buffer
+	_, err = buf.ReadFrom(resp.Body)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"data": null, "errors": [{"message": "unauthorized"}]}`, buf.String())
+
+	sess, err := session.FromAPIKey(t.Context(), http.DefaultClient)(server.URL, "key-id", "key-secret")
+	require.NoError(t, err)
+
+	var query struct {
+		Viewer struct {
+			ID string `graphql:"id"`
+		} `graphql:"viewer"`
+	}
+	require.NoError(t, client.New(http.DefaultClient, sess).Query(t.Context(), &query, nil))
+	assert.Equal(t, "fake-user", query.Viewer.ID)
+}
+
+func TestParseOperation(t *testing.T) {
+	op, err := parseOperation(`
+		# A comment
+		query Stacks($id: ID!) {
+			first: stack(id: $id) { id, labels }
+			stacks(input: {first: 10, after: null, flags: [true, false], text: "a \"quoted\" value", order: ASC, ratio: -1.5})
+		}`, map[string]any{"id": "my-stack"})
+	require.NoError(t, err)
+
+	assert.False(t, op.mutation)
+	require.Len(t, op.selections, 2)
+	assert.Equal(t, "first", op.selections[0].key())
+	assert.Equal(t, "stack", op.selections[0].name)
+	assert.Equal(t, map[string]any{"id": "my-stack"}, op.selections[0].args)
+	assert.Len(t, op.selections[0].selections, 2)
+	assert.Equal(t, map[string]any{"input": Object{
+		"first": 10.0,
+		"after": nil,
+		"flags": []any{true, false},
+		"text":  `a "quoted" value`,
+		"order": "ASC",
+		"ratio": -1.5,
+	}}, op.selections[1].args)
+
+	_, err = parseOperation(`{ stack { ...StackFields } }`, nil)
+	assert.ErrorContains(t, err, "named fragments are not supported")
+}
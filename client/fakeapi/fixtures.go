@@ -0,0 +1,39 @@
+package fakeapi
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+//go:embed fixtures.json
+var defaultFixtures []byte
+
+// DefaultFixtures returns a small account with a couple of stacks, one of
+// them with a failed run, a plan policy and a module.
+func DefaultFixtures() Fixtures {
+	var fixtures Fixtures
+	if err := json.Unmarshal(defaultFixtures, &fixtures); err != nil {
+		panic(err) // The embedded fixtures are tested.
+	}
+
+	return fixtures
+}
+
+// LoadFixtures reads fixtures from a JSON file.
+func LoadFixtures(path string) (Fixtures, error) {
+	var fixtures Fixtures
+
+	// #nosec G304
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fixtures, fmt.Errorf("could not read fixtures: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &fixtures); err != nil {
+		return fixtures, fmt.Errorf("could not parse fixtures from %s: %w", path, err)
+	}
+
+	return fixtures, nil
+}
@@ -0,0 +1,379 @@
+package fakeapi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// operation is a parsed GraphQL operation, with its variables already
+// substituted into the arguments.
+type operation struct {
+	mutation   bool
+	selections []selection
+}
+
+// selection is either a field, or an inline fragment applying its selections
+// to objects of the given type.
+type selection struct {
+	alias      string
+	name       string
+	args       map[string]any
+	typeName   string
+	fragment   bool
+	selections []selection
+}
+
+func (s selection) key() string {
+	if s.alias != "" {
+		return s.alias
+	}
+
+	return s.name
+}
+
+// parser reads the subset of GraphQL spacectl sends: a single operation made
+// of fields, aliases, arguments and inline fragments. Named fragments and
+// directives are not supported.
+type parser struct {
+	src       string
+	pos       int
+	variables map[string]any
+}
+
+func parseOperation(src string, variables map[string]any) (*operation, error) {
+	p := &parser{src: src, variables: variables}
+	op := &operation{}
+
+	p.skipIgnored()
+	if name := p.peekName(); name != "" {
+		switch name {
+		case "query":
+		case "mutation":
+			op.mutation = true
+		default:
+			return nil, fmt.Errorf("unsupported operation type %q", name)
+		}
+		p.readName()
+		p.skipIgnored()
+
+		// The operation name is optional.
+		if p.peekName() != "" {
+			p.readName()
+			p.skipIgnored()
+		}
+
+		// Variable definitions carry types we have no use for.
+		if p.peek() == '(' {
+			if err := p.skipBalanced('(', ')'); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	selections, err := p.selectionSet()
+	if err != nil {
+		return nil, err
+	}
+	op.selections = selections
+
+	if p.skipIgnored(); p.pos < len(p.src) {
+		return nil, p.errorf("only a single operation is supported")
+	}
+
+	return op, nil
+}
+
+func (p *parser) selectionSet() ([]selection, error) {
+	if err := p.expect('{'); err != nil {
+		return nil, err
+	}
+
+	var selections []selection
+	for {
+		p.skipIgnored()
+		if p.peek() == '}' {
+			p.pos++
+			return selections, nil
+		}
+		if p.pos >= len(p.src) {
+			return nil, p.errorf("unterminated selection set")
+		}
+
+		sel, err := p.selection()
+		if err != nil {
+			return nil, err
+		}
+		selections = append(selections, sel)
+	}
+}
+
+func (p *parser) selection() (selection, error) {
+	if strings.HasPrefix(p.src[p.pos:], "...") {
+		p.pos += 3
+		p.skipIgnored()
+
+		sel := selection{fragment: true}
+		if p.peekName() == "on" {
+			p.readName()
+			p.skipIgnored()
+			if sel.typeName = p.readName(); sel.typeName == "" {
+				return sel, p.errorf("expected a type condition")
+			}
+			p.skipIgnored()
+		} else if p.peek() != '{' {
+			return sel, p.errorf("named fragments are not supported")
+		}
+
+		var err error
+		sel.selections, err = p.selectionSet()
+		return sel, err
+	}
+
+	var sel selection
+	if sel.name = p.readName(); sel.name == "" {
+		return sel, p.errorf("expected a field name")
+	}
+
+	p.skipIgnored()
+	if p.peek() == ':' {
+		p.pos++
+		p.skipIgnored()
+		sel.alias = sel.name
+		if sel.name = p.readName(); sel.name == "" {
+			return sel, p.errorf("expected a field name after alias %q", sel.alias)
+		}
+		p.skipIgnored()
+	}
+
+	if p.peek() == '(' {
+		args, err := p.arguments()
+		if err != nil {
+			return sel, err
+		}
+		sel.args = args
+		p.skipIgnored()
+	}
+
+	if p.peek() == '@' {
+		return sel, p.errorf("directives are not supported")
+	}
+
+	if p.peek() == '{' {
+		var err error
+		if sel.selections, err = p.selectionSet(); err != nil {
+			return sel, err
+		}
+	}
+
+	return sel, nil
+}
+
+func (p *parser) arguments() (map[string]any, error) {
+	p.pos++ // (
+
+	args := make(map[string]any)
+	for {
+		p.skipIgnored()
+		if p.peek() == ')' {
+			p.pos++
+			return args, nil
+		}
+
+		name := p.readName()
+		if name == "" {
+			return nil, p.errorf("expected an argument name")
+		}
+
+		p.skipIgnored()
+		if err := p.expect(':'); err != nil {
+			return nil, err
+		}
+
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		args[name] = value
+	}
+}
+
+func (p *parser) value() (any, error) {
+	p.skipIgnored()
+
+	switch c := p.peek(); {
+	case c == '$':
+		p.pos++
+		return p.variables[p.readName()], nil
+	case c == '"':
+		return p.stringValue()
+	case c == '[':
+		p.pos++
+		list := []any{}
+		for {
+			p.skipIgnored()
+			if p.peek() == ']' {
+				p.pos++
+				return list, nil
+			}
+			if p.pos >= len(p.src) {
+				return nil, p.errorf("unterminated list")
+			}
+
+			item, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, item)
+		}
+	case c == '{':
+		p.pos++
+		object := Object{}
+		for {
+			p.skipIgnored()
+			if p.peek() == '}' {
+				p.pos++
+				return object, nil
+			}
+
+			name := p.readName()
+			if name == "" {
+				return nil, p.errorf("expected an object field name")
+			}
+
+			p.skipIgnored()
+			if err := p.expect(':'); err != nil {
+				return nil, err
+			}
+
+			item, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			object[name] = item
+		}
+	case c == '-' || (c >= '0' && c <= '9'):
+		start := p.pos
+		p.pos++
+		for p.pos < len(p.src) && strings.IndexByte("0123456789.eE+-", p.src[p.pos]) >= 0 {
+			p.pos++
+		}
+		return strconv.ParseFloat(p.src[start:p.pos], 64)
+	default:
+		switch name := p.readName(); name {
+		case "":
+			return nil, p.errorf("expected a value")
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		default:
+			// Enum values are served as strings.
+			return name, nil
+		}
+	}
+}
+
+func (p *parser) stringValue() (string, error) {
+	if strings.HasPrefix(p.src[p.pos:], `"""`) {
+		return "", p.errorf("block strings are not supported")
+	}
+
+	start := p.pos
+	p.pos++
+	for p.pos < len(p.src) {
+		switch p.src[p.pos] {
+		case '\\':
+			p.pos += 2
+		case '"':
+			p.pos++
+			return strconv.Unquote(p.src[start:p.pos])
+		default:
+			p.pos++
+		}
+	}
+
+	return "", p.errorf("unterminated string")
+}
+
+func (p *parser) skipBalanced(open, closing byte) error {
+	depth := 0
+	for ; p.pos < len(p.src); p.pos++ {
+		switch p.src[p.pos] {
+		case open:
+			depth++
+		case closing:
+			depth--
+			if depth == 0 {
+				p.pos++
+				p.skipIgnored()
+				return nil
+			}
+		}
+	}
+
+	return p.errorf("unbalanced %q", open)
+}
+
+// skipIgnored skips whitespace, commas and comments, which are insignificant
+// in GraphQL.
+func (p *parser) skipIgnored() {
+	for p.pos < len(p.src) {
+		switch p.src[p.pos] {
+		case ' ', '\t', '\n', '\r', ',':
+			p.pos++
+		case '#':
+			for p.pos < len(p.src) && p.src[p.pos] != '\n' {
+				p.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (p *parser) peek() byte {
+	if p.pos >= len(p.src) {
+		return 0
+	}
+
+	return p.src[p.pos]
+}
+
+func (p *parser) peekName() string {
+	pos := p.pos
+	name := p.readName()
+	p.pos = pos
+
+	return name
+}
+
+func (p *parser) readName() string {
+	start := p.pos
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		if c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (p.pos > start && c >= '0' && c <= '9') {
+			p.pos++
+			continue
+		}
+		break
+	}
+
+	return p.src[start:p.pos]
+}
+
+func (p *parser) expect(c byte) error {
+	p.skipIgnored()
+	if p.peek() != c {
+		return p.errorf("expected %q", c)
+	}
+	p.pos++
+
+	return nil
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("syntax error at offset %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
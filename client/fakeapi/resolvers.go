@@ -0,0 +1,555 @@
+package fakeapi
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	uploadsPath  = "/uploads/"
+	statesPath   = "/states/"
+	runsPageSize = 50
+)
+
+// jwtKey signs the tokens returned for API keys. Nothing verifies them.
+var jwtKey = []byte("fakeapi")
+
+// resolver computes a field taking arguments, or a mutation, from its parent.
+type resolver func(e *executor, parent Object, args map[string]any) (any, error)
+
+var resolvers = map[string]resolver{
+	"Query.stacks": func(e *executor, _ Object, _ map[string]any) (any, error) { return list(e.api.data.Stacks), nil },
+	"Query.stack": func(e *executor, _ Object, args map[string]any) (any, error) {
+		return find(e.api.data.Stacks, args["id"]), nil
+	},
+	"Query.searchStacks": func(e *executor, _ Object, args map[string]any) (any, error) {
+		return search(e.api.data.Stacks, args["input"]), nil
+	},
+	"Query.policies": func(e *executor, _ Object, _ map[string]any) (any, error) { return list(e.api.data.Policies), nil },
+	"Query.policy": func(e *executor, _ Object, args map[string]any) (any, error) {
+		return find(e.api.data.Policies, args["id"]), nil
+	},
+	"Query.searchPolicies": func(e *executor, _ Object, args map[string]any) (any, error) {
+		return search(e.api.data.Policies, args["input"]), nil
+	},
+	"Query.modules": func(e *executor, _ Object, _ map[string]any) (any, error) { return list(e.api.data.Modules), nil },
+	"Query.module": func(e *executor, _ Object, args map[string]any) (any, error) {
+		return find(e.api.data.Modules, args["id"]), nil
+	},
+	"Query.searchModules": func(e *executor, _ Object, args map[string]any) (any, error) {
+		return search(e.api.data.Modules, args["input"]), nil
+	},
+	"Query.spaces": func(e *executor, _ Object, _ map[string]any) (any, error) { return list(e.api.data.Spaces), nil },
+	"Query.space": func(e *executor, _ Object, args map[string]any) (any, error) {
+		return find(e.api.data.Spaces, args["id"]), nil
+	},
+	"Query.blueprint": func(e *executor, _ Object, args map[string]any) (any, error) {
+		return find(e.api.data.Blueprints, args["id"]), nil
+	},
+	"Query.searchBlueprints": func(e *executor, _ Object, args map[string]any) (any, error) {
+		return search(e.api.data.Blueprints, args["input"]), nil
+	},
+	"Query.workerPool": func(e *executor, _ Object, args map[string]any) (any, error) {
+		return find(e.api.data.WorkerPools, args["id"]), nil
+	},
+	"Query.searchWorkerPools": func(e *executor, _ Object, args map[string]any) (any, error) {
+		return search(e.api.data.WorkerPools, args["input"]), nil
+	},
+
+	"Stack.runs": func(_ *executor, stack Object, args map[string]any) (any, error) {
+		return stackRuns(stack, false, args["before"]), nil
+	},
+	"Stack.proposedRuns": func(_ *executor, stack Object, args map[string]any) (any, error) {
+		return stackRuns(stack, true, args["before"]), nil
+	},
+	"Stack.run": func(_ *executor, stack Object, args map[string]any) (any, error) {
+		return find(objects(stack["runs"]), args["id"]), nil
+	},
+
+	"Run.logs": runLogs,
+
+	"Module.versions": moduleVersions,
+	"Module.version": func(_ *executor, module Object, args map[string]any) (any, error) {
+		return find(objects(module["versions"]), args["id"]), nil
+	},
+	"Module.searchModuleVersions": func(_ *executor, module Object, args map[string]any) (any, error) {
+		return search(objects(module["versions"]), args["input"]), nil
+	},
+
+	"Mutation.apiKeyUser":               (*executor).user,
+	"Mutation.oauthUser":                (*executor).user,
+	"Mutation.runTrigger":               (*executor).runTrigger,
+	"Mutation.runConfirm":               (*executor).runConfirm,
+	"Mutation.runDiscard":               (*executor).runDiscard,
+	"Mutation.stackLock":                (*executor).stackLock,
+	"Mutation.stackUnlock":              (*executor).stackUnlock,
+	"Mutation.uploadLocalWorkspace":     (*executor).uploadLocalWorkspace,
+	"Mutation.stateDownloadUrl":         (*executor).stateDownloadURL,
+	"Mutation.runProposeLocalWorkspace": (*executor).runProposeLocalWorkspace,
+}
+
+// normalize sets the type names the resolvers rely on, and the hasLogs flag
+// of transitions that do not say whether they have logs.
+func (f *Fixtures) normalize() {
+	if f.Viewer == nil {
+		f.Viewer = Object{"id": "fake-user", "name": "Fake User"}
+	}
+	if f.DebugInfo == nil {
+		f.DebugInfo = Object{"selfHostedVersion": ""}
+	}
+	setTypeName(f.Viewer, "User")
+
+	for _, stack := range f.Stacks {
+		setTypeName(stack, "Stack")
+		for _, run := range objects(stack["runs"]) {
+			setTypeName(run, "Run")
+			logs, _ := run["logs"].(Object)
+			for _, transition := range objects(run["history"]) {
+				if _, ok := transition["hasLogs"]; !ok {
+					_, transition["hasLogs"] = logs[fmt.Sprint(transition["state"])]
+				}
+			}
+		}
+	}
+
+	for _, policy := range f.Policies {
+		setTypeName(policy, "Policy")
+	}
+
+	for _, module := range f.Modules {
+		setTypeName(module, "Module")
+		for _, version := range objects(module["versions"]) {
+			setTypeName(version, "Version")
+		}
+	}
+
+	for _, space := range f.Spaces {
+		setTypeName(space, "Space")
+	}
+
+	for _, blueprint := range f.Blueprints {
+		setTypeName(blueprint, "Blueprint")
+	}
+
+	for _, pool := range f.WorkerPools {
+		setTypeName(pool, "WorkerPool")
+	}
+}
+
+func setTypeName(o Object, typeName string) {
+	if _, ok := o["__typename"]; !ok {
+		o["__typename"] = typeName
+	}
+}
+
+func list(items []Object) []any {
+	out := make([]any, 0, len(items))
+	for _, item := range items {
+		out = append(out, item)
+	}
+
+	return out
+}
+
+func objects(value any) []Object {
+	items, _ := value.([]any)
+
+	out := make([]Object, 0, len(items))
+	for _, item := range items {
+		if o, ok := item.(Object); ok {
+			out = append(out, o)
+		}
+	}
+
+	return out
+}
+
+// find returns the object with the given ID, or nil.
+func find(items []Object, id any) any {
+	for _, item := range items {
+		if item["id"] == id {
+			return item
+		}
+	}
+
+	return nil
+}
+
+// search implements the SearchInput based queries: full text search,
+// predicates and cursor pagination. Cursors are the IDs of the nodes.
+func search(items []Object, input any) Object {
+	in, _ := input.(Object)
+
+	var matching []Object
+	for _, item := range items {
+		if matchesSearch(item, in) {
+			matching = append(matching, item)
+		}
+	}
+
+	start := 0
+	if after, _ := in["after"].(string); after != "" {
+		start = len(matching)
+		for i, item := range matching {
+			if item["id"] == after {
+				start = i + 1
+				break
+			}
+		}
+	}
+
+	end := len(matching)
+	if first, ok := in["first"].(float64); ok {
+		end = min(start+int(first), end)
+	}
+
+	edges := []any{}
+	endCursor := ""
+	for _, item := range matching[start:end] {
+		endCursor = fmt.Sprint(item["id"])
+		edges = append(edges, Object{"cursor": endCursor, "node": item})
+	}
+
+	return Object{
+		"edges": edges,
+		"pageInfo": Object{
+			"endCursor":       endCursor,
+			"hasNextPage":     end < len(matching),
+			"hasPreviousPage": start > 0,
+		},
+	}
+}
+
+func matchesSearch(item Object, in Object) bool {
+	if text, _ := in["fullTextSearch"].(string); text != "" {
+		text = strings.ToLower(text)
+		found := false
+		for _, field := range []string{"id", "name", "description"} {
+			if value, ok := item[field].(string); ok && strings.Contains(strings.ToLower(value), text) {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	predicates, _ := in["predicates"].([]any)
+	for _, p := range predicates {
+		predicate, _ := p.(Object)
+		field, _ := predicate["field"].(string)
+		constraint, _ := predicate["constraint"].(Object)
+		exclude, _ := predicate["exclude"].(bool)
+
+		if matchesConstraint(item[field], constraint) == exclude {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchesConstraint compares values as strings. String constraints match
+// substrings, case-insensitively. List fields match if any element does.
+func matchesConstraint(value any, constraint Object) bool {
+	values, ok := value.([]any)
+	if !ok {
+		values = []any{value}
+	}
+
+	for name, candidates := range constraint {
+		candidates, ok := candidates.([]any)
+		if !ok {
+			continue
+		}
+
+		for _, candidate := range candidates {
+			for _, v := range values {
+				want, got := fmt.Sprint(candidate), fmt.Sprint(v)
+				if name == "stringMatches" && strings.Contains(strings.ToLower(got), strings.ToLower(want)) {
+					return true
+				}
+				if got == want {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// stackRuns returns the tracked or proposed runs of a stack older than the
+// given one, newest first.
+func stackRuns(stack Object, proposed bool, before any) []any {
+	out := []any{}
+	reached := before == nil || before == ""
+
+	for _, run := range objects(stack["runs"]) {
+		if !reached {
+			reached = run["id"] == before
+			continue
+		}
+
+		if (run["type"] == "PROPOSED") == proposed && len(out) < runsPageSize {
+			out = append(out, run)
+		}
+	}
+
+	return out
+}
+
+// runLogs serves the logs of a state in a single page, one message per line.
+func runLogs(_ *executor, run Object, args map[string]any) (any, error) {
+	logs, _ := run["logs"].(Object)
+	text, exists := logs[fmt.Sprint(args["state"])].(string)
+
+	messages := []any{}
+	for line := range strings.SplitAfterSeq(text, "\n") {
+		if line != "" {
+			messages = append(messages, Object{"message": line})
+		}
+	}
+
+	return Object{
+		"exists":    exists,
+		"finished":  true,
+		"hasMore":   false,
+		"messages":  messages,
+		"nextToken": nil,
+	}, nil
+}
+
+func moduleVersions(_ *executor, module Object, args map[string]any) (any, error) {
+	includeFailed, _ := args["includeFailed"].(bool)
+
+	out := []any{}
+	for _, version := range objects(module["versions"]) {
+		if includeFailed || version["state"] != "FAILED" {
+			out = append(out, version)
+		}
+	}
+
+	return out, nil
+}
+
+func (e *executor) user(_ Object, args map[string]any) (any, error) {
+	expiresAt := e.api.now().Add(time.Hour)
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Audience:  jwt.ClaimStrings{e.baseURL},
+		Subject:   fmt.Sprint(e.api.data.Viewer["id"]),
+		IssuedAt:  jwt.NewNumericDate(e.api.now()),
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+	}).SignedString(jwtKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return Object{"id": e.api.data.Viewer["id"], "jwt": token, "validUntil": expiresAt.Unix()}, nil
+}
+
+func (e *executor) stack(id any) (Object, error) {
+	stack, _ := find(e.api.data.Stacks, id).(Object)
+	if stack == nil {
+		return nil, fmt.Errorf("stack %v not found", id)
+	}
+
+	return stack, nil
+}
+
+func (e *executor) run(stackID, runID any) (Object, Object, error) {
+	stack, err := e.stack(stackID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	run, _ := find(objects(stack["runs"]), runID).(Object)
+	if run == nil {
+		return nil, nil, fmt.Errorf("run %v not found", runID)
+	}
+
+	return stack, run, nil
+}
+
+// newRun creates a run on the stack. Proposed runs finish immediately and
+// tracked ones wait for confirmation.
+func (e *executor) newRun(stack Object, runType string, commit any) Object {
+	e.api.lastID++
+
+	if commit == nil || commit == "" {
+		trackedCommit, _ := stack["trackedCommit"].(Object)
+		commit = trackedCommit["hash"]
+	}
+
+	run := Object{
+		"__typename":  "Run",
+		"id":          fmt.Sprintf("fake-run-%d", e.api.lastID),
+		"type":        runType,
+		"branch":      stack["branch"],
+		"commit":      Object{"hash": commit},
+		"createdAt":   e.api.now().Unix(),
+		"triggeredBy": e.api.data.Viewer["id"],
+		"delta":       Object{"addCount": 0, "changeCount": 0, "deleteCount": 0, "resources": 0},
+		"history":     []any{},
+		"logs":        Object{},
+	}
+
+	for _, state := range []string{"QUEUED", "PREPARING", "INITIALIZING"} {
+		e.transition(run, state, "", false)
+	}
+	e.transition(run, "PLANNING", "Planning finished.\n", false)
+
+	if runType == "PROPOSED" {
+		e.transition(run, "FINISHED", "", true)
+	} else {
+		e.transition(run, "UNCONFIRMED", "", false)
+	}
+
+	runs, _ := stack["runs"].([]any)
+	stack["runs"] = append([]any{run}, runs...)
+
+	return run
+}
+
+// transition moves the run to a new state, newest first in its history.
+func (e *executor) transition(run Object, state, logs string, terminal bool) {
+	if logs != "" {
+		run["logs"].(Object)[state] = logs
+	}
+
+	run["state"] = state
+	run["history"] = append([]any{Object{
+		"state":        state,
+		"hasLogs":      logs != "",
+		"terminal":     terminal,
+		"timestamp":    e.api.now().Unix(),
+		"stateVersion": 1,
+		"note":         nil,
+		"username":     e.api.data.Viewer["id"],
+	}}, run["history"].([]any)...)
+}
+
+func (e *executor) runTrigger(_ Object, args map[string]any) (any, error) {
+	stack, err := e.stack(args["stack"])
+	if err != nil {
+		return nil, err
+	}
+
+	runType, _ := args["runType"].(string)
+	if runType == "" {
+		runType = "TRACKED"
+	}
+
+	return e.newRun(stack, runType, args["commitSha"]), nil
+}
+
+func (e *executor) runConfirm(_ Object, args map[string]any) (any, error) {
+	_, run, err := e.run(args["stack"], args["run"])
+	if err != nil {
+		return nil, err
+	}
+
+	if run["state"] != "UNCONFIRMED" {
+		return nil, fmt.Errorf("run %v is not awaiting confirmation", args["run"])
+	}
+
+	e.transition(run, "CONFIRMED", "", false)
+	e.transition(run, "APPLYING", "Applying finished.\n", false)
+	e.transition(run, "FINISHED", "", true)
+
+	return run, nil
+}
+
+func (e *executor) runDiscard(_ Object, args map[string]any) (any, error) {
+	_, run, err := e.run(args["stack"], args["run"])
+	if err != nil {
+		return nil, err
+	}
+
+	if !slices.Contains([]any{"QUEUED", "UNCONFIRMED"}, run["state"]) {
+		return nil, fmt.Errorf("run %v cannot be discarded in state %v", args["run"], run["state"])
+	}
+
+	e.transition(run, "DISCARDED", "", true)
+
+	return run, nil
+}
+
+func (e *executor) stackLock(_ Object, args map[string]any) (any, error) {
+	stack, err := e.stack(args["id"])
+	if err != nil {
+		return nil, err
+	}
+
+	if lockedBy := stack["lockedBy"]; lockedBy != nil && lockedBy != "" && lockedBy != e.api.data.Viewer["id"] {
+		return nil, fmt.Errorf("stack %v is already locked by %v", args["id"], lockedBy)
+	}
+
+	stack["lockedBy"] = e.api.data.Viewer["id"]
+	stack["lockNote"] = args["note"]
+
+	return stack, nil
+}
+
+func (e *executor) stackUnlock(_ Object, args map[string]any) (any, error) {
+	stack, err := e.stack(args["id"])
+	if err != nil {
+		return nil, err
+	}
+
+	stack["lockedBy"] = nil
+	stack["lockNote"] = nil
+
+	return stack, nil
+}
+
+func (e *executor) stateDownloadURL(_ Object, args map[string]any) (any, error) {
+	input, _ := args["input"].(Object)
+
+	stack, err := e.stack(input["stackId"])
+	if err != nil {
+		return nil, err
+	}
+
+	return Object{"url": e.baseURL + statesPath + fmt.Sprint(stack["id"])}, nil
+}
+
+func (e *executor) uploadLocalWorkspace(_ Object, args map[string]any) (any, error) {
+	if _, err := e.stack(args["stack"]); err != nil {
+		return nil, err
+	}
+
+	e.api.lastID++
+	id := fmt.Sprintf("fake-workspace-%d", e.api.lastID)
+	e.api.uploads[id] = nil
+
+	return Object{
+		"id":        id,
+		"uploadUrl": e.baseURL + uploadsPath + id,
+		"uploadHeaders": Object{"entries": []any{
+			Object{"key": "Content-Type", "value": "application/x-gzip"},
+		}},
+	}, nil
+}
+
+func (e *executor) runProposeLocalWorkspace(_ Object, args map[string]any) (any, error) {
+	stack, err := e.stack(args["stack"])
+	if err != nil {
+		return nil, err
+	}
+
+	if e.api.uploads[fmt.Sprint(args["workspace"])] == nil {
+		return nil, fmt.Errorf("workspace %v has not been uploaded", args["workspace"])
+	}
+
+	run := e.newRun(stack, "PROPOSED", nil)
+	run["workspace"] = args["workspace"]
+	run["environmentVarsOverrides"] = args["environmentVarsOverrides"]
+
+	return run, nil
+}
@@ -12,3 +12,10 @@ var httpClient = &http.Client{
 func GetHTTPClient() *http.Client {
 	return httpClient
 }
+
+// GetTransferHTTPClient returns a client for uploading and downloading files
+// using pre-signed URLs. It shares the transport of the API client so that
+// proxy and TLS settings apply, but has no timeout as transfers can be large.
+func GetTransferHTTPClient() *http.Client {
+	return &http.Client{Transport: httpClient.Transport}
+}
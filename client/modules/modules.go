@@ -0,0 +1,83 @@
+// Package modules provides typed access to the modules of the Spacelift
+// private registry.
+package modules
+
+import (
+	"context"
+	"fmt"
+	"iter"
+
+	"github.com/spacelift-io/spacectl/client"
+	"github.com/spacelift-io/spacectl/client/structs"
+)
+
+// Version is a version of a module.
+type Version struct {
+	ID     string `json:"id" graphql:"id"`
+	Commit struct {
+		AuthorLogin string `json:"authorLogin" graphql:"authorLogin"`
+		AuthorName  string `json:"authorName" graphql:"authorName"`
+		Hash        string `json:"hash" graphql:"hash"`
+		Message     string `json:"message" graphql:"message"`
+		Timestamp   int    `json:"timestamp" graphql:"timestamp"`
+		URL         string `json:"url" graphql:"url"`
+	} `json:"commit" graphql:"commit"`
+	DownloadLink any    `json:"downloadLink" graphql:"downloadLink"`
+	Number       string `json:"number" graphql:"number"`
+	SourceURL    string `json:"sourceURL" graphql:"sourceURL"`
+	State        string `json:"state" graphql:"state"`
+	VersionCount int    `json:"versionCount" graphql:"versionCount"`
+	Yanked       bool   `json:"yanked" graphql:"yanked"`
+}
+
+// VersionsPage is a page of module version search results.
+type VersionsPage struct {
+	Versions []Version
+	PageInfo structs.PageInfo
+}
+
+// Service gives access to the modules of an account.
+type Service struct {
+	client client.Client
+}
+
+// New returns a Service using the client.
+func New(c client.Client) *Service {
+	return &Service{client: c}
+}
+
+// SearchVersions returns a single page of the versions of a module matching
+// the input.
+func (s *Service) SearchVersions(ctx context.Context, moduleID string, input structs.SearchInput) (VersionsPage, error) {
+	var query struct {
+		Module struct {
+			SearchModuleVersions struct {
+				PageInfo structs.PageInfo `graphql:"pageInfo"`
+				Edges    []struct {
+					Node Version `graphql:"node"`
+				} `graphql:"edges"`
+			} `graphql:"searchModuleVersions(input: $input)"`
+		} `graphql:"module(id: $id)"`
+	}
+
+	if err := s.client.Query(ctx, &query, map[string]any{"id": moduleID, "input": input}); err != nil {
+		return VersionsPage{}, fmt.Errorf("failed to query list of modules: %w", err)
+	}
+
+	result := query.Module.SearchModuleVersions
+	page := VersionsPage{Versions: make([]Version, 0, len(result.Edges)), PageInfo: result.PageInfo}
+	for _, edge := range result.Edges {
+		page.Versions = append(page.Versions, edge.Node)
+	}
+
+	return page, nil
+}
+
+// Versions iterates over the versions of a module matching the input,
+// fetching pages as needed. The iteration stops at the first error.
+func (s *Service) Versions(ctx context.Context, moduleID string, input structs.SearchInput, opts client.PaginateOptions) iter.Seq2[Version, error] {
+	return client.Paginate(ctx, input, opts, func(ctx context.Context, input structs.SearchInput) (client.Page[Version], error) {
+		page, err := s.SearchVersions(ctx, moduleID, input)
+		return client.Page[Version]{Items: page.Versions, PageInfo: page.PageInfo}, err
+	})
+}
@@ -0,0 +1,36 @@
+package modules
+
+import (
+	"testing"
+
+	"github.com/shurcooL/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacelift-io/spacectl/client"
+	"github.com/spacelift-io/spacectl/client/fakeapi"
+	"github.com/spacelift-io/spacectl/client/structs"
+)
+
+func TestVersions(t *testing.T) {
+	server, err := fakeapi.NewServer(fakeapi.DefaultFixtures())
+	require.NoError(t, err)
+	t.Cleanup(server.Close)
+
+	service := New(server.Client())
+
+	input := structs.SearchInput{
+		Predicates: &[]structs.QueryPredicate{{
+			Field:      "state",
+			Exclude:    true,
+			Constraint: structs.QueryFieldConstraint{EnumEquals: &[]graphql.String{"FAILED"}},
+		}},
+	}
+
+	var numbers []string
+	for version, err := range service.Versions(t.Context(), "terraform-aws-vpc", input, client.PaginateOptions{PageSize: 1}) {
+		require.NoError(t, err)
+		numbers = append(numbers, version.Number)
+	}
+	assert.Equal(t, []string{"1.2.0", "1.1.0"}, numbers)
+}
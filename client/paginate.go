@@ -0,0 +1,79 @@
+package client
+
+import (
+	"context"
+	"iter"
+
+	"github.com/shurcooL/graphql"
+
+	"github.com/spacelift-io/spacectl/client/structs"
+)
+
+// DefaultPageSize is the number of items fetched per page when paginating,
+// which is also the largest page size the API accepts.
+const DefaultPageSize = 50
+
+// Page is a page of search results.
+type Page[T any] struct {
+	Items    []T
+	PageInfo structs.PageInfo
+}
+
+// PageFunc fetches the page of search results selected by the input.
+type PageFunc[T any] func(ctx context.Context, input structs.SearchInput) (Page[T], error)
+
+// PaginateOptions control how many items are fetched and how.
+type PaginateOptions struct {
+	// Limit is the maximum number of items to return, 0 for all of them.
+	Limit int
+
+	// PageSize is the number of items fetched per request, DefaultPageSize
+	// if 0.
+	PageSize int
+}
+
+// Paginate iterates over the results of a search, fetching pages as they are
+// consumed so that callers can process items as they arrive. The First and
+// After fields of the input are managed by Paginate, and the last page is
+// shortened to fetch no more than the limit. The iteration stops at the first
+// error.
+func Paginate[T any](ctx context.Context, input structs.SearchInput, opts PaginateOptions, fetch PageFunc[T]) iter.Seq2[T, error] {
+	pageSize := opts.PageSize
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+
+	return func(yield func(T, error) bool) {
+		input.After = nil
+
+		for count := 0; ; {
+			first := pageSize
+			if opts.Limit > 0 {
+				first = min(pageSize, opts.Limit-count)
+			}
+			input.First = new(graphql.Int(first)) //nolint: gosec
+
+			page, err := fetch(ctx, input)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+
+			for _, item := range page.Items {
+				if !yield(item, nil) {
+					return
+				}
+
+				if count++; opts.Limit > 0 && count >= opts.Limit {
+					return
+				}
+			}
+
+			if !page.PageInfo.HasNextPage {
+				return
+			}
+			input.After = new(graphql.String(page.PageInfo.EndCursor))
+		}
+	}
+}
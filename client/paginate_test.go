@@ -0,0 +1,105 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"strconv"
+	"testing"
+
+	"github.com/shurcooL/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacelift-io/spacectl/client/structs"
+)
+
+// fakePages serves the numbers from 0 to total-1, recording the page sizes
+// requested.
+func fakePages(total int, requested *[]int) PageFunc[int] {
+	return func(_ context.Context, input structs.SearchInput) (Page[int], error) {
+		start := 0
+		if input.After != nil {
+			start, _ = strconv.Atoi(string(*input.After))
+		}
+
+		first := int(*input.First)
+		*requested = append(*requested, first)
+
+		var page Page[int]
+		for i := start; i < total && i < start+first; i++ {
+			page.Items = append(page.Items, i)
+		}
+
+		end := start + len(page.Items)
+		page.PageInfo = structs.PageInfo{EndCursor: strconv.Itoa(end), HasNextPage: end < total}
+
+		return page, nil
+	}
+}
+
+func collect(t *testing.T, items iter.Seq2[int, error]) []int {
+	t.Helper()
+
+	var out []int
+	for item, err := range items {
+		require.NoError(t, err)
+		out = append(out, item)
+	}
+
+	return out
+}
+
+func TestPaginate(t *testing.T) {
+	t.Run("fetches all the pages", func(t *testing.T) {
+		var requested []int
+		items := collect(t, Paginate(t.Context(), structs.SearchInput{}, PaginateOptions{}, fakePages(120, &requested)))
+
+		assert.Len(t, items, 120)
+		assert.Equal(t, 119, items[119])
+		assert.Equal(t, []int{50, 50, 50}, requested)
+	})
+
+	t.Run("fetches no more than the limit", func(t *testing.T) {
+		var requested []int
+		items := collect(t, Paginate(t.Context(), structs.SearchInput{}, PaginateOptions{Limit: 25, PageSize: 10}, fakePages(120, &requested)))
+
+		assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24}, items)
+		assert.Equal(t, []int{10, 10, 5}, requested)
+	})
+
+	t.Run("ignores the cursor of the input", func(t *testing.T) {
+		var requested []int
+		input := structs.SearchInput{First: graphql.NewInt(1), After: graphql.NewString("100")}
+		items := collect(t, Paginate(t.Context(), input, PaginateOptions{Limit: 3}, fakePages(120, &requested)))
+
+		assert.Equal(t, []int{0, 1, 2}, items)
+	})
+
+	t.Run("stops fetching when the caller stops", func(t *testing.T) {
+		var requested []int
+		for item := range Paginate(t.Context(), structs.SearchInput{}, PaginateOptions{PageSize: 2}, fakePages(120, &requested)) {
+			if item == 2 {
+				break
+			}
+		}
+
+		assert.Equal(t, []int{2, 2}, requested)
+	})
+
+	t.Run("stops at the first error", func(t *testing.T) {
+		failure := errors.New("failure")
+		calls := 0
+
+		var errs []error
+		for _, err := range Paginate(t.Context(), structs.SearchInput{}, PaginateOptions{}, func(context.Context, structs.SearchInput) (Page[int], error) {
+			calls++
+			return Page[int]{}, failure
+		}) {
+			errs = append(errs, err)
+		}
+
+		assert.Equal(t, []error{failure}, errs)
+		assert.Equal(t, 1, calls)
+	})
+}
@@ -0,0 +1,103 @@
+// Package policies provides typed access to Spacelift policies.
+package policies
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+
+	"github.com/shurcooL/graphql"
+
+	"github.com/spacelift-io/spacectl/client"
+	"github.com/spacelift-io/spacectl/client/structs"
+)
+
+// ErrNotFound is returned when a policy does not exist or is not visible to
+// the current user.
+var ErrNotFound = errors.New("policy not found")
+
+// Type is the type of a policy, like PLAN or APPROVAL.
+type Type string
+
+// Policy is a Spacelift policy.
+type Policy struct {
+	ID          string `graphql:"id" json:"id"`
+	Name        string `graphql:"name" json:"name"`
+	Description string `graphql:"description" json:"description"`
+	Body        string `graphql:"body" json:"body"`
+	Space       struct {
+		ID          string `graphql:"id" json:"id"`
+		Name        string `graphql:"name" json:"name"`
+		AccessLevel string `graphql:"accessLevel" json:"accessLevel"`
+	} `graphql:"spaceDetails" json:"spaceDetails"`
+	CreatedAt int      `graphql:"createdAt" json:"createdAt"`
+	UpdatedAt int      `graphql:"updatedAt" json:"updatedAt"`
+	Type      Type     `graphql:"type" json:"type"`
+	Labels    []string `graphql:"labels" json:"labels"`
+}
+
+// Page is a page of policy search results.
+type Page struct {
+	Policies []Policy
+	PageInfo structs.PageInfo
+}
+
+// Service gives access to the policies of an account.
+type Service struct {
+	client client.Client
+}
+
+// New returns a Service using the client.
+func New(c client.Client) *Service {
+	return &Service{client: c}
+}
+
+// Get returns the policy with the given ID, or ErrNotFound.
+func (s *Service) Get(ctx context.Context, id string) (*Policy, error) {
+	var query struct {
+		Policy *Policy `graphql:"policy(id: $policyId)"`
+	}
+
+	if err := s.client.Query(ctx, &query, map[string]any{"policyId": graphql.ID(id)}); err != nil {
+		return nil, fmt.Errorf("failed to query for policy ID %q: %w", id, err)
+	}
+
+	if query.Policy == nil {
+		return nil, ErrNotFound
+	}
+
+	return query.Policy, nil
+}
+
+// Search returns a single page of the policies matching the input.
+func (s *Service) Search(ctx context.Context, input structs.SearchInput) (Page, error) {
+	var query struct {
+		SearchPoliciesOutput struct {
+			Edges []struct {
+				Node Policy `graphql:"node"`
+			} `graphql:"edges"`
+			PageInfo structs.PageInfo `graphql:"pageInfo"`
+		} `graphql:"searchPolicies(input: $input)"`
+	}
+
+	if err := s.client.Query(ctx, &query, map[string]any{"input": input}); err != nil {
+		return Page{}, fmt.Errorf("failed search for policies: %w", err)
+	}
+
+	page := Page{Policies: make([]Policy, 0, len(query.SearchPoliciesOutput.Edges)), PageInfo: query.SearchPoliciesOutput.PageInfo}
+	for _, edge := range query.SearchPoliciesOutput.Edges {
+		page.Policies = append(page.Policies, edge.Node)
+	}
+
+	return page, nil
+}
+
+// All iterates over the policies matching the input, fetching pages as
+// needed. The iteration stops at the first error.
+func (s *Service) All(ctx context.Context, input structs.SearchInput, opts client.PaginateOptions) iter.Seq2[Policy, error] {
+	return client.Paginate(ctx, input, opts, func(ctx context.Context, input structs.SearchInput) (client.Page[Policy], error) {
+		page, err := s.Search(ctx, input)
+		return client.Page[Policy]{Items: page.Policies, PageInfo: page.PageInfo}, err
+	})
+}
@@ -0,0 +1,35 @@
+package policies
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacelift-io/spacectl/client"
+	"github.com/spacelift-io/spacectl/client/fakeapi"
+	"github.com/spacelift-io/spacectl/client/structs"
+)
+
+func TestService(t *testing.T) {
+	server, err := fakeapi.NewServer(fakeapi.DefaultFixtures())
+	require.NoError(t, err)
+	t.Cleanup(server.Close)
+
+	service := New(server.Client())
+
+	policy, err := service.Get(t.Context(), "no-public-buckets")
+	require.NoError(t, err)
+	assert.NotEmpty(t, policy.Body)
+	assert.NotEmpty(t, policy.Type)
+
+	_, err = service.Get(t.Context(), "missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	var ids []string
+	for policy, err := range service.All(t.Context(), structs.SearchInput{}, client.PaginateOptions{}) {
+		require.NoError(t, err)
+		ids = append(ids, policy.ID)
+	}
+	assert.Equal(t, []string{"no-public-buckets"}, ids)
+}
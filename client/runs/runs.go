@@ -0,0 +1,298 @@
+// Package runs provides typed access to the runs of Spacelift stacks.
+package runs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+
+	"github.com/shurcooL/graphql"
+
+	"github.com/spacelift-io/spacectl/client"
+	"github.com/spacelift-io/spacectl/client/structs"
+)
+
+// ErrNotFound is returned when a stack or a run does not exist or is not
+// visible to the current user.
+var ErrNotFound = errors.New("run not found")
+
+// Run is a run of a stack.
+type Run struct {
+	ID       string `graphql:"id" json:"id"`
+	Branch   string `graphql:"branch" json:"branch"`
+	CanRetry bool   `graphql:"canRetry" json:"canRetry"`
+	Commit   struct {
+		AuthorLogin string `graphql:"authorLogin" json:"authorLogin"`
+		AuthorName  string `graphql:"authorName" json:"authorName"`
+		Hash        string `graphql:"hash" json:"hash"`
+	} `graphql:"commit" json:"commit"`
+	CreatedAt int `graphql:"createdAt" json:"createdAt"`
+	Delta     *struct {
+		AddCount    int `graphql:"addCount" json:"addCount"`
+		ChangeCount int `graphql:"changeCount" json:"changeCount"`
+		DeleteCount int `graphql:"deleteCount" json:"deleteCount"`
+		Resources   int `graphql:"resources" json:"resources"`
+	} `graphql:"delta" json:"delta"`
+	DriftDetection bool             `graphql:"driftDetection" json:"driftDetection"`
+	Expired        bool             `graphql:"expired" json:"expired"`
+	IsMostRecent   bool             `graphql:"isMostRecent" json:"isMostRecent"`
+	NeedsApproval  bool             `graphql:"needsApproval" json:"needsApproval"`
+	State          structs.RunState `graphql:"state" json:"state"`
+	Title          string           `graphql:"title" json:"title"`
+	TriggeredBy    string           `graphql:"triggeredBy" json:"triggeredBy"`
+	Type           string           `graphql:"type" json:"type"`
+}
+
+// LogsPage is a page of the logs of a run state.
+type LogsPage struct {
+	// Exists tells whether the state has logs at all.
+	Exists bool
+
+	// Finished tells whether all the logs of the state have been written.
+	Finished bool
+
+	// HasMore tells whether more logs can already be fetched with NextToken.
+	HasMore bool
+
+	// Messages are the log lines of the page.
+	Messages []string
+
+	// NextToken is the token to fetch the next page with.
+	NextToken *string
+}
+
+// TriggerInput describes a run to trigger.
+type TriggerInput struct {
+	// Type is the type of the run, TRACKED or PROPOSED.
+	Type structs.RunType
+
+	// CommitSHA is the commit to run, instead of the tracked one.
+	CommitSHA *string
+
+	// RuntimeConfig overrides the stack configuration for this run.
+	RuntimeConfig *structs.RuntimeConfigInput
+
+	// ForceApply applies a tracked run without waiting for its dependencies.
+	// It requires Spacelift Self-Hosted 5.1.0 or later.
+	ForceApply *structs.ForceApplyMode
+}
+
+// Service gives access to the runs of the stacks of an account.
+type Service struct {
+	client client.Client
+}
+
+// New returns a Service using the client.
+func New(c client.Client) *Service {
+	return &Service{client: c}
+}
+
+// Get returns a run of a stack, or ErrNotFound.
+func (s *Service) Get(ctx context.Context, stackID, runID string) (*Run, error) {
+	var query struct {
+		Stack *struct {
+			Run *Run `graphql:"run(id: $run)"`
+		} `graphql:"stack(id: $stack)"`
+	}
+
+	if err := s.client.Query(ctx, &query, map[string]any{"stack": graphql.ID(stackID), "run": graphql.ID(runID)}); err != nil {
+		return nil, fmt.Errorf("failed to query run %q: %w", runID, err)
+	}
+
+	if query.Stack == nil || query.Stack.Run == nil {
+		return nil, ErrNotFound
+	}
+
+	return query.Stack.Run, nil
+}
+
+// List iterates over the tracked runs of a stack, newest first, fetching
+// pages as needed. The iteration stops at the first error.
+func (s *Service) List(ctx context.Context, stackID string) iter.Seq2[Run, error] {
+	return func(yield func(Run, error) bool) {
+		var query struct {
+			Stack *struct {
+				Runs []Run `graphql:"runs(before: $before)"`
+			} `graphql:"stack(id: $stack)"`
+		}
+
+		var before *graphql.ID
+		for {
+			if err := s.client.Query(ctx, &query, map[string]any{"stack": graphql.ID(stackID), "before": before}); err != nil {
+				yield(Run{}, fmt.Errorf("failed to query run list: %w", err))
+				return
+			}
+
+			if query.Stack == nil {
+				yield(Run{}, ErrNotFound)
+				return
+			}
+
+			if len(query.Stack.Runs) == 0 {
+				return
+			}
+
+			for _, run := range query.Stack.Runs {
+				if !yield(run, nil) {
+					return
+				}
+			}
+
+			before = new(graphql.ID(query.Stack.Runs[len(query.Stack.Runs)-1].ID))
+		}
+	}
+}
+
+// Trigger triggers a run of a stack.
+func (s *Service) Trigger(ctx context.Context, stackID string, input TriggerInput, opts ...graphql.RequestOption) (*Run, error) {
+	var sha *graphql.String
+	if input.CommitSHA != nil {
+		sha = new(graphql.String(*input.CommitSHA))
+	}
+
+	variables := map[string]any{
+		"stack":         graphql.ID(stackID),
+		"sha":           sha,
+		"type":          new(input.Type),
+		"runtimeConfig": input.RuntimeConfig,
+	}
+
+	// The forceApply argument is only sent when used, as older Spacelift
+	// versions do not know it.
+	if input.ForceApply != nil {
+		var mutation struct {
+			RunTrigger Run `graphql:"runTrigger(stack: $stack, commitSha: $sha, runType: $type, runtimeConfig: $runtimeConfig, forceApply: $forceApply)"`
+		}
+
+		variables["forceApply"] = input.ForceApply
+		if err := s.client.Mutate(ctx, &mutation, variables, opts...); err != nil {
+			return nil, err
+		}
+
+		return &mutation.RunTrigger, nil
+	}
+
+	var mutation struct {
+		RunTrigger Run `graphql:"runTrigger(stack: $stack, commitSha: $sha, runType: $type, runtimeConfig: $runtimeConfig)"`
+	}
+
+	if err := s.client.Mutate(ctx, &mutation, variables, opts...); err != nil {
+		return nil, err
+	}
+
+	return &mutation.RunTrigger, nil
+}
+
+// Confirm confirms a run awaiting confirmation.
+func (s *Service) Confirm(ctx context.Context, stackID, runID string, opts ...graphql.RequestOption) error {
+	var mutation struct {
+		RunConfirm struct {
+			ID string `graphql:"id"`
+		} `graphql:"runConfirm(stack: $stack, run: $run)"`
+	}
+
+	return s.client.Mutate(ctx, &mutation, map[string]any{"stack": graphql.ID(stackID), "run": graphql.ID(runID)}, opts...)
+}
+
+// Discard discards a run awaiting confirmation.
+func (s *Service) Discard(ctx context.Context, stackID, runID string, opts ...graphql.RequestOption) error {
+	var mutation struct {
+		RunDiscard struct {
+			ID string `graphql:"id"`
+		} `graphql:"runDiscard(stack: $stack, run: $run)"`
+	}
+
+	return s.client.Mutate(ctx, &mutation, map[string]any{"stack": graphql.ID(stackID), "run": graphql.ID(runID)}, opts...)
+}
+
+// History returns the state transitions of a run, newest first.
+func (s *Service) History(ctx context.Context, stackID, runID string) ([]structs.RunStateTransition, error) {
+	var query struct {
+		Stack *struct {
+			Run *struct {
+				History []structs.RunStateTransition `graphql:"history"`
+			} `graphql:"run(id: $run)"`
+		} `graphql:"stack(id: $stack)"`
+	}
+
+	if err := s.client.Query(ctx, &query, map[string]any{"stack": graphql.ID(stackID), "run": graphql.ID(runID)}); err != nil {
+		return nil, err
+	}
+
+	if query.Stack == nil {
+		return nil, fmt.Errorf("stack %q not found", stackID)
+	}
+
+	if query.Stack.Run == nil {
+		return nil, fmt.Errorf("run %q in stack %q not found", runID, stackID)
+	}
+
+	return query.Stack.Run.History, nil
+}
+
+// Logs returns a page of the logs of a run state, starting at the token if
+// set. Runs can go through the same state several times: version selects
+// which one, starting at 1.
+func (s *Service) Logs(ctx context.Context, stackID, runID string, state structs.RunState, version int, token *string) (LogsPage, error) {
+	var query struct {
+		Stack *struct {
+			Run *struct {
+				Logs *struct {
+					Exists   bool `graphql:"exists"`
+					Finished bool `graphql:"finished"`
+					HasMore  bool `graphql:"hasMore"`
+					Messages []struct {
+						Body string `graphql:"message"`
+					} `graphql:"messages"`
+					NextToken *graphql.String `graphql:"nextToken"`
+				} `graphql:"logs(state: $state, token: $token, stateVersion: $stateVersion)"`
+			} `graphql:"run(id: $run)"`
+		} `graphql:"stack(id: $stack)"`
+	}
+
+	var tokenVariable *graphql.String
+	if token != nil {
+		tokenVariable = new(graphql.String(*token))
+	}
+
+	variables := map[string]any{
+		"stack":        graphql.ID(stackID),
+		"run":          graphql.ID(runID),
+		"state":        state,
+		"token":        tokenVariable,
+		"stateVersion": graphql.Int(version), //nolint: gosec
+	}
+
+	if err := s.client.Query(ctx, &query, variables); err != nil {
+		return LogsPage{}, err
+	}
+
+	if query.Stack == nil {
+		return LogsPage{}, fmt.Errorf("stack %q not found", stackID)
+	}
+
+	if query.Stack.Run == nil {
+		return LogsPage{}, fmt.Errorf("run %q in stack %q not found", runID, stackID)
+	}
+
+	if query.Stack.Run.Logs == nil {
+		return LogsPage{}, fmt.Errorf("logs for run %q in stack %q not found", runID, stackID)
+	}
+
+	logs := query.Stack.Run.Logs
+	page := LogsPage{
+		Exists:   logs.Exists,
+		Finished: logs.Finished,
+		HasMore:  logs.HasMore,
+		Messages: make([]string, 0, len(logs.Messages)),
+	}
+	for _, message := range logs.Messages {
+		page.Messages = append(page.Messages, message.Body)
+	}
+	if logs.NextToken != nil {
+		page.NextToken = new(string(*logs.NextToken))
+	}
+
+	return page, nil
+}
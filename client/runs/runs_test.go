@@ -0,0 +1,82 @@
+package runs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacelift-io/spacectl/client/fakeapi"
+	"github.com/spacelift-io/spacectl/client/structs"
+)
+
+func newTestService(t *testing.T) *Service {
+	t.Helper()
+
+	server, err := fakeapi.NewServer(fakeapi.DefaultFixtures())
+	require.NoError(t, err)
+	t.Cleanup(server.Close)
+
+	return New(server.Client())
+}
+
+func TestTriggerAndConfirm(t *testing.T) {
+	service := newTestService(t)
+
+	run, err := service.Trigger(t.Context(), "networking", TriggerInput{Type: "TRACKED"})
+	require.NoError(t, err)
+	assert.Equal(t, structs.RunState("UNCONFIRMED"), run.State)
+
+	require.NoError(t, service.Confirm(t.Context(), "networking", run.ID))
+
+	run, err = service.Get(t.Context(), "networking", run.ID)
+	require.NoError(t, err)
+	assert.Equal(t, structs.RunState("FINISHED"), run.State)
+
+	assert.ErrorContains(t, service.Discard(t.Context(), "networking", run.ID), "cannot be discarded")
+
+	_, err = service.Get(t.Context(), "networking", "missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestList(t *testing.T) {
+	service := newTestService(t)
+
+	// The fake API returns pages of 50 runs.
+	for range 50 {
+		_, err := service.Trigger(t.Context(), "networking", TriggerInput{Type: "TRACKED"})
+		require.NoError(t, err)
+	}
+
+	var ids []string
+	for run, err := range service.List(t.Context(), "networking") {
+		require.NoError(t, err)
+		ids = append(ids, run.ID)
+	}
+	require.Len(t, ids, 51)
+	assert.Equal(t, "fake-run-50", ids[0])
+	assert.Equal(t, "01JXNET0000000000000000002", ids[50], "proposed runs are not listed")
+
+	for _, err := range service.List(t.Context(), "missing") {
+		assert.ErrorIs(t, err, ErrNotFound)
+	}
+}
+
+func TestHistoryAndLogs(t *testing.T) {
+	service := newTestService(t)
+
+	history, err := service.History(t.Context(), "app-servers", "01JXAPP0000000000000000001")
+	require.NoError(t, err)
+	require.NotEmpty(t, history)
+	assert.True(t, history[0].Terminal)
+
+	logs, err := service.Logs(t.Context(), "app-servers", "01JXAPP0000000000000000001", "PLANNING", 1, nil)
+	require.NoError(t, err)
+	assert.True(t, logs.Exists)
+	assert.True(t, logs.Finished)
+	assert.Contains(t, strings.Join(logs.Messages, ""), "Error: creating EC2 Instance")
+
+	_, err = service.History(t.Context(), "app-servers", "missing")
+	assert.ErrorContains(t, err, `run "missing" in stack "app-servers" not found`)
+}
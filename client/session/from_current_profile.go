@@ -21,3 +21,19 @@ func FromCurrentProfile(ctx context.Context, client *http.Client) (Session, erro
 
 	return currentProfile.Credentials.Session(ctx, client)
 }
+
+// NetworkSettingsFromCurrentProfile returns the network settings of the currently selected
+// profile, or nil if no profile is selected or the profiles cannot be read.
+func NetworkSettingsFromCurrentProfile() *NetworkSettings {
+	manager, err := UserProfileManager()
+	if err != nil {
+		return nil
+	}
+
+	currentProfile := manager.Current()
+	if currentProfile == nil {
+		return nil
+	}
+
+	return currentProfile.Network
+}
@@ -0,0 +1,105 @@
+package session
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"net/url"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+var errCACertificateParse = errors.New("failed to parse the CA certificate")
+
+// NetworkSettings contains proxy and TLS settings stored alongside a profile's
+// credentials. They are applied to every outbound request made on behalf of
+// the profile, including uploads and downloads using pre-signed URLs.
+type NetworkSettings struct {
+	// ProxyURL is the URL of the HTTP(S) proxy to send requests through.
+	ProxyURL string `json:"proxy_url,omitempty"`
+
+	// NoProxy is a comma-separated list of hosts which bypass the proxy, using
+	// the same syntax as the NO_PROXY environment variable.
+	NoProxy string `json:"no_proxy,omitempty"`
+
+	// CACertificate is a PEM bundle used to verify server certificates.
+	CACertificate string `json:"ca_certificate,omitempty"`
+
+	// ClientCertificate is a PEM-encoded client certificate used for mutual TLS.
+	ClientCertificate string `json:"client_certificate,omitempty"`
+
+	// ClientKey is the PEM-encoded private key of the client certificate.
+	ClientKey string `json:"client_key,omitempty"`
+}
+
+// IsEmpty returns true if none of the settings are set.
+func (s *NetworkSettings) IsEmpty() bool {
+	return s == nil || *s == NetworkSettings{}
+}
+
+// Validate checks that the settings can be turned into a working transport.
+func (s *NetworkSettings) Validate() error {
+	if s == nil {
+		return nil
+	}
+
+	if s.ProxyURL != "" {
+		if _, err := url.Parse(s.ProxyURL); err != nil {
+			return errors.New("'ProxyURL' must be a valid URL")
+		}
+	}
+
+	if (s.ClientCertificate == "") != (s.ClientKey == "") {
+		return errors.New("'ClientCertificate' and 'ClientKey' must be provided together")
+	}
+
+	_, err := s.ApplyTLS(&tls.Config{MinVersion: tls.VersionTLS12})
+	return err
+}
+
+// ApplyTLS applies the CA bundle and client certificate to the TLS config and
+// returns it.
+func (s *NetworkSettings) ApplyTLS(config *tls.Config) (*tls.Config, error) {
+	if s == nil {
+		return config, nil
+	}
+
+	if s.CACertificate != "" {
+		caCertPool := x509.NewCertPool()
+		if !caCertPool.AppendCertsFromPEM([]byte(s.CACertificate)) {
+			return nil, errCACertificateParse
+		}
+
+		config.RootCAs = caCertPool
+	}
+
+	if s.ClientCertificate != "" && s.ClientKey != "" {
+		cert, err := tls.X509KeyPair([]byte(s.ClientCertificate), []byte(s.ClientKey))
+		if err != nil {
+			return nil, err
+		}
+
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
+
+// Proxy returns the proxy function to use in an HTTP transport. When no proxy
+// is configured, the proxy is taken from the environment.
+func (s *NetworkSettings) Proxy() func(*http.Request) (*url.URL, error) {
+	if s == nil || s.ProxyURL == "" {
+		return http.ProxyFromEnvironment
+	}
+
+	proxyFunc := (&httpproxy.Config{
+		HTTPProxy:  s.ProxyURL,
+		HTTPSProxy: s.ProxyURL,
+		NoProxy:    s.NoProxy,
+	}).ProxyFunc()
+
+	return func(req *http.Request) (*url.URL, error) {
+		return proxyFunc(req.URL)
+	}
+}
@@ -42,6 +42,9 @@ type Profile struct {
 
 	// The credentials used to make Spacelift API requests.
 	Credentials *StoredCredentials `json:"credentials,omitempty"`
+
+	// The proxy and TLS settings used when making requests for this profile.
+	Network *NetworkSettings `json:"network,omitempty"`
 }
 
 // A ProfileManager is used to interact with Spacelift profiles.
@@ -194,6 +197,10 @@ func validateProfile(profile *Profile) error {
 		return errors.New("'Endpoint' must be provided")
 	}
 
+	if err := profile.Network.Validate(); err != nil {
+		return fmt.Errorf("invalid network settings: %w", err)
+	}
+
 	switch credentialType := profile.Credentials.Type; credentialType {
 	case CredentialsTypeGitHubToken:
 		if err := validateGitHubCredentials(profile); err != nil {
@@ -0,0 +1,87 @@
+package stacks
+
+import (
+	"context"
+	"errors"
+
+	"github.com/shurcooL/graphql"
+)
+
+// ErrConfigNotFound is returned when deleting a configuration element that
+// does not exist.
+var ErrConfigNotFound = errors.New("configuration element not found")
+
+// ConfigType is a type of configuration element.
+type ConfigType string
+
+const (
+	// ConfigTypeEnvironmentVariable is an environment variable.
+	ConfigTypeEnvironmentVariable = ConfigType("ENVIRONMENT_VARIABLE")
+
+	// ConfigTypeFileMount is a file mounted in the workspace.
+	ConfigTypeFileMount = ConfigType("FILE_MOUNT")
+)
+
+// ConfigInput represents the input required to create or update a config
+// element. The values of file mounts are base64 encoded.
+type ConfigInput struct {
+	ID        graphql.ID      `json:"id"`
+	Type      ConfigType      `json:"type"`
+	Value     graphql.String  `json:"value"`
+	WriteOnly graphql.Boolean `json:"writeOnly"`
+}
+
+// ConfigElement is an environment variable or a mounted file of a stack.
+// The values of write-only elements are not returned.
+type ConfigElement struct {
+	ID        string     `graphql:"id" json:"id,omitempty"`
+	Checksum  string     `graphql:"checksum" json:"checksum,omitempty"`
+	CreatedAt int64      `graphql:"createdAt" json:"createdAt,omitempty"`
+	Runtime   bool       `graphql:"runtime" json:"runtime,omitempty"`
+	Type      ConfigType `graphql:"type" json:"type,omitempty"`
+	Value     *string    `graphql:"value" json:"value,omitempty"`
+	WriteOnly bool       `graphql:"writeOnly" json:"writeOnly,omitempty"`
+}
+
+// AddConfig creates or updates a configuration element of the stack.
+func (s *Service) AddConfig(ctx context.Context, stackID string, input ConfigInput) (*ConfigElement, error) {
+	var mutation struct {
+		ConfigElement ConfigElement `graphql:"stackConfigAdd(stack: $stack, config: $config)"`
+	}
+
+	variables := map[string]any{
+		"stack":  graphql.ID(stackID),
+		"config": input,
+	}
+
+	if err := s.client.Mutate(ctx, &mutation, variables); err != nil {
+		return nil, err
+	}
+
+	return &mutation.ConfigElement, nil
+}
+
+// DeleteConfig deletes a configuration element of the stack, or returns
+// ErrConfigNotFound.
+func (s *Service) DeleteConfig(ctx context.Context, stackID, id string) error {
+	var mutation struct {
+		ConfigElement *struct {
+			ID string
+		} `graphql:"stackConfigDelete(stack: $stack, id: $id)"`
+	}
+
+	variables := map[string]any{
+		"stack": graphql.ID(stackID),
+		"id":    graphql.ID(id),
+	}
+
+	if err := s.client.Mutate(ctx, &mutation, variables); err != nil {
+		return err
+	}
+
+	if mutation.ConfigElement == nil {
+		return ErrConfigNotFound
+	}
+
+	return nil
+}
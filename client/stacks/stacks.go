@@ -0,0 +1,251 @@
+// Package stacks provides typed access to Spacelift stacks.
+package stacks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+
+	"github.com/shurcooL/graphql"
+
+	"github.com/spacelift-io/spacectl/client"
+	"github.com/spacelift-io/spacectl/client/structs"
+)
+
+// ErrNotFound is returned when a stack does not exist or is not visible to the
+// current user.
+var ErrNotFound = errors.New("stack not found")
+
+// Stack is a Spacelift stack.
+type Stack struct {
+	ID             string `graphql:"id" json:"id,omitempty"`
+	Administrative bool   `graphql:"administrative" json:"administrative,omitempty"`
+	Autodeploy     bool   `graphql:"autodeploy" json:"autodeploy,omitempty"`
+	Autoretry      bool   `graphql:"autoretry" json:"autoretry,omitempty"`
+	Blocker        struct {
+		ID string `graphql:"id" json:"id,omitempty"`
+	} `graphql:"blocker"`
+	AfterApply          []string `graphql:"afterApply" json:"afterApply,omitempty"`
+	BeforeApply         []string `graphql:"beforeApply" json:"beforeApply,omitempty"`
+	AfterInit           []string `graphql:"afterInit" json:"afterInit,omitempty"`
+	BeforeInit          []string `graphql:"beforeInit" json:"beforeInit,omitempty"`
+	AfterPlan           []string `graphql:"afterPlan" json:"afterPlan,omitempty"`
+	BeforePlan          []string `graphql:"beforePlan" json:"beforePlan,omitempty"`
+	AfterPerform        []string `graphql:"afterPerform" json:"afterPerform,omitempty"`
+	BeforePerform       []string `graphql:"beforePerform" json:"beforePerform,omitempty"`
+	AfterDestroy        []string `graphql:"afterDestroy" json:"afterDestroy,omitempty"`
+	BeforeDestroy       []string `graphql:"beforeDestroy" json:"beforeDestroy,omitempty"`
+	Branch              string   `graphql:"branch" json:"branch,omitempty"`
+	CanWrite            bool     `graphql:"canWrite" json:"canWrite,omitempty"`
+	CreatedAt           int64    `graphql:"createdAt" json:"createdAt,omitempty"`
+	Deleted             bool     `graphql:"deleted" json:"deleted,omitempty"`
+	Deleting            bool     `graphql:"deleting" json:"deleting,omitempty"`
+	Description         string   `graphql:"description" json:"description,omitempty"`
+	Labels              []string `graphql:"labels" json:"labels,omitempty"`
+	LocalPreviewEnabled bool     `graphql:"localPreviewEnabled" json:"localPreviewEnabled,omitempty"`
+	LockedBy            string   `graphql:"lockedBy" json:"lockedBy,omitempty"`
+	ManagesStateFile    bool     `graphql:"managesStateFile" json:"managesStateFile,omitempty"`
+	Name                string   `graphql:"name" json:"name,omitempty"`
+	Namespace           string   `graphql:"namespace" json:"namespace,omitempty"`
+	ProjectRoot         string   `graphql:"projectRoot" json:"projectRoot,omitempty"`
+	Provider            string   `graphql:"provider" json:"provider,omitempty"`
+	Repository          string   `graphql:"repository" json:"repository,omitempty"`
+	RunnerImage         string   `graphql:"runnerImage" json:"runnerImage,omitempty"`
+	Starred             bool     `graphql:"starred" json:"starred,omitempty"`
+	State               string   `graphql:"state" json:"state,omitempty"`
+	StateSetAt          int64    `graphql:"stateSetAt" json:"stateSetAt,omitempty"`
+	TerraformVersion    string   `graphql:"terraformVersion" json:"terraformVersion,omitempty"`
+	SpaceDetails        struct {
+		ID          string  `graphql:"id" json:"id,omitempty"`
+		Name        string  `graphql:"name" json:"name,omitempty"`
+		Description string  `graphql:"description" json:"description,omitempty"`
+		ParentSpace *string `graphql:"parentSpace" json:"parentSpace,omitempty"`
+	} `graphql:"spaceDetails" json:"spaceDetails"`
+	TrackedCommit struct {
+		AuthorLogin string `graphql:"authorLogin" json:"authorLogin,omitempty"`
+		AuthorName  string `graphql:"authorName" json:"authorName,omitempty"`
+		Hash        string `graphql:"hash" json:"hash,omitempty"`
+		Message     string `graphql:"message" json:"message,omitempty"`
+		Timestamp   int64  `graphql:"timestamp" json:"timestamp,omitempty"`
+		URL         string `graphql:"url" json:"url,omitempty"`
+	} `graphql:"trackedCommit" json:"trackedCommit"`
+	TrackedCommitSetBy string `graphql:"trackedCommitSetBy" json:"trackedCommitSetBy,omitempty"`
+	VendorConfig       struct {
+		Vendor string `graphql:"__typename" json:"vendor,omitempty"`
+	} `graphql:"vendorConfig" json:"vendorConfig"`
+	WorkerPool struct {
+		ID   string `graphql:"id" json:"id,omitempty"`
+		Name string `graphql:"name" json:"name,omitempty"`
+	} `graphql:"workerPool" json:"workerPool"`
+}
+
+// GetID returns the ID of the stack.
+func (s Stack) GetID() string {
+	return s.ID
+}
+
+// GetName returns the name of the stack.
+func (s Stack) GetName() string {
+	return s.Name
+}
+
+// Page is a page of stack search results.
+type Page struct {
+	Stacks   []Stack
+	PageInfo structs.PageInfo
+}
+
+// Service gives access to the stacks of an account.
+type Service struct {
+	client         client.Client
+	transferClient *http.Client
+}
+
+// Option configures a Service.
+type Option func(*Service)
+
+// WithTransferHTTPClient sets the HTTP client downloading files from presigned
+// URLs, like state files. It defaults to client.GetTransferHTTPClient.
+func WithTransferHTTPClient(c *http.Client) Option {
+	return func(s *Service) {
+		s.transferClient = c
+	}
+}
+
+// New returns a Service using the client.
+func New(c client.Client, opts ...Option) *Service {
+	s := &Service{client: c}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Get returns the stack with the given ID, or ErrNotFound.
+func (s *Service) Get(ctx context.Context, id string) (*Stack, error) {
+	var query struct {
+		Stack *Stack `graphql:"stack(id: $id)"`
+	}
+
+	if err := s.client.Query(ctx, &query, map[string]any{"id": graphql.ID(id)}); err != nil {
+		return nil, fmt.Errorf("failed to query stack %q: %w", id, err)
+	}
+
+	if query.Stack == nil {
+		return nil, ErrNotFound
+	}
+
+	return query.Stack, nil
+}
+
+// Search returns a single page of the stacks matching the input.
+func (s *Service) Search(ctx context.Context, input structs.SearchInput) (Page, error) {
+	var query struct {
+		SearchStacksOutput struct {
+			Edges []struct {
+				Node Stack `graphql:"node"`
+			} `graphql:"edges"`
+			PageInfo structs.PageInfo `graphql:"pageInfo"`
+		} `graphql:"searchStacks(input: $input)"`
+	}
+
+	if err := s.client.Query(
+		ctx,
+		&query,
+		map[string]any{"input": input},
+		graphql.WithHeader("Spacelift-GraphQL-Query", "StacksPage"),
+	); err != nil {
+		return Page{}, fmt.Errorf("failed search for stacks: %w", err)
+	}
+
+	page := Page{Stacks: make([]Stack, 0, len(query.SearchStacksOutput.Edges)), PageInfo: query.SearchStacksOutput.PageInfo}
+	for _, edge := range query.SearchStacksOutput.Edges {
+		page.Stacks = append(page.Stacks, edge.Node)
+	}
+
+	return page, nil
+}
+
+// All iterates over the stacks matching the input, fetching pages as needed.
+// The iteration stops at the first error.
+func (s *Service) All(ctx context.Context, input structs.SearchInput, opts client.PaginateOptions) iter.Seq2[Stack, error] {
+	return client.Paginate(ctx, input, opts, func(ctx context.Context, input structs.SearchInput) (client.Page[Stack], error) {
+		page, err := s.Search(ctx, input)
+		return client.Page[Stack]{Items: page.Stacks, PageInfo: page.PageInfo}, err
+	})
+}
+
+// Lock locks the stack for the current user, with an optional note.
+func (s *Service) Lock(ctx context.Context, id, note string) error {
+	var mutation struct {
+		Stack struct {
+			ID string `graphql:"id"`
+		} `graphql:"stackLock(id: $stack, note: $note)"`
+	}
+
+	return s.client.Mutate(ctx, &mutation, map[string]any{
+		"stack": graphql.ID(id),
+		"note":  graphql.String(note),
+	})
+}
+
+// Unlock releases the lock on the stack.
+func (s *Service) Unlock(ctx context.Context, id string) error {
+	var mutation struct {
+		Stack struct {
+			ID string `graphql:"id"`
+		} `graphql:"stackUnlock(id: $stack)"`
+	}
+
+	return s.client.Mutate(ctx, &mutation, map[string]any{"stack": graphql.ID(id)})
+}
+
+// StateDownloadUrlInput is the input of the stateDownloadUrl mutation.
+type StateDownloadUrlInput struct { //nolint:staticcheck // type name must match GraphQL schema exactly
+	StackID graphql.ID `json:"stackId"`
+}
+
+// DownloadState returns the current state file of a stack managing its
+// state. Callers must close it.
+func (s *Service) DownloadState(ctx context.Context, id string) (io.ReadCloser, error) {
+	var mutation struct {
+		StateDownloadURL struct {
+			URL string `graphql:"url"`
+		} `graphql:"stateDownloadUrl(input: $input)"`
+	}
+
+	variables := map[string]any{
+		"input": StateDownloadUrlInput{StackID: graphql.ID(id)},
+	}
+
+	if err := s.client.Mutate(ctx, &mutation, variables); err != nil {
+		return nil, fmt.Errorf("failed to get state download URL for stack %q: %w", id, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, mutation.StateDownloadURL.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create download request: %w", err)
+	}
+
+	transferClient := s.transferClient
+	if transferClient == nil {
+		transferClient = client.GetTransferHTTPClient()
+	}
+
+	resp, err := transferClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download state: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("failed to download state: HTTP %d: %s", resp.StatusCode, string(body))
+	}
+
+	return resp.Body, nil
+}
@@ -0,0 +1,110 @@
+package stacks
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacelift-io/spacectl/client"
+	"github.com/spacelift-io/spacectl/client/fakeapi"
+	"github.com/spacelift-io/spacectl/client/structs"
+)
+
+func newTestService(t *testing.T) *Service {
+	t.Helper()
+
+	server, err := fakeapi.NewServer(fakeapi.DefaultFixtures())
+	require.NoError(t, err)
+	t.Cleanup(server.Close)
+
+	return New(server.Client())
+}
+
+func TestGet(t *testing.T) {
+	service := newTestService(t)
+
+	stack, err := service.Get(t.Context(), "app-servers")
+	require.NoError(t, err)
+	assert.Equal(t, "app-servers", stack.GetID())
+	assert.Equal(t, "StackConfigVendorTerraform", stack.VendorConfig.Vendor)
+
+	_, err = service.Get(t.Context(), "missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestAll(t *testing.T) {
+	service := newTestService(t)
+
+	var ids []string
+	for stack, err := range service.All(t.Context(), structs.SearchInput{}, client.PaginateOptions{PageSize: 1}) {
+		require.NoError(t, err)
+		ids = append(ids, stack.ID)
+	}
+	assert.Equal(t, []string{"networking", "app-servers"}, ids)
+
+	ids = nil
+	for stack, err := range service.All(t.Context(), structs.SearchInput{}, client.PaginateOptions{Limit: 1}) {
+		require.NoError(t, err)
+		ids = append(ids, stack.ID)
+	}
+	assert.Equal(t, []string{"networking"}, ids)
+
+	for stack, err := range service.All(t.Context(), structs.SearchInput{}, client.PaginateOptions{PageSize: 1}) {
+		require.NoError(t, err)
+		assert.Equal(t, "networking", stack.ID)
+		break
+	}
+}
+
+func TestLockAndUnlock(t *testing.T) {
+	service := newTestService(t)
+
+	require.NoError(t, service.Lock(t.Context(), "networking", "maintenance"))
+	stack, err := service.Get(t.Context(), "networking")
+	require.NoError(t, err)
+	assert.NotEmpty(t, stack.LockedBy)
+
+	require.NoError(t, service.Unlock(t.Context(), "networking"))
+	stack, err = service.Get(t.Context(), "networking")
+	require.NoError(t, err)
+	assert.Empty(t, stack.LockedBy)
+}
+
+type countingTransport struct {
+	requests int
+}
+
+func (c *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	c.requests++
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestDownloadState(t *testing.T) {
+	server, err := fakeapi.NewServer(fakeapi.Fixtures{
+		Stacks: []fakeapi.Object{
+			{"id": "networking", "stateFile": fakeapi.Object{"version": 4, "serial": 7}},
+			{"id": "app-servers"},
+		},
+	})
+	require.NoError(t, err)
+	t.Cleanup(server.Close)
+
+	transport := &countingTransport{}
+	service := New(server.Client(), WithTransferHTTPClient(&http.Client{Transport: transport}))
+
+	state, err := service.DownloadState(t.Context(), "networking")
+	require.NoError(t, err)
+	defer state.Close()
+
+	var decoded map[string]any
+	require.NoError(t, json.NewDecoder(state).Decode(&decoded))
+	assert.Equal(t, map[string]any{"version": float64(4), "serial": float64(7)}, decoded)
+	assert.Equal(t, 1, transport.requests, "the state is downloaded with the transfer client")
+
+	_, err = service.DownloadState(t.Context(), "app-servers")
+	assert.ErrorContains(t, err, "failed to download state: HTTP 404")
+	assert.Equal(t, 2, transport.requests)
+}
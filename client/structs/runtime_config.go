@@ -1,4 +1,4 @@
-package stack
+package structs
 
 // RuntimeConfigInput represents the input for triggering a run with a runtime configuration.
 type RuntimeConfigInput struct {
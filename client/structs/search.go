@@ -32,6 +32,14 @@ type QueryFieldConstraint struct {
 	BooleanEquals *[]graphql.Boolean `json:"booleanEquals"`
 	EnumEquals    *[]graphql.String  `json:"enumEquals"`
 	StringMatches *[]graphql.String  `json:"stringMatches"`
+	TimeInRange   *TimeRange         `json:"timeInRange,omitempty"`
+}
+
+// TimeRange is a time window used by a search
+// constraint, as Unix timestamps in seconds.
+type TimeRange struct {
+	Start *graphql.Int `json:"start"`
+	End   *graphql.Int `json:"end"`
 }
 
 // PageInfo is the extra information about
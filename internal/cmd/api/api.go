@@ -86,7 +86,7 @@ func run(ctx context.Context, cliCmd *cli.Command) error {
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := authenticated.Client().Do(req)
+	resp, err := authenticated.Client(ctx).Do(req)
 	if err != nil {
 		return err
 	}
@@ -74,7 +74,7 @@ func registerListAPIKeysTool(s *server.MCPServer) {
 			APIKeys []apiKeyNode `graphql:"apiKeys"`
 		}
 
-		if err := authenticated.Client().Query(ctx, &query, map[string]any{}); err != nil {
+		if err := authenticated.Client(ctx).Query(ctx, &query, map[string]any{}); err != nil {
 			return nil, errors.Wrap(err, "failed to query API keys")
 		}
 
@@ -117,7 +117,7 @@ func registerGetAPIKeyTool(s *server.MCPServer) {
 			"id": graphql.ID(apiKeyID),
 		}
 
-		if err := authenticated.Client().Query(ctx, &query, variables); err != nil {
+		if err := authenticated.Client(ctx).Query(ctx, &query, variables); err != nil {
 			return nil, errors.Wrapf(err, "failed to query for API key ID %q", apiKeyID)
 		}
 
@@ -30,6 +30,7 @@ func Command() cmd.Command {
 								cmd.FlagOutputFormat,
 								cmd.FlagNoColor,
 								cmd.FlagLimit,
+								cmd.FlagPageSize,
 								cmd.FlagSearch,
 							},
 							Action: listAuditTrails(),
@@ -3,13 +3,13 @@ package audittrail
 import (
 	"context"
 	"fmt"
-	"math"
-	"slices"
+	"iter"
 
 	"github.com/pkg/errors"
 	"github.com/shurcooL/graphql"
 	"github.com/urfave/cli/v3"
 
+	"github.com/spacelift-io/spacectl/client"
 	"github.com/spacelift-io/spacectl/client/structs"
 	"github.com/spacelift-io/spacectl/internal/cmd"
 	"github.com/spacelift-io/spacectl/internal/cmd/authenticated"
@@ -22,70 +22,45 @@ var defaultOrder = structs.QueryOrder{
 
 func listAuditTrails() cli.ActionFunc {
 	return func(ctx context.Context, cliCmd *cli.Command) error {
-		outputFormat, err := cmd.GetOutputFormat(cliCmd)
+		outputFormat, formatter, err := cmd.GetOutputFormat(cliCmd)
 		if err != nil {
 			return err
 		}
 
-		var limit *uint
-		if cliCmd.IsSet(cmd.FlagLimit.Name) {
-			if cliCmd.Uint(cmd.FlagLimit.Name) >= math.MaxInt32 {
-				return fmt.Errorf("limit must be less than %d", math.MaxInt32)
-			}
-
-			limit = new(cliCmd.Uint(cmd.FlagLimit.Name))
+		opts, err := cmd.GetPaginateOptions(cliCmd)
+		if err != nil {
+			return err
 		}
 
-		var search *string
+		input := structs.SearchInput{OrderBy: &defaultOrder}
 		if cliCmd.IsSet(cmd.FlagSearch.Name) {
 			if cliCmd.String(cmd.FlagSearch.Name) == "" {
 				return fmt.Errorf("search must be non-empty")
 			}
 
-			search = new(cliCmd.String(cmd.FlagSearch.Name))
+			input.FullTextSearch = new(graphql.String(cliCmd.String(cmd.FlagSearch.Name)))
 		}
 
+		entries := paginateAuditTrailEntries(ctx, input, opts)
+
 		switch outputFormat {
 		case cmd.OutputFormatTable:
-			return listAuditTrailEntriesTable(ctx, search, limit)
-		case cmd.OutputFormatJSON:
-			return listAuditTrailEntriesJSON(ctx, search, limit)
+			return listAuditTrailEntriesTable(entries)
+		default:
+			return cmd.OutputStream(outputFormat, formatter, entries)
 		}
-
-		return fmt.Errorf("unknown output format: %v", outputFormat)
 	}
 }
 
-func listAuditTrailEntriesTable(
-	ctx context.Context,
-	search *string,
-	limit *uint,
-) error {
-	var first *graphql.Int
-	if limit != nil {
-		first = new(graphql.Int(*limit)) //nolint: gosec
-	}
-
-	var fullTextSearch *graphql.String
-	if search != nil {
-		fullTextSearch = new(graphql.String(*search))
-	}
-
-	input := structs.SearchInput{
-		First:          first,
-		FullTextSearch: fullTextSearch,
-		OrderBy:        &defaultOrder,
-	}
-
-	entries, err := searchAllAuditTrailEntries(ctx, input)
-	if err != nil {
-		return err
-	}
-
+func listAuditTrailEntriesTable(entries iter.Seq2[auditTrailEntryNode, error]) error {
 	columns := []string{"Action", "Type", "Affected Resource", "Related Resource", "Created By", "Created At"}
 
 	tableData := [][]string{columns}
-	for _, b := range entries {
+	for b, err := range entries {
+		if err != nil {
+			return err
+		}
+
 		row := []string{
 			b.Action,
 			b.EventType,
@@ -101,74 +76,11 @@ func listAuditTrailEntriesTable(
 	return cmd.OutputTable(tableData, true)
 }
 
-func listAuditTrailEntriesJSON(
-	ctx context.Context,
-	search *string,
-	limit *uint,
-) error {
-	var first *graphql.Int
-	if limit != nil {
-		//nolint: gosec
-		first = new(graphql.Int(*limit))
-	}
-
-	var fullTextSearch *graphql.String
-	if search != nil {
-		fullTextSearch = new(graphql.String(*search))
-	}
-
-	auditTrailEntries, err := searchAllAuditTrailEntries(ctx, structs.SearchInput{
-		First:          first,
-		FullTextSearch: fullTextSearch,
-		OrderBy:        &defaultOrder,
+func paginateAuditTrailEntries(ctx context.Context, input structs.SearchInput, opts client.PaginateOptions) iter.Seq2[auditTrailEntryNode, error] {
+	return client.Paginate(ctx, input, opts, func(ctx context.Context, input structs.SearchInput) (client.Page[auditTrailEntryNode], error) {
+		result, err := searchAuditTrailEntries(ctx, input)
+		return client.Page[auditTrailEntryNode]{Items: result.AuditTrailEntries, PageInfo: result.PageInfo}, err
 	})
-	if err != nil {
-		return err
-	}
-
-	return cmd.OutputJSON(auditTrailEntries)
-}
-
-func searchAllAuditTrailEntries(ctx context.Context, input structs.SearchInput) ([]auditTrailEntryNode, error) {
-	const maxPageSize = 50
-
-	var limit int
-	if input.First != nil {
-		limit = int(*input.First)
-	}
-	fetchAll := limit == 0
-
-	out := []auditTrailEntryNode{}
-	pageInput := structs.SearchInput{
-		First:          graphql.NewInt(maxPageSize),
-		FullTextSearch: input.FullTextSearch,
-		OrderBy:        input.OrderBy,
-	}
-	for {
-		if !fetchAll {
-			pageInput.First = new(
-				//nolint: gosec
-				graphql.Int(
-					slices.Min([]int{maxPageSize, limit - len(out)}),
-				),
-			)
-		}
-
-		result, err := searchAuditTrailEntries(ctx, pageInput)
-		if err != nil {
-			return nil, err
-		}
-
-		out = append(out, result.AuditTrailEntries...)
-
-		if result.PageInfo.HasNextPage && (fetchAll || limit > len(out)) {
-			pageInput.After = new(graphql.String(result.PageInfo.EndCursor))
-		} else {
-			break
-		}
-	}
-
-	return out, nil
 }
 
 func searchAuditTrailEntries(ctx context.Context, input structs.SearchInput) (searchAuditTrailEntriesResult, error) {
@@ -181,7 +93,7 @@ func searchAuditTrailEntries(ctx context.Context, input structs.SearchInput) (se
 		} `graphql:"searchAuditTrailEntries(input: $input)"`
 	}
 
-	if err := authenticated.Client().Query(
+	if err := authenticated.Client(ctx).Query(
 		ctx,
 		&query,
 		map[string]any{"input": input},
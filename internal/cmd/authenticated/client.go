@@ -12,6 +12,7 @@ import (
 	"github.com/urfave/cli/v3"
 
 	"github.com/spacelift-io/spacectl/client"
+	"github.com/spacelift-io/spacectl/client/cassette"
 	"github.com/spacelift-io/spacectl/client/session"
 )
 
@@ -39,28 +40,60 @@ var (
 	m    sync.Mutex
 )
 
-// Client returns the authenticated client.
-//
-// This is an unfortunate global which we have to lock for MCP.
-// TODO: Refactor this to not use a global.
-func Client() client.Client {
+type clientKey struct{}
+
+// WithClient returns a context carrying its own authenticated client, which
+// takes precedence over the one set up by Ensure. The MCP HTTP server uses it
+// to make the API calls of a request with the credentials of that request.
+func WithClient(ctx context.Context, c client.Client) context.Context {
+	return context.WithValue(ctx, clientKey{}, c)
+}
+
+// Client returns the authenticated client carried by the context or, if there
+// is none, the one set up by Ensure.
+func Client(ctx context.Context) client.Client {
+	if c, ok := ctx.Value(clientKey{}).(client.Client); ok {
+		return c
+	}
+
 	m.Lock()
 	defer m.Unlock()
 
 	return auth
 }
 
+type credentialsKey struct{}
+
+// WithCredentials returns a context recording an identifier of the
+// credentials of its client, so that work done for a caller, like polling a
+// run, is only shared with the callers using the same credentials.
+func WithCredentials(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, credentialsKey{}, id)
+}
+
+// Credentials returns the identifier recorded with WithCredentials, or an
+// empty string for the client set up by Ensure.
+func Credentials(ctx context.Context) string {
+	id, _ := ctx.Value(credentialsKey{}).(string)
+	return id
+}
+
 // Ensure is a way of ensuring that the Client exists, and it meant to be used
 // as a Before action for commands that need it.
 //
-// You can also use it diretly to refresh the client.
+// You can also use it diretly to refresh the client. It does nothing if the
+// context already carries a client.
 func Ensure(ctx context.Context, _ *cli.Command) (context.Context, error) {
+	if _, ok := ctx.Value(clientKey{}).(client.Client); ok {
+		return ctx, nil
+	}
+
 	m.Lock()
 	defer m.Unlock()
 
 	httpClient := client.GetHTTPClient()
 
-	if err := configureTLS(httpClient); err != nil {
+	if err := ConfigureHTTPClient(httpClient, session.NetworkSettingsFromCurrentProfile()); err != nil {
 		return ctx, err
 	}
 
@@ -74,10 +107,15 @@ func Ensure(ctx context.Context, _ *cli.Command) (context.Context, error) {
 	return ctx, nil
 }
 
-// configureTLS configures client TLS from the environment.
-func configureTLS(httpClient *http.Client) error {
-	clientTLS := &tls.Config{
+// ConfigureHTTPClient configures the proxy and TLS settings of the client
+// transport from the profile network settings and the environment. TLS
+// settings from the environment take precedence over the profile ones.
+func ConfigureHTTPClient(httpClient *http.Client, settings *session.NetworkSettings) error {
+	clientTLS, err := settings.ApplyTLS(&tls.Config{
 		MinVersion: tls.VersionTLS12,
+	})
+	if err != nil {
+		return fmt.Errorf("invalid profile network settings: %w", err)
 	}
 
 	// Check SPACELIFT_API_TLS_CA first, then fall back to SSL_CERT_FILE.
@@ -119,8 +157,15 @@ func configureTLS(httpClient *http.Client) error {
 
 	transport := http.DefaultTransport.(*http.Transport).Clone()
 	transport.TLSClientConfig = clientTLS
+	transport.Proxy = settings.Proxy()
+
+	// Record or replay the exchanges if asked to, see the cassette package.
+	roundTripper, err := cassette.FromEnvironment(transport)
+	if err != nil {
+		return err
+	}
 
-	httpClient.Transport = transport
+	httpClient.Transport = roundTripper
 
 	return nil
 }
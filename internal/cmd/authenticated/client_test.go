@@ -17,6 +17,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/spacelift-io/spacectl/client/session"
 )
 
 func TestConfigureTLS_NoEnvVars(t *testing.T) {
@@ -24,7 +26,7 @@ func TestConfigureTLS_NoEnvVars(t *testing.T) {
 	t.Setenv("SSL_CERT_FILE", "")
 
 	httpClient := &http.Client{}
-	err := configureTLS(httpClient)
+	err := ConfigureHTTPClient(httpClient, nil)
 	require.NoError(t, err)
 
 	transport := httpClient.Transport.(*http.Transport)
@@ -39,7 +41,7 @@ func TestConfigureTLS_SpaceliftCA(t *testing.T) {
 	t.Setenv("SSL_CERT_FILE", "")
 
 	httpClient := &http.Client{}
-	err := configureTLS(httpClient)
+	err := ConfigureHTTPClient(httpClient, nil)
 	require.NoError(t, err)
 
 	transport := httpClient.Transport.(*http.Transport)
@@ -53,7 +55,7 @@ func TestConfigureTLS_SSLCertFileFallback(t *testing.T) {
 	t.Setenv("SSL_CERT_FILE", caFile)
 
 	httpClient := &http.Client{}
-	err := configureTLS(httpClient)
+	err := ConfigureHTTPClient(httpClient, nil)
 	require.NoError(t, err)
 
 	transport := httpClient.Transport.(*http.Transport)
@@ -67,7 +69,7 @@ func TestConfigureTLS_SpaceliftCATakesPrecedence(t *testing.T) {
 	t.Setenv("SSL_CERT_FILE", "/nonexistent/path.pem")
 
 	httpClient := &http.Client{}
-	err := configureTLS(httpClient)
+	err := ConfigureHTTPClient(httpClient, nil)
 	require.NoError(t, err, "should use SPACELIFT_API_TLS_CA and ignore invalid SSL_CERT_FILE")
 
 	transport := httpClient.Transport.(*http.Transport)
@@ -78,7 +80,7 @@ func TestConfigureTLS_InvalidCAPath(t *testing.T) {
 	t.Setenv(EnvSpaceliftAPIClientCA, "/nonexistent/ca.pem")
 
 	httpClient := &http.Client{}
-	err := configureTLS(httpClient)
+	err := ConfigureHTTPClient(httpClient, nil)
 	assert.Error(t, err)
 }
 
@@ -89,10 +91,57 @@ func TestConfigureTLS_InvalidCACert(t *testing.T) {
 	t.Setenv(EnvSpaceliftAPIClientCA, tmpFile)
 
 	httpClient := &http.Client{}
-	err := configureTLS(httpClient)
+	err := ConfigureHTTPClient(httpClient, nil)
 	assert.ErrorIs(t, err, errEnvSpaceliftAPIClientCAParse)
 }
 
+func TestConfigureHTTPClient_ProfileCA(t *testing.T) {
+	t.Setenv(EnvSpaceliftAPIClientCA, "")
+	t.Setenv("SSL_CERT_FILE", "")
+
+	caPEM, err := os.ReadFile(writeTempCA(t))
+	require.NoError(t, err)
+
+	httpClient := &http.Client{}
+	err = ConfigureHTTPClient(httpClient, &session.NetworkSettings{CACertificate: string(caPEM)})
+	require.NoError(t, err)
+
+	transport := httpClient.Transport.(*http.Transport)
+	assert.NotNil(t, transport.TLSClientConfig.RootCAs, "RootCAs should be set from the profile CA")
+}
+
+func TestConfigureHTTPClient_InvalidProfileCA(t *testing.T) {
+	httpClient := &http.Client{}
+	err := ConfigureHTTPClient(httpClient, &session.NetworkSettings{CACertificate: "not a certificate"})
+	assert.Error(t, err)
+}
+
+func TestConfigureHTTPClient_ProfileProxy(t *testing.T) {
+	httpClient := &http.Client{}
+	err := ConfigureHTTPClient(httpClient, &session.NetworkSettings{
+		ProxyURL: "http://proxy.example.com:3128",
+		NoProxy:  "internal.example.com",
+	})
+	require.NoError(t, err)
+
+	transport := httpClient.Transport.(*http.Transport)
+
+	req, err := http.NewRequest(http.MethodGet, "https://acme.app.spacelift.io/graphql", nil)
+	require.NoError(t, err)
+
+	proxyURL, err := transport.Proxy(req)
+	require.NoError(t, err)
+	require.NotNil(t, proxyURL)
+	assert.Equal(t, "proxy.example.com:3128", proxyURL.Host)
+
+	req, err = http.NewRequest(http.MethodGet, "https://internal.example.com/upload", nil)
+	require.NoError(t, err)
+
+	proxyURL, err = transport.Proxy(req)
+	require.NoError(t, err)
+	assert.Nil(t, proxyURL, "hosts listed in NoProxy should bypass the proxy")
+}
+
 // writeTempCA generates a self-signed CA certificate and writes it to a temp file.
 func writeTempCA(t *testing.T) string {
 	t.Helper()
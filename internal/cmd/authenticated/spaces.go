@@ -0,0 +1,39 @@
+package authenticated
+
+import "context"
+
+// SpaceScope reports whether the entities of a space can be returned to the
+// caller.
+type SpaceScope func(ctx context.Context, spaceID string) (bool, error)
+
+type spaceScopeKey struct{}
+
+// WithSpaceScope returns a context restricting the entities listed with it to
+// the spaces accepted by the scope. The MCP server uses it to hide what is
+// outside of the spaces it is restricted to.
+func WithSpaceScope(ctx context.Context, scope SpaceScope) context.Context {
+	return context.WithValue(ctx, spaceScopeKey{}, scope)
+}
+
+// FilterBySpace returns the items in the space scope of the context, or all of
+// them if the context has no scope.
+func FilterBySpace[T any](ctx context.Context, items []T, space func(T) string) ([]T, error) {
+	scope, ok := ctx.Value(spaceScopeKey{}).(SpaceScope)
+	if !ok {
+		return items, nil
+	}
+
+	filtered := make([]T, 0, len(items))
+	for _, item := range items {
+		inScope, err := scope(ctx, space(item))
+		if err != nil {
+			return nil, err
+		}
+
+		if inScope {
+			filtered = append(filtered, item)
+		}
+	}
+
+	return filtered, nil
+}
@@ -35,6 +35,7 @@ func Command() cmd.Command {
 								cmd.FlagOutputFormat,
 								cmd.FlagNoColor,
 								cmd.FlagLimit,
+								cmd.FlagPageSize,
 								cmd.FlagSearch,
 							},
 							Action: listBlueprints(),
@@ -78,6 +79,9 @@ func Command() cmd.Command {
 							Flags: []cli.Flag{
 								flagRequiredBlueprintID,
 								flagInputFile,
+								flagSet,
+								flagMatrix,
+								flagDryRun,
 								cmd.FlagNoColor,
 							},
 							Action:    (&deployCommand{}).deploy,
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"os"
 	"slices"
 	"strconv"
@@ -14,6 +15,7 @@ import (
 	"github.com/urfave/cli/v3"
 
 	"github.com/spacelift-io/spacectl/internal/cmd/authenticated"
+	"github.com/spacelift-io/spacectl/internal/templateinputs"
 )
 
 type deployCommand struct{}
@@ -30,10 +32,15 @@ func (c *deployCommand) deploy(ctx context.Context, cliCmd *cli.Command) error {
 		return fmt.Errorf("blueprint with ID %q not found", blueprintID)
 	}
 
+	if matrixPath := cliCmd.String(flagMatrix.Name); matrixPath != "" {
+		return c.deployMatrix(ctx, cliCmd, b, matrixPath)
+	}
+
 	var templateInputs []BlueprintStackCreateInputPair
 
-	if filePath := cliCmd.String(flagInputFile.Name); filePath != "" {
-		if templateInputs, err = inputsFromFile(filePath, b.Inputs); err != nil {
+	filePath, overrides := cliCmd.String(flagInputFile.Name), cliCmd.StringSlice(flagSet.Name)
+	if filePath != "" || len(overrides) > 0 {
+		if templateInputs, err = inputsFromFileAndOverrides(filePath, overrides, b.Inputs); err != nil {
 			return err
 		}
 	} else {
@@ -42,24 +49,48 @@ func (c *deployCommand) deploy(ctx context.Context, cliCmd *cli.Command) error {
 		}
 	}
 
+	if cliCmd.Bool(flagDryRun.Name) {
+		return c.printDryRun(b, templateInputs)
+	}
+
+	stackID, err := createStackFromBlueprint(ctx, blueprintID, templateInputs)
+	if err != nil {
+		return err
+	}
+
+	url := authenticated.Client(ctx).URL("/stack/%s", stackID)
+	fmt.Printf("\nCreated stack: %q", url)
+
+	return nil
+}
+
+func createStackFromBlueprint(ctx context.Context, blueprintID string, templateInputs []BlueprintStackCreateInputPair) (string, error) {
 	var mutation struct {
 		BlueprintCreateStack struct {
 			StackID string `graphql:"stackID"`
 		} `graphql:"blueprintCreateStack(id: $id, input: $input)"`
 	}
 
-	err = authenticated.Client().Mutate(ctx, &mutation, map[string]any{
+	err := authenticated.Client(ctx).Mutate(ctx, &mutation, map[string]any{
 		"id": blueprintID, "input": BlueprintStackCreateInput{TemplateInputs: templateInputs},
 	},
 	)
 	if err != nil {
-		return fmt.Errorf("failed to deploy stack from the blueprint: %w", err)
+		return "", fmt.Errorf("failed to deploy stack from the blueprint: %w", err)
 	}
 
-	url := authenticated.Client().URL("/stack/%s", mutation.BlueprintCreateStack.StackID)
-	fmt.Printf("\nCreated stack: %q", url)
+	return mutation.BlueprintCreateStack.StackID, nil
+}
 
-	return nil
+func (c *deployCommand) printDryRun(b blueprint, templateInputs []BlueprintStackCreateInputPair) error {
+	rendered, unresolved := templateinputs.Render(b.RawTemplate, b.Inputs, templateInputs)
+
+	if len(unresolved) > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: the following expressions are evaluated by Spacelift and were left as-is:\n  - %s\n\n", strings.Join(unresolved, "\n  - "))
+	}
+
+	_, err := fmt.Println(rendered)
+	return err
 }
 
 func formatLabel(input blueprintInput) string {
@@ -222,131 +253,44 @@ func promptForBooleanInput(input blueprintInput) (string, error) {
 // in the file and no extra keys are allowed. All errors are aggregated before
 // returning so the user sees the full set of problems at once.
 func inputsFromFile(filePath string, inputs []blueprintInput) ([]BlueprintStackCreateInputPair, error) {
-	data, err := os.ReadFile(filePath)
+	fileInputs, err := templateinputs.ReadFile(filePath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read input file %q: %w", filePath, err)
-	}
-
-	var fileInputs map[string]json.RawMessage
-	if err = json.Unmarshal(data, &fileInputs); err != nil {
-		return nil, fmt.Errorf("failed to parse input file %q as JSON object: %w", filePath, err)
+		return nil, err
 	}
 
-	blueprintInputsByID := make(map[string]blueprintInput, len(inputs))
-	for _, input := range inputs {
-		blueprintInputsByID[input.ID] = input
+	result, errs := templateinputs.Validate(fileInputs, inputs, "blueprint")
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("input file %q validation failed:\n%s", filePath, strings.Join(errs, "\n"))
 	}
 
-	var errs []string
+	return result, nil
+}
 
-	for id := range fileInputs {
-		if _, ok := blueprintInputsByID[id]; !ok {
-			errs = append(errs, fmt.Sprintf("  - extra input %q is not defined in the blueprint", id))
-		}
+// inputsFromFileAndOverrides reads blueprint inputs from an optional file and
+// applies the key=value overrides on top of it before validating them.
+func inputsFromFileAndOverrides(filePath string, overrides []string, inputs []blueprintInput) ([]BlueprintStackCreateInputPair, error) {
+	if len(overrides) == 0 {
+		return inputsFromFile(filePath, inputs)
 	}
 
-	result := make([]BlueprintStackCreateInputPair, 0, len(inputs))
-	for _, input := range inputs {
-		raw, ok := fileInputs[input.ID]
-		if !ok {
-			if input.Default != "" { // If omitted and has a default, use it.
-				raw = []byte(fmt.Sprintf("%q", input.Default))
-			} else {
-				errs = append(errs, fmt.Sprintf("  - missing required input %q (%s)", input.ID, input.Name))
-				continue
-			}
-		}
-
-		value, parseErr := parseFileInputValue(input, raw)
-		if parseErr != nil {
-			errs = append(errs, fmt.Sprintf("  - input %q (%s): %s", input.ID, input.Name, parseErr))
-			continue
+	values := make(map[string]json.RawMessage)
+	if filePath != "" {
+		var err error
+		if values, err = templateinputs.ReadFile(filePath); err != nil {
+			return nil, err
 		}
+	}
 
-		result = append(result, BlueprintStackCreateInputPair{ID: input.ID, Value: value})
+	overrideValues, err := templateinputs.ParseOverrides(overrides)
+	if err != nil {
+		return nil, err
 	}
+	maps.Copy(values, overrideValues)
 
+	result, errs := templateinputs.Validate(values, inputs, "blueprint")
 	if len(errs) > 0 {
-		return nil, fmt.Errorf("input file %q validation failed:\n%s", filePath, strings.Join(errs, "\n"))
+		return nil, fmt.Errorf("inputs validation failed:\n%s", strings.Join(errs, "\n"))
 	}
 
 	return result, nil
 }
-
-// parseFileInputValue converts a raw JSON value to the string representation
-// expected by the blueprint API. Text and select types require a JSON string.
-// Number, float, and boolean accept either their native JSON type or a JSON
-// string that parses to the correct type (e.g. "42", "3.14", "true").
-func parseFileInputValue(input blueprintInput, raw json.RawMessage) (string, error) {
-	switch strings.ToLower(input.Type) {
-	case "", "short_text", "long_text", "secret":
-		var s string
-		if err := json.Unmarshal(raw, &s); err != nil {
-			return "", fmt.Errorf("must be a string")
-		}
-		return s, nil
-
-	case "number":
-		// Accept a JSON integer directly, or a JSON string that holds an integer.
-		// Use *int64 so JSON null yields nil (json.Unmarshal(null, &int64) is a silent no-op).
-		var n *int64
-		if err := json.Unmarshal(raw, &n); err == nil && n != nil {
-			return strconv.FormatInt(*n, 10), nil
-		}
-		var s string
-		if err := json.Unmarshal(raw, &s); err == nil {
-			if v, err := strconv.ParseInt(s, 10, 64); err == nil {
-				return strconv.FormatInt(v, 10), nil
-			}
-		}
-		return "", fmt.Errorf("must be an integer")
-
-	case "float":
-		// Accept a JSON number directly, or a JSON string that holds a float.
-		// Use *float64 so JSON null yields nil.
-		var f *float64
-		if err := json.Unmarshal(raw, &f); err == nil && f != nil {
-			return strconv.FormatFloat(*f, 'f', -1, 64), nil
-		}
-		var s string
-		if err := json.Unmarshal(raw, &s); err == nil {
-			if v, err := strconv.ParseFloat(s, 64); err == nil {
-				return strconv.FormatFloat(v, 'f', -1, 64), nil
-			}
-		}
-		return "", fmt.Errorf("must be a float")
-
-	case "boolean":
-		// Accept a JSON boolean directly, or a JSON string that holds a boolean.
-		// Use *bool so JSON null yields nil instead of the silent no-op that
-		// json.Unmarshal(null, &bool) produces.
-		var b *bool
-		if err := json.Unmarshal(raw, &b); err == nil && b != nil {
-			return strconv.FormatBool(*b), nil
-		}
-		var s string
-		if err := json.Unmarshal(raw, &s); err == nil {
-			if v, err := strconv.ParseBool(s); err == nil {
-				return strconv.FormatBool(v), nil
-			}
-		}
-		return "", fmt.Errorf("must be a boolean")
-
-	case "select":
-		var s string
-		if err := json.Unmarshal(raw, &s); err != nil {
-			return "", fmt.Errorf("must be a string")
-		}
-		if !slices.Contains(input.Options, s) {
-			return "", fmt.Errorf("must be one of [%s], got %q", strings.Join(input.Options, ", "), s)
-		}
-		return s, nil
-
-	default:
-		var s string
-		if err := json.Unmarshal(raw, &s); err != nil {
-			return "", fmt.Errorf("must be a string")
-		}
-		return s, nil
-	}
-}
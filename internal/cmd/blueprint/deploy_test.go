@@ -466,3 +466,82 @@ func TestInputsFromFile_errors(t *testing.T) {
 		})
 	}
 }
+
+func TestInputsFromFile_yaml(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "inputs.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("env: production\ncount: 3\nenabled: true\n"), 0600))
+
+	got, err := inputsFromFile(path, []blueprintInput{
+		{ID: "env", Name: "Environment", Type: "short_text"},
+		{ID: "count", Name: "Count", Type: "number"},
+		{ID: "enabled", Name: "Enabled", Type: "boolean"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []BlueprintStackCreateInputPair{
+		{ID: "env", Value: "production"},
+		{ID: "count", Value: "3"},
+		{ID: "enabled", Value: "true"},
+	}, got)
+}
+
+func TestInputsFromFileAndOverrides(t *testing.T) {
+	inputs := []blueprintInput{
+		{ID: "env", Name: "Environment", Type: "short_text"},
+		{ID: "count", Name: "Count", Type: "number"},
+		{ID: "region", Name: "Region", Type: "select", Options: []string{"us-east-1", "eu-west-1"}},
+	}
+
+	t.Run("overrides take precedence over the file", func(t *testing.T) {
+		filePath := writeTempInputFile(t, `{"env": "staging", "count": 1, "region": "us-east-1"}`)
+
+		got, err := inputsFromFileAndOverrides(filePath, []string{"env=prod", "count=5"}, inputs)
+		require.NoError(t, err)
+		assert.Equal(t, []BlueprintStackCreateInputPair{
+			{ID: "env", Value: "prod"},
+			{ID: "count", Value: "5"},
+			{ID: "region", Value: "us-east-1"},
+		}, got)
+	})
+
+	t.Run("overrides without a file", func(t *testing.T) {
+		got, err := inputsFromFileAndOverrides("", []string{"env=prod", "count=5", "region=eu-west-1"}, inputs)
+		require.NoError(t, err)
+		assert.Equal(t, []BlueprintStackCreateInputPair{
+			{ID: "env", Value: "prod"},
+			{ID: "count", Value: "5"},
+			{ID: "region", Value: "eu-west-1"},
+		}, got)
+	})
+
+	t.Run("overrides on top of a null file", func(t *testing.T) {
+		filePath := writeTempInputFile(t, "null")
+
+		got, err := inputsFromFileAndOverrides(filePath, []string{"env=prod", "count=5", "region=eu-west-1"}, inputs)
+		require.NoError(t, err)
+		assert.Equal(t, []BlueprintStackCreateInputPair{
+			{ID: "env", Value: "prod"},
+			{ID: "count", Value: "5"},
+			{ID: "region", Value: "eu-west-1"},
+		}, got)
+	})
+
+	t.Run("values containing equals signs", func(t *testing.T) {
+		got, err := inputsFromFileAndOverrides("", []string{"env=a=b", "count=1", "region=eu-west-1"}, inputs)
+		require.NoError(t, err)
+		assert.Equal(t, "a=b", got[0].Value)
+	})
+
+	t.Run("malformed override", func(t *testing.T) {
+		_, err := inputsFromFileAndOverrides("", []string{"env"}, inputs)
+		assert.ErrorContains(t, err, `invalid --set value "env", expected KEY=VALUE`)
+	})
+
+	t.Run("validation errors are aggregated", func(t *testing.T) {
+		_, err := inputsFromFileAndOverrides("", []string{"count=many", "region=mars", "ghost=1"}, inputs)
+		require.Error(t, err)
+		assert.ErrorContains(t, err, `missing required input "env" (Environment)`)
+		assert.ErrorContains(t, err, `input "count" (Count): must be an integer`)
+		assert.ErrorContains(t, err, `input "region" (Region): must be one of [us-east-1, eu-west-1], got "mars"`)
+		assert.ErrorContains(t, err, `extra input "ghost" is not defined in the blueprint`)
+	})
+}
@@ -12,5 +12,21 @@ var flagRequiredBlueprintID = &cli.StringFlag{
 var flagInputFile = &cli.StringFlag{
 	Name:    "input-file",
 	Aliases: []string{"if"},
-	Usage:   "[Optional] Load blueprint options from the JSON `FILE`",
+	Usage:   "[Optional] Load blueprint options from the JSON or YAML `FILE`",
+}
+
+var flagSet = &cli.StringSliceFlag{
+	Name:  "set",
+	Usage: "[Optional] Set a blueprint input, overriding the input file, example: --set environment=prod --set region=eu-west-1",
+}
+
+var flagDryRun = &cli.BoolFlag{
+	Name:  "dry-run",
+	Usage: "[Optional] Validate the inputs and print the rendered template without creating the stack",
+}
+
+var flagMatrix = &cli.StringFlag{
+	Name:      "matrix",
+	Usage:     "[Optional] Create one stack per row of the JSON or YAML `FILE`, containing a list of input objects",
+	TakesFile: true,
 }
@@ -1,10 +1,9 @@
 package blueprint
 
+import "github.com/spacelift-io/spacectl/internal/templateinputs"
+
 // BlueprintStackCreateInputPair represents a key-value pair for a blueprint input.
-type BlueprintStackCreateInputPair struct {
-	ID    string `json:"id"`
-	Value string `json:"value"`
-}
+type BlueprintStackCreateInputPair = templateinputs.Pair
 
 // BlueprintStackCreateInput represents the input for creating a new stack from a blueprint.
 type BlueprintStackCreateInput struct {
@@ -2,14 +2,14 @@ package blueprint
 
 import (
 	"context"
-	"fmt"
-	"slices"
+	"iter"
 	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/shurcooL/graphql"
 	"github.com/urfave/cli/v3"
 
+	"github.com/spacelift-io/spacectl/client"
 	"github.com/spacelift-io/spacectl/client/structs"
 	"github.com/spacelift-io/spacectl/internal/cmd"
 	"github.com/spacelift-io/spacectl/internal/cmd/authenticated"
@@ -17,95 +17,47 @@ import (
 
 func listBlueprints() cli.ActionFunc {
 	return func(ctx context.Context, cliCmd *cli.Command) error {
-		outputFormat, err := cmd.GetOutputFormat(cliCmd)
+		outputFormat, formatter, err := cmd.GetOutputFormat(cliCmd)
 		if err != nil {
 			return err
 		}
 
-		var limit *uint
-		if cliCmd.IsSet(cmd.FlagLimit.Name) {
-			limit = new(cliCmd.Uint(cmd.FlagLimit.Name))
+		opts, err := cmd.GetPaginateOptions(cliCmd)
+		if err != nil {
+			return err
 		}
 
-		var search *string
+		var input structs.SearchInput
 		if cliCmd.IsSet(cmd.FlagSearch.Name) {
-			search = new(cliCmd.String(cmd.FlagSearch.Name))
+			input.FullTextSearch = new(graphql.String(cliCmd.String(cmd.FlagSearch.Name)))
 		}
 
 		switch outputFormat {
 		case cmd.OutputFormatTable:
-			return listBlueprintsTable(ctx, cliCmd, search, limit)
-		case cmd.OutputFormatJSON:
-			return listBlueprintsJSON(ctx, search, limit)
+			input.OrderBy = &structs.QueryOrder{
+				Field:     "name",
+				Direction: "DESC",
+			}
+
+			return listBlueprintsTable(cliCmd, paginateBlueprints(ctx, input, opts))
+		default:
+			return cmd.OutputStream(outputFormat, formatter, paginateBlueprints(ctx, input, opts))
 		}
-
-		return fmt.Errorf("unknown output format: %v", outputFormat)
 	}
 }
 
-func listBlueprintsJSON(
-	ctx context.Context,
-	search *string,
-	limit *uint,
-) error {
-	var first *graphql.Int
-	if limit != nil {
-		first = new(graphql.Int(*limit)) //nolint: gosec
-	}
-
-	var fullTextSearch *graphql.String
-	if search != nil {
-		fullTextSearch = new(graphql.String(*search))
-	}
-
-	blueprints, err := searchAllBlueprints(ctx, structs.SearchInput{
-		First:          first,
-		FullTextSearch: fullTextSearch,
-	})
-	if err != nil {
-		return err
-	}
-
-	return cmd.OutputJSON(blueprints)
-}
-
-func listBlueprintsTable(
-	ctx context.Context,
-	cliCmd *cli.Command,
-	search *string,
-	limit *uint,
-) error {
-	var first *graphql.Int
-	if limit != nil {
-		first = new(graphql.Int(*limit)) //nolint: gosec
-	}
-
-	var fullTextSearch *graphql.String
-	if search != nil {
-		fullTextSearch = new(graphql.String(*search))
-	}
-
-	input := structs.SearchInput{
-		First:          first,
-		FullTextSearch: fullTextSearch,
-		OrderBy: &structs.QueryOrder{
-			Field:     "name",
-			Direction: "DESC",
-		},
-	}
-
-	blueprints, err := searchAllBlueprints(ctx, input)
-	if err != nil {
-		return err
-	}
-
+func listBlueprintsTable(cliCmd *cli.Command, found iter.Seq2[blueprintNode, error]) error {
 	columns := []string{"Name", "ID", "Description", "State", "Space", "Updated At"}
 	if cliCmd.Bool(cmd.FlagShowLabels.Name) {
 		columns = append(columns, "Labels")
 	}
 
 	tableData := [][]string{columns}
-	for _, b := range blueprints {
+	for b, err := range found {
+		if err != nil {
+			return err
+		}
+
 		row := []string{
 			b.Name,
 			b.ID,
@@ -124,48 +76,13 @@ func listBlueprintsTable(
 	return cmd.OutputTable(tableData, true)
 }
 
-// searchAllBlueprints returns a list of stacks based on the provided search input.
-// input.First limits the total number of returned stacks, if not provided all stacks are returned.
-func searchAllBlueprints(ctx context.Context, input structs.SearchInput) ([]blueprintNode, error) {
-	const maxPageSize = 50
-
-	var limit int
-	if input.First != nil {
-		limit = int(*input.First)
-	}
-	fetchAll := limit == 0
-
-	out := []blueprintNode{}
-	pageInput := structs.SearchInput{
-		First:          graphql.NewInt(maxPageSize),
-		FullTextSearch: input.FullTextSearch,
-	}
-	for {
-		if !fetchAll {
-			// Fetch exactly the number of items requested
-			pageInput.First = new(
-				//nolint: gosec
-				graphql.Int(
-					slices.Min([]int{maxPageSize, limit - len(out)}),
-				),
-			)
-		}
-
-		result, err := searchBlueprints(ctx, pageInput)
-		if err != nil {
-			return nil, err
-		}
-
-		out = append(out, result.Blueprints...)
-
-		if result.PageInfo.HasNextPage && (fetchAll || limit > len(out)) {
-			pageInput.After = new(graphql.String(result.PageInfo.EndCursor))
-		} else {
-			break
-		}
-	}
-
-	return out, nil
+// paginateBlueprints iterates over the blueprints matching the input,
+// fetching pages as they are consumed.
+func paginateBlueprints(ctx context.Context, input structs.SearchInput, opts client.PaginateOptions) iter.Seq2[blueprintNode, error] {
+	return client.Paginate(ctx, input, opts, func(ctx context.Context, input structs.SearchInput) (client.Page[blueprintNode], error) {
+		result, err := searchBlueprints(ctx, input)
+		return client.Page[blueprintNode]{Items: result.Blueprints, PageInfo: result.PageInfo}, err
+	})
 }
 
 type blueprintNode struct {
@@ -206,7 +123,7 @@ func searchBlueprints(ctx context.Context, input structs.SearchInput) (searchBlu
 		} `graphql:"searchBlueprints(input: $input)"`
 	}
 
-	if err := authenticated.Client().Query(
+	if err := authenticated.Client(ctx).Query(
 		ctx,
 		&query,
 		map[string]any{"input": input},
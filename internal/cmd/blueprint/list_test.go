@@ -0,0 +1,40 @@
+package blueprint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacelift-io/spacectl/client"
+	"github.com/spacelift-io/spacectl/client/fakeapi"
+	"github.com/spacelift-io/spacectl/client/structs"
+	"github.com/spacelift-io/spacectl/internal/cmd/authenticated"
+)
+
+func TestPaginateBlueprints(t *testing.T) {
+	server, err := fakeapi.NewServer(fakeapi.Fixtures{
+		Viewer: fakeapi.Object{"id": "fake-user"},
+		Blueprints: []fakeapi.Object{
+			{"id": "network", "name": "network", "space": fakeapi.Object{"id": "root", "name": "root"}},
+			{"id": "database", "name": "database", "space": fakeapi.Object{"id": "root", "name": "root"}},
+			{"id": "cluster", "name": "cluster", "space": fakeapi.Object{"id": "root", "name": "root"}},
+		},
+	})
+	require.NoError(t, err)
+	t.Cleanup(server.Close)
+
+	ctx := authenticated.WithClient(t.Context(), server.Client())
+
+	ids := func(opts client.PaginateOptions) []string {
+		var out []string
+		for b, err := range paginateBlueprints(ctx, structs.SearchInput{}, opts) {
+			require.NoError(t, err)
+			out = append(out, b.ID)
+		}
+		return out
+	}
+
+	assert.Equal(t, []string{"network", "database", "cluster"}, ids(client.PaginateOptions{PageSize: 1}))
+	assert.Equal(t, []string{"network", "database"}, ids(client.PaginateOptions{PageSize: 1, Limit: 2}))
+}
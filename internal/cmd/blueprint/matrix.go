@@ -0,0 +1,281 @@
+package blueprint
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/shurcooL/graphql"
+	"github.com/urfave/cli/v3"
+	"gopkg.in/yaml.v3"
+
+	"github.com/spacelift-io/spacectl/client/structs"
+	"github.com/spacelift-io/spacectl/internal/cmd"
+	"github.com/spacelift-io/spacectl/internal/cmd/authenticated"
+	"github.com/spacelift-io/spacectl/internal/templateinputs"
+)
+
+const (
+	matrixRowCreated = "CREATED"
+	matrixRowSkipped = "SKIPPED"
+	matrixRowPlanned = "PLANNED"
+	matrixRowFailed  = "FAILED"
+)
+
+// unresolvedExpressionPlaceholder replaces the expressions only Spacelift can
+// evaluate, so that the rendered template remains parseable YAML.
+const unresolvedExpressionPlaceholder = "__spacelift_expression__"
+
+// matrixRowResult is the outcome of deploying a single row of the matrix file.
+type matrixRowResult struct {
+	Row     int    `json:"row"`
+	Status  string `json:"status"`
+	Name    string `json:"name,omitempty"`
+	Space   string `json:"space,omitempty"`
+	StackID string `json:"stackId,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// deployMatrix creates one stack per row of the matrix file. Rows inherit the
+// values from --input-file, and --set overrides apply to every row. Stacks which
+// already exist with the same name in the same space are skipped, so the
+// command can safely be re-run after a partial failure.
+func (c *deployCommand) deployMatrix(ctx context.Context, cliCmd *cli.Command, b blueprint, matrixPath string) error {
+	rows, err := readMatrixFile(matrixPath)
+	if err != nil {
+		return err
+	}
+
+	base := make(map[string]json.RawMessage)
+	if filePath := cliCmd.String(flagInputFile.Name); filePath != "" {
+		if base, err = templateinputs.ReadFile(filePath); err != nil {
+			return err
+		}
+	}
+
+	overrides, err := templateinputs.ParseOverrides(cliCmd.StringSlice(flagSet.Name))
+	if err != nil {
+		return err
+	}
+
+	dryRun := cliCmd.Bool(flagDryRun.Name)
+
+	results := make([]matrixRowResult, 0, len(rows))
+	var failed int
+
+	for i, row := range rows {
+		values := make(map[string]json.RawMessage, len(base)+len(row)+len(overrides))
+		maps.Copy(values, base)
+		maps.Copy(values, row)
+		maps.Copy(values, overrides)
+
+		result := deployMatrixRow(ctx, b, i+1, values, dryRun)
+		if result.Status == matrixRowFailed {
+			failed++
+			fmt.Fprintf(os.Stderr, "Row %d: %s: %s\n", result.Row, result.Status, result.Error)
+		} else {
+			fmt.Fprintf(os.Stderr, "Row %d: %s: %s\n", result.Row, result.Status, result.Name)
+		}
+
+		results = append(results, result)
+	}
+
+	if err := cmd.OutputJSON(results); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d matrix rows failed", failed, len(rows))
+	}
+
+	return nil
+}
+
+func deployMatrixRow(ctx context.Context, b blueprint, row int, values map[string]json.RawMessage, dryRun bool) matrixRowResult {
+	result := matrixRowResult{Row: row}
+
+	fail := func(err error) matrixRowResult {
+		result.Status = matrixRowFailed
+		result.Error = err.Error()
+		return result
+	}
+
+	templateInputs, errs := templateinputs.Validate(values, b.Inputs, "blueprint")
+	if len(errs) > 0 {
+		return fail(fmt.Errorf("inputs validation failed: %s", strings.Join(trimErrorLines(errs), "; ")))
+	}
+
+	var err error
+	if result.Name, result.Space, err = stackIdentity(b, templateInputs); err != nil {
+		return fail(err)
+	}
+
+	existingID, found, err := findStack(ctx, result.Name, result.Space)
+	if err != nil {
+		return fail(err)
+	}
+
+	if found {
+		result.Status = matrixRowSkipped
+		result.StackID = existingID
+		return result
+	}
+
+	if dryRun {
+		result.Status = matrixRowPlanned
+		return result
+	}
+
+	if result.StackID, err = createStackFromBlueprint(ctx, b.ID, templateInputs); err != nil {
+		return fail(err)
+	}
+
+	result.Status = matrixRowCreated
+	return result
+}
+
+// readMatrixFile reads a list of input objects from a JSON or YAML file.
+func readMatrixFile(filePath string) ([]map[string]json.RawMessage, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read matrix file %q: %w", filePath, err)
+	}
+
+	var rows []map[string]any
+
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &rows)
+	default:
+		err = json.Unmarshal(data, &rows)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse matrix file %q as a list of objects: %w", filePath, err)
+	}
+
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("matrix file %q contains no rows", filePath)
+	}
+
+	out := make([]map[string]json.RawMessage, 0, len(rows))
+	for i, row := range rows {
+		values := make(map[string]json.RawMessage, len(row))
+		for id, value := range row {
+			raw, err := json.Marshal(value)
+			if err != nil {
+				return nil, fmt.Errorf("failed to convert input %q in row %d of matrix file %q: %w", id, i+1, filePath, err)
+			}
+			values[id] = raw
+		}
+		out = append(out, values)
+	}
+
+	return out, nil
+}
+
+// stackIdentity renders the blueprint template locally to find the name and
+// space of the stack it would create.
+func stackIdentity(b blueprint, templateInputs []BlueprintStackCreateInputPair) (string, string, error) {
+	rendered, _ := templateinputs.Render(b.RawTemplate, b.Inputs, templateInputs)
+	rendered = templateinputs.ReplaceExpressions(rendered, unresolvedExpressionPlaceholder)
+
+	var spec struct {
+		Stack struct {
+			Name  string `yaml:"name"`
+			Space string `yaml:"space"`
+		} `yaml:"stack"`
+	}
+
+	if err := yaml.Unmarshal([]byte(rendered), &spec); err != nil {
+		return "", "", fmt.Errorf("failed to parse the rendered blueprint template: %w", err)
+	}
+
+	name, space := spec.Stack.Name, spec.Stack.Space
+	if space == "" {
+		space = defaultStackSpace
+	}
+
+	if name == "" {
+		return "", "", errors.New("the rendered blueprint template does not define stack.name")
+	}
+
+	if strings.Contains(name, unresolvedExpressionPlaceholder) || strings.Contains(space, unresolvedExpressionPlaceholder) {
+		return "", "", errors.New("stack.name and stack.space must only depend on inputs to check whether the stack already exists")
+	}
+
+	return name, space, nil
+}
+
+// defaultStackSpace is the space of the stacks created from blueprints that
+// do not set stack.space.
+const defaultStackSpace = "root"
+
+// findStack looks for a stack with exactly the given name in the space. It
+// fails if the name is ambiguous, as any of the stacks could be reported.
+func findStack(ctx context.Context, name, space string) (string, bool, error) {
+	input := structs.SearchInput{
+		First: graphql.NewInt(50),
+		Predicates: &[]structs.QueryPredicate{
+			{
+				Field: "name",
+				Constraint: structs.QueryFieldConstraint{
+					StringMatches: &[]graphql.String{graphql.String(name)},
+				},
+			},
+		},
+	}
+
+	var ids []string
+	for {
+		var query struct {
+			SearchStacksOutput struct {
+				Edges []struct {
+					Node struct {
+						ID    string `graphql:"id"`
+						Name  string `graphql:"name"`
+						Space string `graphql:"space"`
+					} `graphql:"node"`
+				} `graphql:"edges"`
+				PageInfo structs.PageInfo `graphql:"pageInfo"`
+			} `graphql:"searchStacks(input: $input)"`
+		}
+
+		if err := authenticated.Client(ctx).Query(ctx, &query, map[string]any{"input": input}); err != nil {
+			return "", false, errors.Wrapf(err, "failed to search for stack %q", name)
+		}
+
+		for _, edge := range query.SearchStacksOutput.Edges {
+			if edge.Node.Name == name && edge.Node.Space == space {
+				ids = append(ids, edge.Node.ID)
+			}
+		}
+
+		if !query.SearchStacksOutput.PageInfo.HasNextPage {
+			break
+		}
+
+		input.After = graphql.NewString(graphql.String(query.SearchStacksOutput.PageInfo.EndCursor))
+	}
+
+	switch len(ids) {
+	case 0:
+		return "", false, nil
+	case 1:
+		return ids[0], true, nil
+	default:
+		return "", false, fmt.Errorf("found %d stacks named %q in space %q (%s), cannot tell which one the row refers to", len(ids), name, space, strings.Join(ids, ", "))
+	}
+}
+
+func trimErrorLines(errs []string) []string {
+	out := make([]string, 0, len(errs))
+	for _, e := range errs {
+		out = append(out, strings.TrimPrefix(e, "  - "))
+	}
+	return out
+}
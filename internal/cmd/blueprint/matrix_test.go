@@ -0,0 +1,122 @@
+package blueprint
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacelift-io/spacectl/client/fakeapi"
+	"github.com/spacelift-io/spacectl/internal/cmd/authenticated"
+)
+
+func TestReadMatrixFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stacks.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("- env: prod\n  count: 2\n- env: dev\n  count: 1\n"), 0600))
+
+	rows, err := readMatrixFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]json.RawMessage{
+		{"env": json.RawMessage(`"prod"`), "count": json.RawMessage(`2`)},
+		{"env": json.RawMessage(`"dev"`), "count": json.RawMessage(`1`)},
+	}, rows)
+}
+
+func TestReadMatrixFile_errors(t *testing.T) {
+	dir := t.TempDir()
+
+	notAList := filepath.Join(dir, "stacks.json")
+	require.NoError(t, os.WriteFile(notAList, []byte(`{"env": "prod"}`), 0600))
+
+	_, err := readMatrixFile(notAList)
+	assert.ErrorContains(t, err, "as a list of objects")
+
+	empty := filepath.Join(dir, "empty.json")
+	require.NoError(t, os.WriteFile(empty, []byte(`[]`), 0600))
+
+	_, err = readMatrixFile(empty)
+	assert.ErrorContains(t, err, "contains no rows")
+}
+
+func TestStackIdentity(t *testing.T) {
+	inputs := []blueprintInput{{ID: "env", Type: "short_text"}}
+	values := []BlueprintStackCreateInputPair{{ID: "env", Value: "prod"}}
+
+	t.Run("name and space from inputs", func(t *testing.T) {
+		b := blueprint{
+			Inputs: inputs,
+			RawTemplate: `stack:
+  name: network-${{ inputs.env }}
+  space: ${{ inputs.env }}-space
+  description: ${{ inputs.env == 'prod' ? 'Production: careful' : 'Other' }}
+`,
+		}
+
+		name, space, err := stackIdentity(b, values)
+		require.NoError(t, err)
+		assert.Equal(t, "network-prod", name)
+		assert.Equal(t, "prod-space", space)
+	})
+
+	t.Run("name depending on Spacelift expressions", func(t *testing.T) {
+		b := blueprint{
+			Inputs:      inputs,
+			RawTemplate: "stack:\n  name: network-${{ context.random_string(4) }}\n  space: root\n",
+		}
+
+		_, _, err := stackIdentity(b, values)
+		assert.ErrorContains(t, err, "must only depend on inputs")
+	})
+
+	t.Run("root space by default", func(t *testing.T) {
+		b := blueprint{Inputs: inputs, RawTemplate: "stack:\n  name: network-${{ inputs.env }}\n"}
+
+		name, space, err := stackIdentity(b, values)
+		require.NoError(t, err)
+		assert.Equal(t, "network-prod", name)
+		assert.Equal(t, "root", space)
+	})
+
+	t.Run("missing name", func(t *testing.T) {
+		b := blueprint{Inputs: inputs, RawTemplate: "stack:\n  space: root\n"}
+
+		_, _, err := stackIdentity(b, values)
+		assert.ErrorContains(t, err, "does not define stack.name")
+	})
+}
+
+func TestFindStack(t *testing.T) {
+	server, err := fakeapi.NewServer(fakeapi.Fixtures{
+		Viewer: fakeapi.Object{"id": "fake-user"},
+		Stacks: []fakeapi.Object{
+			{"id": "network-root", "name": "network", "space": "root"},
+			{"id": "network-prod", "name": "network", "space": "prod"},
+			{"id": "network-dev", "name": "network", "space": "dev"},
+			{"id": "network-dev-2", "name": "network", "space": "dev"},
+		},
+	})
+	require.NoError(t, err)
+	t.Cleanup(server.Close)
+
+	ctx := authenticated.WithClient(t.Context(), server.Client())
+
+	id, found, err := findStack(ctx, "network", "prod")
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "network-prod", id)
+
+	id, found, err = findStack(ctx, "network", "root")
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "network-root", id)
+
+	_, found, err = findStack(ctx, "network", "staging")
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	_, _, err = findStack(ctx, "network", "dev")
+	assert.ErrorContains(t, err, `found 2 stacks named "network" in space "dev"`)
+}
@@ -56,6 +56,11 @@ func registerListBlueprintsTool(s *server.MCPServer) {
 			return nil, errors.Wrap(err, "failed to search blueprints")
 		}
 
+		result.Blueprints, err = authenticated.FilterBySpace(ctx, result.Blueprints, func(b blueprintNode) string { return b.Space.ID })
+		if err != nil {
+			return nil, err
+		}
+
 		blueprintsJSON, err := json.Marshal(result.Blueprints)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to marshal blueprints to JSON")
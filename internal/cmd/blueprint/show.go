@@ -12,16 +12,10 @@ import (
 
 	"github.com/spacelift-io/spacectl/internal/cmd"
 	"github.com/spacelift-io/spacectl/internal/cmd/authenticated"
+	"github.com/spacelift-io/spacectl/internal/templateinputs"
 )
 
-type blueprintInput struct {
-	ID          string   `graphql:"id" json:"id,omitempty"`
-	Name        string   `graphql:"name" json:"name,omitempty"`
-	Default     string   `graphql:"default" json:"default,omitempty"`
-	Description string   `graphql:"description" json:"description,omitempty"`
-	Options     []string `graphql:"options" json:"options,omitempty"`
-	Type        string   `graphql:"type" json:"type,omitempty"`
-}
+type blueprintInput = templateinputs.Definition
 
 type blueprint struct {
 	ID          string `graphql:"id" json:"id,omitempty"`
@@ -46,7 +40,7 @@ type showCommand struct{}
 func (c *showCommand) show(ctx context.Context, cliCmd *cli.Command) error {
 	blueprintID := cliCmd.String(flagRequiredBlueprintID.Name)
 
-	outputFormat, err := cmd.GetOutputFormat(cliCmd)
+	outputFormat, formatter, err := cmd.GetOutputFormat(cliCmd)
 	if err != nil {
 		return err
 	}
@@ -63,11 +57,9 @@ func (c *showCommand) show(ctx context.Context, cliCmd *cli.Command) error {
 	switch outputFormat {
 	case cmd.OutputFormatTable:
 		return c.showBlueprintTable(b)
-	case cmd.OutputFormatJSON:
-		return cmd.OutputJSON(b)
+	default:
+		return cmd.Output(formatter, b)
 	}
-
-	return fmt.Errorf("unknown output format: %v", outputFormat)
 }
 
 func (c *showCommand) showBlueprintTable(b blueprint) error {
@@ -164,7 +156,7 @@ func getBlueprintByID(ctx context.Context, blueprintID string) (blueprint, bool,
 		"blueprintId": graphql.ID(blueprintID),
 	}
 
-	if err := authenticated.Client().Query(ctx, &query, variables); err != nil {
+	if err := authenticated.Client(ctx).Query(ctx, &query, variables); err != nil {
 		return blueprint{}, false, errors.Wrapf(err, "failed to query for blueprint ID %q", blueprintID)
 	}
 
@@ -84,7 +84,7 @@ func searchContexts(ctx context.Context, input structs.SearchInput) (*searchCont
 
 	variables := map[string]any{"input": input}
 
-	if err := authenticated.Client().Query(ctx, &query, variables); err != nil {
+	if err := authenticated.Client(ctx).Query(ctx, &query, variables); err != nil {
 		return nil, errors.Wrap(err, "failed to execute contexts search query")
 	}
 
@@ -136,6 +136,11 @@ func registerListContextsTool(s *server.MCPServer) {
 			return nil, errors.Wrap(err, "failed to search contexts")
 		}
 
+		result.Contexts, err = authenticated.FilterBySpace(ctx, result.Contexts, func(c contextNode) string { return c.Space.ID })
+		if err != nil {
+			return nil, err
+		}
+
 		contextsJSON, err := json.Marshal(result.Contexts)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to marshal contexts to JSON")
@@ -184,7 +189,7 @@ func registerGetContextTool(s *server.MCPServer) {
 			"contextId": graphql.ID(contextID),
 		}
 
-		if err := authenticated.Client().Query(ctx, &query, variables); err != nil {
+		if err := authenticated.Client(ctx).Query(ctx, &query, variables); err != nil {
 			return nil, errors.Wrapf(err, "failed to query for context ID %q", contextID)
 		}
 
@@ -265,6 +270,11 @@ func registerSearchContextsTool(s *server.MCPServer) {
 			return nil, errors.Wrap(err, "failed to search contexts")
 		}
 
+		result.Contexts, err = authenticated.FilterBySpace(ctx, result.Contexts, func(c contextNode) string { return c.Space.ID })
+		if err != nil {
+			return nil, err
+		}
+
 		contextsJSON, err := json.Marshal(result.Contexts)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to marshal contexts to JSON")
@@ -0,0 +1,91 @@
+package dev
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/spacelift-io/spacectl/client/fakeapi"
+	"github.com/spacelift-io/spacectl/internal/cmd"
+)
+
+// Command returns the dev command subtree.
+func Command() cmd.Command {
+	return cmd.Command{
+		Name:  "dev",
+		Usage: "Tools for developing against spacectl",
+		Versions: []cmd.VersionedCommand{
+			{
+				EarliestVersion: cmd.SupportedVersionAll,
+				Command:         &cli.Command{},
+			},
+		},
+		Subcommands: []cmd.Command{
+			{
+				Name:  "mock-server",
+				Usage: "Serve a fake Spacelift API for demos and tests",
+				Versions: []cmd.VersionedCommand{
+					{
+						EarliestVersion: cmd.SupportedVersionAll,
+						Command: &cli.Command{
+							Flags:     []cli.Flag{flagListen, flagFixtures},
+							ArgsUsage: cmd.EmptyArgsUsage,
+							Action:    mockServer,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func mockServer(ctx context.Context, cliCmd *cli.Command) error {
+	fixtures := fakeapi.DefaultFixtures()
+	if path := cliCmd.String(flagFixtures.Name); path != "" {
+		var err error
+		if fixtures, err = fakeapi.LoadFixtures(path); err != nil {
+			return err
+		}
+	}
+
+	api, err := fakeapi.New(fixtures)
+	if err != nil {
+		return err
+	}
+
+	addr := cliCmd.String(flagListen.Name)
+	httpServer := &http.Server{
+		Addr:              addr,
+		Handler:           api,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if err := httpServer.Shutdown(shutdownCtx); err != nil {
+			log.Printf("failed to shut down the mock server: %v", err)
+		}
+	}()
+
+	log.Printf("Serving a fake Spacelift API on http://%s", addr)
+	log.Printf("Use it with SPACELIFT_API_KEY_ENDPOINT=http://%s SPACELIFT_API_KEY_ID=fake SPACELIFT_API_KEY_SECRET=fake", addr)
+
+	if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
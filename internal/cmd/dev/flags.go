@@ -0,0 +1,15 @@
+package dev
+
+import "github.com/urfave/cli/v3"
+
+var flagListen = &cli.StringFlag{
+	Name:  "listen",
+	Usage: "[Optional] `ADDRESS` to serve the fake API on",
+	Value: "localhost:8081",
+}
+
+var flagFixtures = &cli.StringFlag{
+	Name:      "fixtures",
+	Usage:     "[Optional] JSON `FILE` with the stacks, runs, policies and modules to serve, instead of the built-in ones",
+	TakesFile: true,
+}
@@ -27,6 +27,12 @@ var FlagLimit = &cli.UintFlag{
 	Usage: "[Optional] Limit the number of items to return",
 }
 
+// FlagPageSize is a flag used for setting the number of items fetched per request.
+var FlagPageSize = &cli.UintFlag{
+	Name:  "page-size",
+	Usage: "[Optional] Number of items fetched per request, between 1 and 50",
+}
+
 // FlagSearch is a flag used for performing a full-text search.
 var FlagSearch = &cli.StringFlag{
 	Name:  "search",
@@ -121,7 +121,7 @@ func registerIntrospectSchemaTool(s *server.MCPServer) {
 			} `graphql:"__schema"`
 		}
 
-		if err := authenticated.Client().Query(ctx, &query, map[string]any{}); err != nil {
+		if err := authenticated.Client(ctx).Query(ctx, &query, map[string]any{}); err != nil {
 			return nil, errors.Wrap(err, "failed to introspect GraphQL schema")
 		}
 
@@ -193,7 +193,7 @@ func registerGetTypeDetailsTool(s *server.MCPServer) {
 			} `graphql:"__type(name: $name)"`
 		}
 
-		if err := authenticated.Client().Query(ctx, &query, map[string]any{"name": graphql.String(typeName)}); err != nil {
+		if err := authenticated.Client(ctx).Query(ctx, &query, map[string]any{"name": graphql.String(typeName)}); err != nil {
 			return nil, errors.Wrap(err, "failed to get type details")
 		}
 
@@ -274,7 +274,7 @@ func registerSearchSchemaFieldsTool(s *server.MCPServer) {
 			} `graphql:"__schema"`
 		}
 
-		if err := authenticated.Client().Query(ctx, &query, map[string]any{}); err != nil {
+		if err := authenticated.Client(ctx).Query(ctx, &query, map[string]any{}); err != nil {
 			return nil, errors.Wrap(err, "failed to introspect GraphQL schema")
 		}
 
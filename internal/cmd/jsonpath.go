@@ -0,0 +1,219 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// jsonPath is a kubectl-style JSONPath template: text with expressions in
+// braces, for example "{.items[*].name}" or "{.id}{\"\t\"}{.state}". It
+// supports field access (.name or ['name']), list indexes ([0], [-1]),
+// wildcards ([*] or .*) and quoted string literals. An expression without
+// braces is treated as a single expression.
+type jsonPath struct {
+	segments []jsonPathSegment
+}
+
+type jsonPathSegment struct {
+	literal string
+	steps   []jsonPathStep
+	isExpr  bool
+}
+
+type jsonPathStep struct {
+	field    string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+func parseJSONPath(expression string) (jsonPath, error) {
+	if !strings.Contains(expression, "{") {
+		expression = "{" + expression + "}"
+	}
+
+	var path jsonPath
+
+	for rest := expression; rest != ""; {
+		start := strings.IndexByte(rest, '{')
+		if start < 0 {
+			path.segments = append(path.segments, jsonPathSegment{literal: rest})
+			break
+		}
+
+		if start > 0 {
+			path.segments = append(path.segments, jsonPathSegment{literal: rest[:start]})
+		}
+
+		end := closingBrace(rest, start)
+		if end < 0 {
+			return jsonPath{}, fmt.Errorf("unclosed expression in %q", expression)
+		}
+
+		segment, err := parseJSONPathExpression(strings.TrimSpace(rest[start+1 : end]))
+		if err != nil {
+			return jsonPath{}, err
+		}
+
+		path.segments = append(path.segments, segment)
+		rest = rest[end+1:]
+	}
+
+	return path, nil
+}
+
+// closingBrace finds the brace closing the one at start, ignoring braces in
+// quoted strings.
+func closingBrace(s string, start int) int {
+	var quote byte
+
+	for i := start + 1; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0 && c == '\\':
+			i++
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote != 0:
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '}':
+			return i
+		}
+	}
+
+	return -1
+}
+
+func parseJSONPathExpression(expr string) (jsonPathSegment, error) {
+	if strings.HasPrefix(expr, `"`) {
+		literal, err := strconv.Unquote(expr)
+		if err != nil {
+			return jsonPathSegment{}, fmt.Errorf("invalid string literal %s: %w", expr, err)
+		}
+
+		return jsonPathSegment{literal: literal}, nil
+	}
+
+	segment := jsonPathSegment{isExpr: true}
+	rest := strings.TrimPrefix(expr, "$")
+
+	for rest != "" {
+		switch {
+		case rest == ".":
+			rest = ""
+		case strings.HasPrefix(rest, ".*"):
+			segment.steps = append(segment.steps, jsonPathStep{wildcard: true})
+			rest = rest[2:]
+		case rest[0] == '.':
+			end := strings.IndexAny(rest[1:], ".[")
+			if end < 0 {
+				end = len(rest) - 1
+			}
+
+			field := rest[1 : end+1]
+			if field == "" {
+				return jsonPathSegment{}, fmt.Errorf("invalid expression %q: empty field name", expr)
+			}
+
+			segment.steps = append(segment.steps, jsonPathStep{field: field})
+			rest = rest[end+1:]
+		case rest[0] == '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return jsonPathSegment{}, fmt.Errorf("invalid expression %q: unclosed bracket", expr)
+			}
+
+			step, err := parseJSONPathBracket(rest[1:end])
+			if err != nil {
+				return jsonPathSegment{}, fmt.Errorf("invalid expression %q: %w", expr, err)
+			}
+
+			segment.steps = append(segment.steps, step)
+			rest = rest[end+1:]
+		default:
+			return jsonPathSegment{}, fmt.Errorf("invalid expression %q: expected '.' or '[' at %q", expr, rest)
+		}
+	}
+
+	return segment, nil
+}
+
+func parseJSONPathBracket(content string) (jsonPathStep, error) {
+	content = strings.TrimSpace(content)
+
+	if content == "*" {
+		return jsonPathStep{wildcard: true}, nil
+	}
+
+	if len(content) >= 2 && (content[0] == '\'' || content[0] == '"') && content[len(content)-1] == content[0] {
+		return jsonPathStep{field: content[1 : len(content)-1]}, nil
+	}
+
+	index, err := strconv.Atoi(content)
+	if err != nil {
+		return jsonPathStep{}, fmt.Errorf("unsupported subscript [%s]", content)
+	}
+
+	return jsonPathStep{index: index, isIndex: true}, nil
+}
+
+func (p jsonPath) execute(data any) string {
+	var b strings.Builder
+
+	for _, segment := range p.segments {
+		if !segment.isExpr {
+			b.WriteString(segment.literal)
+			continue
+		}
+
+		values := []any{data}
+		for _, step := range segment.steps {
+			values = step.apply(values)
+		}
+
+		for i, value := range values {
+			if i > 0 {
+				b.WriteByte(' ')
+			}
+			b.WriteString(scalarString(value))
+		}
+	}
+
+	return b.String()
+}
+
+func (s jsonPathStep) apply(values []any) []any {
+	var out []any
+
+	for _, value := range values {
+		switch v := value.(type) {
+		case orderedObject:
+			switch {
+			case s.wildcard:
+				for _, key := range v.keys {
+					out = append(out, v.values[key])
+				}
+			case !s.isIndex:
+				if child, found := v.values[s.field]; found {
+					out = append(out, child)
+				}
+			}
+		case []any:
+			switch {
+			case s.wildcard:
+				out = append(out, v...)
+			case s.isIndex:
+				index := s.index
+				if index < 0 {
+					index += len(v)
+				}
+				if index >= 0 && index < len(v) {
+					out = append(out, v[index])
+				}
+			}
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,154 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const redacted = "[REDACTED]"
+
+// sensitiveArgument matches the names of tool arguments whose values must not
+// be logged. The keys of nested objects, like the names of environment
+// variables, are kept.
+var sensitiveArgument = regexp.MustCompile(`(?i)secret|token|password|credential|private|environment_variables|value`)
+
+// runURL matches the run URLs tools return, to record the runs they created.
+var runURL = regexp.MustCompile(`/stack/([^/\s]+)/run/([^/\s?#]+)`)
+
+// auditEntry is a line of the audit log.
+type auditEntry struct {
+	Time       string         `json:"time"`
+	Session    string         `json:"session,omitempty"`
+	Tool       string         `json:"tool"`
+	Arguments  map[string]any `json:"arguments,omitempty"`
+	StackID    string         `json:"stackId,omitempty"`
+	RunID      string         `json:"runId,omitempty"`
+	DurationMS int64          `json:"durationMs"`
+	Error      string         `json:"error,omitempty"`
+}
+
+// auditLog writes a JSON line for every tool call.
+type auditLog struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+	now func() time.Time
+}
+
+func newAuditLog(w io.Writer) *auditLog {
+	return &auditLog{enc: json.NewEncoder(w), now: time.Now}
+}
+
+// openAuditLog opens the audit log at the path, where - is stderr. The
+// returned function closes it.
+func openAuditLog(path string) (*auditLog, func() error, error) {
+	if path == "-" {
+		return newAuditLog(os.Stderr), func() error { return nil }, nil
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, nil, fmt.Errorf("couldn't open audit log %s: %w", path, err)
+	}
+
+	return newAuditLog(f), f.Close, nil
+}
+
+// middleware records the calls of every tool registered on the server.
+func (a *auditLog) middleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		start := a.now()
+
+		result, err := next(ctx, request)
+
+		entry := auditEntry{
+			Time:       start.UTC().Format(time.RFC3339Nano),
+			Tool:       request.Params.Name,
+			Arguments:  redactArguments(request.GetArguments()),
+			StackID:    request.GetString("stack_id", ""),
+			RunID:      request.GetString("run_id", ""),
+			DurationMS: a.now().Sub(start).Milliseconds(),
+		}
+
+		if session := server.ClientSessionFromContext(ctx); session != nil {
+			entry.Session = session.SessionID()
+		}
+
+		switch {
+		case err != nil:
+			entry.Error = err.Error()
+		case result != nil && result.IsError:
+			entry.Error = resultText(result)
+		case result != nil && entry.RunID == "":
+			if match := runURL.FindStringSubmatch(resultText(result)); match != nil {
+				entry.StackID, entry.RunID = match[1], match[2]
+			}
+		}
+
+		a.write(entry)
+
+		return result, err
+	}
+}
+
+func (a *auditLog) write(entry auditEntry) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	// A failure to audit must not fail the call, which already happened.
+	_ = a.enc.Encode(entry)
+}
+
+func resultText(result *mcp.CallToolResult) string {
+	var text string
+	for _, content := range result.Content {
+		if c, ok := content.(mcp.TextContent); ok {
+			text += c.Text
+		}
+	}
+
+	return text
+}
+
+func redactArguments(arguments map[string]any) map[string]any {
+	if len(arguments) == 0 {
+		return nil
+	}
+
+	out := make(map[string]any, len(arguments))
+	for key, value := range arguments {
+		out[key] = redactValue(value, sensitiveArgument.MatchString(key))
+	}
+
+	return out
+}
+
+func redactValue(value any, sensitive bool) any {
+	switch v := value.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for key, nested := range v {
+			out[key] = redactValue(nested, sensitive || sensitiveArgument.MatchString(key))
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, nested := range v {
+			out[i] = redactValue(nested, sensitive)
+		}
+		return out
+	default:
+		if sensitive {
+			return redacted
+		}
+		return value
+	}
+}
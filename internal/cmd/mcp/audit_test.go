@@ -0,0 +1,77 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditLogMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+	audit := newAuditLog(&buf)
+
+	clock := time.Date(2025, 6, 10, 12, 0, 0, 0, time.UTC)
+	audit.now = func() time.Time {
+		clock = clock.Add(250 * time.Millisecond)
+		return clock
+	}
+
+	call := func(name string, arguments map[string]any, result *mcp.CallToolResult, err error) {
+		handler := audit.middleware(func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return result, err
+		})
+
+		request := mcp.CallToolRequest{}
+		request.Params.Name = name
+		request.Params.Arguments = arguments
+
+		_, _ = handler(t.Context(), request)
+	}
+
+	call("trigger_stack_run", map[string]any{"stack_id": "my-stack", "run_type": "TRACKED"},
+		mcp.NewToolResultText("The live run can be visited at https://example.app.spacelift.io/stack/my-stack/run/01JXRUN"), nil)
+	call("local_preview", map[string]any{
+		"stack_id":              "my-stack",
+		"environment_variables": map[string]any{"TF_VAR_password": "hunter2"},
+		"targets":               []any{"aws_instance.web"},
+	}, nil, errors.New("upload failed"))
+	call("confirm_stack_run", map[string]any{"stack_id": "my-stack", "run_id": "01JXRUN", "confirmation_token": "123.abc"},
+		mcp.NewToolResultError("invalid confirmation token"), nil)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 3)
+
+	entries := make([]auditEntry, len(lines))
+	for i, line := range lines {
+		require.NoError(t, json.Unmarshal([]byte(line), &entries[i]))
+	}
+
+	assert.Equal(t, auditEntry{
+		Time:       "2025-06-10T12:00:00.25Z",
+		Tool:       "trigger_stack_run",
+		Arguments:  map[string]any{"stack_id": "my-stack", "run_type": "TRACKED"},
+		StackID:    "my-stack",
+		RunID:      "01JXRUN",
+		DurationMS: 250,
+	}, entries[0])
+
+	assert.Equal(t, map[string]any{
+		"stack_id":              "my-stack",
+		"environment_variables": map[string]any{"TF_VAR_password": redacted},
+		"targets":               []any{"aws_instance.web"},
+	}, entries[1].Arguments)
+	assert.Equal(t, "upload failed", entries[1].Error)
+	assert.NotContains(t, lines[1], "hunter2")
+
+	assert.Equal(t, redacted, entries[2].Arguments["confirmation_token"])
+	assert.Equal(t, "01JXRUN", entries[2].RunID)
+	assert.Equal(t, "invalid confirmation token", entries[2].Error)
+}
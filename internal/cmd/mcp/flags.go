@@ -0,0 +1,55 @@
+package mcp
+
+import (
+	"github.com/urfave/cli/v3"
+
+	"github.com/spacelift-io/spacectl/client/session"
+)
+
+var flagListen = &cli.StringFlag{
+	Name:  "listen",
+	Usage: "[Optional] `ADDRESS` to serve MCP over Streamable HTTP on, for example :8080. Each request authenticates with its own Spacelift API token (Authorization: Bearer) or API key (Authorization: Basic with the key ID and secret). Without it, MCP is served over stdio",
+}
+
+var flagEndpoint = &cli.StringFlag{
+	Name:    "endpoint",
+	Usage:   "[Optional] Spacelift `URL` the HTTP server makes requests to, for example https://example.app.spacelift.io. Required with --listen",
+	Sources: cli.EnvVars(session.EnvSpaceliftAPIKeyEndpoint),
+}
+
+var flagHTTPPath = &cli.StringFlag{
+	Name:  "http-path",
+	Usage: "[Optional] `PATH` the HTTP server serves MCP on",
+	Value: "/mcp",
+}
+
+var flagReadOnly = &cli.BoolFlag{
+	Name:  "read-only",
+	Usage: "[Optional] Only expose the tools annotated as read-only",
+}
+
+var flagTools = &cli.StringSliceFlag{
+	Name:  "tools",
+	Usage: "[Optional] Only expose the tools matching these `GLOB`s, for example list_*",
+}
+
+var flagExcludeTools = &cli.StringSliceFlag{
+	Name:  "exclude-tools",
+	Usage: "[Optional] Do not expose the tools matching these `GLOB`s",
+}
+
+var flagSpace = &cli.StringSliceFlag{
+	Name:  "space",
+	Usage: "[Optional] Refuse tool calls targeting an entity outside of these space `ID`s and their children, leave the entities of other spaces out of lists and remove the API key tools",
+}
+
+var flagRequireConfirmation = &cli.BoolFlag{
+	Name:  "require-confirmation",
+	Usage: "[Optional] Make destructive tools return a confirmation token on the first call, which has to be passed to a second call with the same arguments to act. Enabled by default with --listen only",
+}
+
+var flagAuditLog = &cli.StringFlag{
+	Name:    "audit-log",
+	Usage:   "[Optional] `FILE` to append a JSON line to for every tool call, with secrets redacted. Use - for stderr",
+	Sources: cli.EnvVars("SPACECTL_MCP_AUDIT_LOG"),
+}
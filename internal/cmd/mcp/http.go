@@ -0,0 +1,183 @@
+package mcp
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/spacelift-io/spacectl/client"
+	"github.com/spacelift-io/spacectl/client/session"
+	"github.com/spacelift-io/spacectl/internal/cmd/authenticated"
+)
+
+var errNoCredentials = errors.New("missing credentials: use an Authorization header with a Spacelift API token as the Bearer token, or an API key ID and secret as Basic credentials")
+
+// apiKeySessionTTL is how long the session of an API key is reused before the
+// key is exchanged again, so that revoked keys stop working.
+const apiKeySessionTTL = 10 * time.Minute
+
+type cachedSession struct {
+	session   session.Session
+	expiresAt time.Time
+}
+
+// requestAuthenticator builds a Spacelift client out of the credentials of an
+// HTTP request, so that each agent talks to Spacelift as itself.
+type requestAuthenticator struct {
+	endpoint   string
+	httpClient *http.Client
+	now        func() time.Time
+
+	// apiKeys caches the sessions of API keys by a hash of their
+	// credentials, as creating one exchanges the key for a token. Only keys
+	// exchanged successfully are cached, for apiKeySessionTTL. Sessions
+	// refresh their token themselves in the meantime.
+	apiKeys   map[[sha256.Size]byte]cachedSession
+	apiKeysMu sync.Mutex
+}
+
+func newRequestAuthenticator(endpoint string, httpClient *http.Client) *requestAuthenticator {
+	return &requestAuthenticator{
+		endpoint:   strings.TrimSuffix(endpoint, "/"),
+		httpClient: httpClient,
+		now:        time.Now,
+		apiKeys:    make(map[[sha256.Size]byte]cachedSession),
+	}
+}
+
+// clientFor returns a client authenticated with the credentials of the request.
+func (a *requestAuthenticator) clientFor(r *http.Request) (client.Client, error) {
+	if keyID, keySecret, ok := r.BasicAuth(); ok {
+		sess, err := a.apiKeySession(r.Context(), keyID, keySecret)
+		if err != nil {
+			return nil, err
+		}
+
+		return client.New(a.httpClient, sess), nil
+	}
+
+	scheme, token, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return nil, errNoCredentials
+	}
+
+	// The endpoint is always set, so the audience of the token can't point
+	// the server to another host.
+	sess, err := session.FromAPIToken(r.Context(), a.httpClient)(a.endpoint, strings.TrimSpace(token))
+	if err != nil {
+		return nil, err
+	}
+
+	return client.New(a.httpClient, sess), nil
+}
+
+func (a *requestAuthenticator) apiKeySession(ctx context.Context, keyID, keySecret string) (session.Session, error) {
+	cacheKey := sha256.Sum256([]byte(keyID + "\x00" + keySecret))
+
+	a.apiKeysMu.Lock()
+	cached, ok := a.apiKeys[cacheKey]
+	a.apiKeysMu.Unlock()
+
+	if ok && a.now().Before(cached.expiresAt) {
+		return cached.session, nil
+	}
+
+	// The session outlives the request, so it must not be bound to its context.
+	sess, err := session.FromAPIKey(context.WithoutCancel(ctx), a.httpClient)(a.endpoint, keyID, keySecret)
+	if err != nil {
+		return nil, err
+	}
+
+	if token, err := sess.BearerToken(ctx); err != nil || token == "" {
+		return nil, errors.New("could not exchange API key and secret for token")
+	}
+
+	a.apiKeysMu.Lock()
+	defer a.apiKeysMu.Unlock()
+
+	now := a.now()
+	for key, cached := range a.apiKeys {
+		if !now.Before(cached.expiresAt) {
+			delete(a.apiKeys, key)
+		}
+	}
+
+	a.apiKeys[cacheKey] = cachedSession{session: sess, expiresAt: now.Add(apiKeySessionTTL)}
+
+	return sess, nil
+}
+
+// authenticate rejects requests without valid credentials and passes the
+// client of the others to the tool handlers through the request context,
+// together with a hash of the credentials identifying them.
+func (a *requestAuthenticator) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, err := a.clientFor(r)
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="spacectl", Basic realm="spacectl"`)
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		credentials := sha256.Sum256([]byte(r.Header.Get("Authorization")))
+		ctx := authenticated.WithCredentials(authenticated.WithClient(r.Context(), c), hex.EncodeToString(credentials[:]))
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// serveHTTP serves MCP over Streamable HTTP until the process is interrupted.
+func serveHTTP(ctx context.Context, s *server.MCPServer, addr, path, endpoint string) error {
+	if endpoint == "" {
+		return fmt.Errorf("--%s is required with --%s", flagEndpoint.Name, flagListen.Name)
+	}
+
+	httpClient := client.GetHTTPClient()
+	if err := authenticated.ConfigureHTTPClient(httpClient, session.NetworkSettingsFromCurrentProfile()); err != nil {
+		return err
+	}
+
+	auth := newRequestAuthenticator(endpoint, httpClient)
+
+	mux := http.NewServeMux()
+	mux.Handle(path, auth.authenticate(server.NewStreamableHTTPServer(s, server.WithEndpointPath(path))))
+
+	httpServer := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if err := httpServer.Shutdown(shutdownCtx); err != nil {
+			log.Printf("failed to shut down the MCP server: %v", err)
+		}
+	}()
+
+	log.Printf("Serving MCP on http://%s%s for %s", addr, path, endpoint)
+
+	if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
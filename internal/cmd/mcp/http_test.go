@@ -0,0 +1,133 @@
+package mcp
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacelift-io/spacectl/internal/cmd/authenticated"
+)
+
+func TestRequestAuthenticator(t *testing.T) {
+	var exchanges atomic.Int32
+	spacelift := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		exchanges.Add(1)
+		assert.Equal(t, "/graphql", r.URL.Path)
+
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		w.Header().Set("Content-Type", "application/json")
+
+		if strings.Contains(string(body), "wrong-secret") {
+			require.NoError(t, json.NewEncoder(w).Encode(map[string]any{
+				"errors": []map[string]any{{"message": "unauthorized"}},
+			}))
+			return
+		}
+
+		require.NoError(t, json.NewEncoder(w).Encode(map[string]any{
+			"data": map[string]any{"apiKeyUser": map[string]any{
+				"jwt":        "exchanged",
+				"validUntil": time.Now().Add(time.Hour).Unix(),
+			}},
+		}))
+	}))
+	defer spacelift.Close()
+
+	auth := newRequestAuthenticator(spacelift.URL+"/", spacelift.Client())
+
+	handler := auth.authenticate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Credentials", authenticated.Credentials(r.Context()))
+		_, _ = w.Write([]byte(authenticated.Client(r.Context()).URL("/stack/%s", "my-stack")))
+	}))
+
+	serve := func(setup func(r *http.Request)) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+		setup(r)
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		return w
+	}
+
+	t.Run("without credentials", func(t *testing.T) {
+		w := serve(func(*http.Request) {})
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Contains(t, w.Header().Get("WWW-Authenticate"), "Bearer")
+	})
+
+	t.Run("with an API token", func(t *testing.T) {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{"https://elsewhere.app.spacelift.io"},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		}).SignedString([]byte("secret"))
+		require.NoError(t, err)
+
+		w := serve(func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+token) })
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, spacelift.URL+"/stack/my-stack", w.Body.String(), "the configured endpoint wins over the token audience")
+	})
+
+	t.Run("with an invalid API token", func(t *testing.T) {
+		w := serve(func(r *http.Request) { r.Header.Set("Authorization", "Bearer not-a-jwt") })
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("with an API key", func(t *testing.T) {
+		for range 2 {
+			w := serve(func(r *http.Request) { r.SetBasicAuth("key-id", "key-secret") })
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, spacelift.URL+"/stack/my-stack", w.Body.String())
+		}
+
+		assert.EqualValues(t, 1, exchanges.Load(), "the API key session is reused")
+	})
+
+	t.Run("credentials identify the caller", func(t *testing.T) {
+		first := serve(func(r *http.Request) { r.SetBasicAuth("key-id", "key-secret") })
+		again := serve(func(r *http.Request) { r.SetBasicAuth("key-id", "key-secret") })
+		other := serve(func(r *http.Request) { r.SetBasicAuth("other-key-id", "key-secret") })
+
+		assert.NotEmpty(t, first.Header().Get("X-Credentials"))
+		assert.Equal(t, first.Header().Get("X-Credentials"), again.Header().Get("X-Credentials"))
+		assert.NotEqual(t, first.Header().Get("X-Credentials"), other.Header().Get("X-Credentials"))
+	})
+
+	t.Run("with an API key after the cache expired", func(t *testing.T) {
+		exchanges.Store(0)
+		auth.now = func() time.Time { return time.Now().Add(apiKeySessionTTL) }
+		t.Cleanup(func() { auth.now = time.Now })
+
+		w := serve(func(r *http.Request) { r.SetBasicAuth("key-id", "key-secret") })
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.EqualValues(t, 1, exchanges.Load(), "the API key is exchanged again")
+	})
+
+	t.Run("with an invalid API key", func(t *testing.T) {
+		exchanges.Store(0)
+
+		for range 2 {
+			w := serve(func(r *http.Request) { r.SetBasicAuth("key-id", "wrong-secret") })
+
+			assert.Equal(t, http.StatusUnauthorized, w.Code)
+		}
+
+		assert.EqualValues(t, 2, exchanges.Load(), "failed exchanges are not cached")
+	})
+}
@@ -38,49 +38,23 @@ func Command() cmd.Command {
 					{
 						EarliestVersion: cmd.SupportedVersionAll,
 						Command: &cli.Command{
+							Flags:     serverFlags,
 							ArgsUsage: cmd.EmptyArgsUsage,
-							Action: func(_ context.Context, _ *cli.Command) error {
-								s := mcpServer()
-
-								stack.RegisterMCPTools(s, stack.McpOptions{
-									UseHeadersForLocalPreview: false,
-								})
-								module.RegisterMCPTools(s)
-								policy.RegisterMCPTools(s)
-								graphql.RegisterMCPTools(s)
-								spaceliftcontext.RegisterMCPTools(s)
-								apikey.RegisterMCPTools(s)
-								space.RegisterMCPTools(s)
-								workerpool.RegisterMCPTools(s)
-								blueprint.RegisterMCPTools(s)
-
-								return server.ServeStdio(s)
-							},
-							Before: authenticated.Ensure,
+							Action: serve(stack.McpOptions{
+								UseHeadersForLocalPreview: false,
+							}),
+							Before: ensureStdioAuthenticated,
 						},
 					},
 					{
 						EarliestVersion: cmd.SupportedVersion("2.5.0"),
 						Command: &cli.Command{
+							Flags:     serverFlags,
 							ArgsUsage: cmd.EmptyArgsUsage,
-							Action: func(_ context.Context, _ *cli.Command) error {
-								s := mcpServer()
-
-								stack.RegisterMCPTools(s, stack.McpOptions{
-									UseHeadersForLocalPreview: true,
-								})
-								module.RegisterMCPTools(s)
-								policy.RegisterMCPTools(s)
-								graphql.RegisterMCPTools(s)
-								spaceliftcontext.RegisterMCPTools(s)
-								apikey.RegisterMCPTools(s)
-								space.RegisterMCPTools(s)
-								workerpool.RegisterMCPTools(s)
-								blueprint.RegisterMCPTools(s)
-
-								return server.ServeStdio(s)
-							},
-							Before: authenticated.Ensure,
+							Action: serve(stack.McpOptions{
+								UseHeadersForLocalPreview: true,
+							}),
+							Before: ensureStdioAuthenticated,
 						},
 					},
 				},
@@ -89,12 +63,85 @@ func Command() cmd.Command {
 	}
 }
 
-func mcpServer() *server.MCPServer {
+var serverFlags = []cli.Flag{
+	flagListen,
+	flagEndpoint,
+	flagHTTPPath,
+	flagReadOnly,
+	flagTools,
+	flagExcludeTools,
+	flagSpace,
+	flagRequireConfirmation,
+	flagAuditLog,
+}
+
+func serve(options stack.McpOptions) cli.ActionFunc {
+	return func(ctx context.Context, cliCmd *cli.Command) error {
+		safety := safetyOptionsFromFlags(cliCmd)
+		hooks := &server.Hooks{}
+
+		serverOptions := append([]server.ServerOption{server.WithHooks(hooks)}, safety.serverOptions()...)
+		if path := cliCmd.String(flagAuditLog.Name); path != "" {
+			audit, closeAudit, err := openAuditLog(path)
+			if err != nil {
+				return err
+			}
+			defer closeAudit()
+
+			serverOptions = append(serverOptions, server.WithToolHandlerMiddleware(audit.middleware))
+		}
+
+		s := mcpServer(serverOptions...)
+
+		stack.RegisterMCPTools(s, options)
+		module.RegisterMCPTools(s)
+		policy.RegisterMCPTools(s)
+		graphql.RegisterMCPTools(s)
+		spaceliftcontext.RegisterMCPTools(s)
+		apikey.RegisterMCPTools(s)
+		space.RegisterMCPTools(s)
+		workerpool.RegisterMCPTools(s)
+		blueprint.RegisterMCPTools(s)
+
+		stack.RegisterMCPResources(s, hooks, safety.checkStack())
+		policy.RegisterMCPResources(s)
+		stack.RegisterMCPPrompts(s)
+
+		if err := applySafety(s, safety); err != nil {
+			return err
+		}
+
+		if addr := cliCmd.String(flagListen.Name); addr != "" {
+			return serveHTTP(ctx, s, addr, cliCmd.String(flagHTTPPath.Name), cliCmd.String(flagEndpoint.Name))
+		}
+
+		return server.ServeStdio(s)
+	}
+}
+
+// ensureStdioAuthenticated authenticates with the local credentials, which
+// only the stdio server uses. The HTTP server authenticates every request.
+func ensureStdioAuthenticated(ctx context.Context, cliCmd *cli.Command) (context.Context, error) {
+	if cliCmd.String(flagListen.Name) != "" {
+		return ctx, nil
+	}
+
+	return authenticated.Ensure(ctx, cliCmd)
+}
+
+// mcpServer creates the server. Tool middlewares passed in the options wrap
+// the recovery one, so they also see the calls that panicked.
+func mcpServer(options ...server.ServerOption) *server.MCPServer {
 	s := server.NewMCPServer(
 		"Spacelift MCP Server",
 		"1.0.0",
-		server.WithLogging(),
-		server.WithRecovery(),
+		append(options,
+			server.WithLogging(),
+			server.WithRecovery(),
+			server.WithResourceCapabilities(true, false),
+			server.WithResourceRecovery(),
+			server.WithPromptCapabilities(false),
+		)...,
 	)
 
 	return s
@@ -0,0 +1,58 @@
+package mcp
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacelift-io/spacectl/internal/cmd/policy"
+	"github.com/spacelift-io/spacectl/internal/cmd/stack"
+)
+
+func TestResourcesAndPrompts(t *testing.T) {
+	s := mcpServer()
+	stack.RegisterMCPResources(s, &server.Hooks{}, nil)
+	policy.RegisterMCPResources(s)
+	stack.RegisterMCPPrompts(s)
+
+	call := func(method string, result any) {
+		response := s.HandleMessage(t.Context(), json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"`+method+`"}`))
+
+		data, err := json.Marshal(response)
+		require.NoError(t, err)
+
+		var envelope struct {
+			Result json.RawMessage `json:"result"`
+		}
+		require.NoError(t, json.Unmarshal(data, &envelope))
+		require.NoError(t, json.Unmarshal(envelope.Result, result), string(data))
+	}
+
+	var templates mcp.ListResourceTemplatesResult
+	call(string(mcp.MethodResourcesTemplatesList), &templates)
+
+	var uris []string
+	for _, template := range templates.ResourceTemplates {
+		uris = append(uris, template.URITemplate.Raw())
+	}
+	assert.ElementsMatch(t, []string{
+		"spacelift://stack/{stack_id}",
+		"spacelift://stack/{stack_id}/run/{run_id}",
+		"spacelift://stack/{stack_id}/run/{run_id}/logs",
+		"spacelift://stack/{stack_id}/run/{run_id}/changes",
+		"spacelift://policy/{policy_id}",
+	}, uris)
+
+	var prompts mcp.ListPromptsResult
+	call(string(mcp.MethodPromptsList), &prompts)
+
+	var names []string
+	for _, prompt := range prompts.Prompts {
+		names = append(names, prompt.Name)
+	}
+	assert.ElementsMatch(t, []string{"diagnose_failed_run", "review_proposed_changes"}, names)
+}
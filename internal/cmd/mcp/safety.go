@@ -0,0 +1,562 @@
+package mcp
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/shurcooL/graphql"
+	"github.com/urfave/cli/v3"
+
+	"github.com/spacelift-io/spacectl/internal/cmd/authenticated"
+)
+
+// spacelessTools work on entities which don't belong to a space, so they are
+// removed when the server is restricted to spaces.
+var spacelessTools = []string{"list_api_keys", "get_api_key"}
+
+const (
+	confirmationTokenArgument = "confirmation_token"
+	confirmationTokenTTL      = 5 * time.Minute
+
+	// spaceTreeTTL is how long the space tree is reused to check whether a
+	// space is a child of the allowed ones.
+	spaceTreeTTL = 5 * time.Minute
+)
+
+// safetyOptions restrict what the agents using the server can do.
+type safetyOptions struct {
+	// readOnly only exposes the tools annotated as read-only.
+	readOnly bool
+	// tools, if set, are globs of the only tools to expose.
+	tools []string
+	// excludeTools are globs of tools not to expose.
+	excludeTools []string
+	// spaces, if set, are the only spaces (with their children) the tools
+	// can target.
+	spaces []string
+	// scope checks spaces against the allowed ones, for all the calls made
+	// to the server. It is set when spaces are.
+	scope *spaceScope
+	// requireConfirmation makes destructive tools return a confirmation
+	// token instead of acting, which has to be passed to a second call.
+	requireConfirmation bool
+}
+
+func safetyOptionsFromFlags(cliCmd *cli.Command) safetyOptions {
+	options := safetyOptions{
+		readOnly:     cliCmd.Bool(flagReadOnly.Name),
+		tools:        cliCmd.StringSlice(flagTools.Name),
+		excludeTools: cliCmd.StringSlice(flagExcludeTools.Name),
+		spaces:       cliCmd.StringSlice(flagSpace.Name),
+	}
+
+	// Confirmations are on by default for remote agents only, local ones
+	// already ask the user before calling tools.
+	options.requireConfirmation = cliCmd.String(flagListen.Name) != ""
+	if cliCmd.IsSet(flagRequireConfirmation.Name) {
+		options.requireConfirmation = cliCmd.Bool(flagRequireConfirmation.Name)
+	}
+
+	if len(options.spaces) > 0 {
+		options.scope = newSpaceScope(options.spaces)
+	}
+
+	return options
+}
+
+// applySafety removes the tools the options don't allow and wraps the
+// handlers of the others with the space and confirmation checks. It must be
+// called after all the tools are registered.
+func applySafety(s *server.MCPServer, options safetyOptions) error {
+	tools := s.ListTools()
+	names := slices.Sorted(maps.Keys(tools))
+
+	for _, pattern := range append(slices.Clone(options.tools), options.excludeTools...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid tool pattern %q: %w", pattern, err)
+		}
+
+		if !slices.ContainsFunc(names, func(name string) bool { return toolMatches([]string{pattern}, name) }) {
+			return fmt.Errorf("no tool matches %q, available tools: %s", pattern, strings.Join(names, ", "))
+		}
+	}
+
+	confirmations := newConfirmationSigner()
+
+	var removed []string
+	for _, name := range names {
+		tool := tools[name]
+
+		if (options.readOnly && !isReadOnly(tool.Tool)) ||
+			(len(options.tools) > 0 && !toolMatches(options.tools, name)) ||
+			toolMatches(options.excludeTools, name) ||
+			(options.scope != nil && slices.Contains(spacelessTools, name)) {
+			removed = append(removed, name)
+			continue
+		}
+
+		handler := tool.Handler
+		if options.requireConfirmation && isDestructive(tool.Tool) {
+			handler = confirmations.require(tool.Tool, handler)
+			tool.Tool.InputSchema.Properties = maps.Clone(tool.Tool.InputSchema.Properties)
+			tool.Tool.InputSchema.Properties[confirmationTokenArgument] = map[string]any{
+				"type":        "string",
+				"description": "The confirmation token returned by a previous call with the same arguments. Only pass it once the user approved the action.",
+			}
+		}
+
+		if options.scope != nil {
+			handler = restrictToSpaces(options.scope, handler)
+		}
+
+		s.AddTool(tool.Tool, handler)
+	}
+
+	s.DeleteTools(removed...)
+
+	return nil
+}
+
+func toolMatches(patterns []string, name string) bool {
+	return slices.ContainsFunc(patterns, func(pattern string) bool {
+		matched, _ := path.Match(pattern, name)
+		return matched
+	})
+}
+
+func isReadOnly(tool mcp.Tool) bool {
+	return tool.Annotations.ReadOnlyHint != nil && *tool.Annotations.ReadOnlyHint
+}
+
+// isDestructive follows the MCP defaults: tools that are not read-only are
+// destructive unless annotated otherwise.
+func isDestructive(tool mcp.Tool) bool {
+	if isReadOnly(tool) {
+		return false
+	}
+
+	return tool.Annotations.DestructiveHint == nil || *tool.Annotations.DestructiveHint
+}
+
+// confirmationSigner issues confirmation tokens bound to the caller, a tool
+// and its arguments. The caller is the MCP session and the credentials it
+// uses. Tokens can only be redeemed once. The key only lives as long as the
+// process.
+type confirmationSigner struct {
+	key []byte
+	now func() time.Time
+
+	mu sync.Mutex
+	// redeemed records the tokens already used, until they expire.
+	redeemed map[string]int64
+}
+
+func newConfirmationSigner() *confirmationSigner {
+	key := make([]byte, 32)
+	_, _ = rand.Read(key)
+
+	return &confirmationSigner{key: key, now: time.Now, redeemed: map[string]int64{}}
+}
+
+// confirmationCaller identifies who a token is issued to.
+func confirmationCaller(ctx context.Context) string {
+	var sessionID string
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		sessionID = session.SessionID()
+	}
+
+	return sessionID + "\n" + authenticated.Credentials(ctx)
+}
+
+func (c *confirmationSigner) sign(caller, tool string, arguments map[string]any, expiresAt int64) (string, error) {
+	arguments = maps.Clone(arguments)
+	delete(arguments, confirmationTokenArgument)
+
+	// Maps are encoded with sorted keys, so equal arguments sign the same.
+	encoded, err := json.Marshal(arguments)
+	if err != nil {
+		return "", err
+	}
+
+	mac := hmac.New(sha256.New, c.key)
+	fmt.Fprintf(mac, "%s\n%s\n%d\n%s", caller, tool, expiresAt, encoded)
+
+	return fmt.Sprintf("%d.%s", expiresAt, hex.EncodeToString(mac.Sum(nil))), nil
+}
+
+// redeem checks the token was issued to the caller for the same call, and
+// that it is neither expired nor used already.
+func (c *confirmationSigner) redeem(caller, tool string, arguments map[string]any, token string) error {
+	expiry, _, _ := strings.Cut(token, ".")
+
+	expiresAt, err := strconv.ParseInt(expiry, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid confirmation token")
+	}
+
+	expected, err := c.sign(caller, tool, arguments, expiresAt)
+	if err != nil {
+		return err
+	}
+
+	if !hmac.Equal([]byte(expected), []byte(token)) {
+		return fmt.Errorf("invalid confirmation token, it must come from a call to %s with the same arguments in this session", tool)
+	}
+
+	now := c.now().Unix()
+	if now > expiresAt {
+		return fmt.Errorf("the confirmation token expired, call %s again without it to get a new one", tool)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.redeemed[token]; ok {
+		return fmt.Errorf("the confirmation token was used already, call %s again without it to get a new one", tool)
+	}
+
+	maps.DeleteFunc(c.redeemed, func(_ string, expiresAt int64) bool { return now > expiresAt })
+	c.redeemed[token] = expiresAt
+
+	return nil
+}
+
+func (c *confirmationSigner) require(tool mcp.Tool, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments := request.GetArguments()
+		caller := confirmationCaller(ctx)
+
+		if token := request.GetString(confirmationTokenArgument, ""); token != "" {
+			if err := c.redeem(caller, tool.Name, arguments, token); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			return next(ctx, request)
+		}
+
+		token, err := c.sign(caller, tool.Name, arguments, c.now().Add(confirmationTokenTTL).Unix())
+		if err != nil {
+			return nil, err
+		}
+
+		delete(arguments, confirmationTokenArgument)
+		encoded, err := json.Marshal(arguments)
+		if err != nil {
+			return nil, err
+		}
+
+		title := tool.Annotations.Title
+		if title == "" {
+			title = tool.Name
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf(
+			"%s was not performed yet: it needs confirmation.\nArguments: %s\n\n"+
+				"Show the action to the user and ask for approval. Once approved, call %s again with the same arguments and \"%s\": %q. The token can only be used once and expires in %s.",
+			title, encoded, tool.Name, confirmationTokenArgument, token, confirmationTokenTTL,
+		)), nil
+	}
+}
+
+// serverOptions restricts the resources and prompts like the tools. They
+// have to be passed to the server when creating it.
+func (o safetyOptions) serverOptions() []server.ServerOption {
+	if o.scope == nil {
+		return nil
+	}
+
+	return []server.ServerOption{
+		server.WithResourceHandlerMiddleware(func(next server.ResourceHandlerFunc) server.ResourceHandlerFunc {
+			return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+				violation, err := o.scope.violation(ctx, func(name string) string {
+					switch v := request.Params.Arguments[name].(type) {
+					case string:
+						return v
+					case []string:
+						if len(v) > 0 {
+							return v[0]
+						}
+					}
+					return ""
+				})
+				if err != nil {
+					return nil, err
+				}
+
+				if violation != "" {
+					return nil, errors.New(violation)
+				}
+
+				return next(authenticated.WithSpaceScope(ctx, o.scope.contains), request)
+			}
+		}),
+		server.WithPromptHandlerMiddleware(func(next server.PromptHandlerFunc) server.PromptHandlerFunc {
+			return func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+				violation, err := o.scope.violation(ctx, func(name string) string {
+					return request.Params.Arguments[name]
+				})
+				if err != nil {
+					return nil, err
+				}
+
+				if violation != "" {
+					return nil, errors.New(violation)
+				}
+
+				return next(authenticated.WithSpaceScope(ctx, o.scope.contains), request)
+			}
+		}),
+	}
+}
+
+// checkStack refuses the stacks out of the allowed spaces, for the requests
+// which don't go through the handler middlewares, like resource
+// subscriptions. It is nil without a space restriction.
+func (o safetyOptions) checkStack() func(ctx context.Context, stackID string) error {
+	if o.scope == nil {
+		return nil
+	}
+
+	return func(ctx context.Context, stackID string) error {
+		violation, err := o.scope.violation(ctx, func(name string) string {
+			if name == "stack_id" {
+				return stackID
+			}
+			return ""
+		})
+		if err != nil {
+			return err
+		}
+
+		if violation != "" {
+			return errors.New(violation)
+		}
+
+		return nil
+	}
+}
+
+// restrictToSpaces refuses tool calls targeting a stack, module, context,
+// policy, blueprint, worker pool or space outside the allowed spaces and their
+// children, and hides the entities of other spaces from the lists returned.
+func restrictToSpaces(scope *spaceScope, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		violation, err := scope.violation(ctx, func(name string) string {
+			return request.GetString(name, "")
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if violation != "" {
+			return mcp.NewToolResultError(violation), nil
+		}
+
+		return next(authenticated.WithSpaceScope(ctx, scope.contains), request)
+	}
+}
+
+// spaceScope tells whether spaces are among the allowed ones or their
+// children. The space tree is fetched once for all the calls, and refreshed
+// every spaceTreeTTL to pick up new spaces.
+type spaceScope struct {
+	allowed []string
+	now     func() time.Time
+	fetch   func(ctx context.Context) (map[string]string, error)
+
+	mu        sync.Mutex
+	parents   map[string]string
+	fetchedAt time.Time
+}
+
+func newSpaceScope(allowed []string) *spaceScope {
+	return &spaceScope{allowed: allowed, now: time.Now, fetch: querySpaceParents}
+}
+
+// violation describes why the entities targeted by the arguments are out of
+// the allowed spaces, or returns an empty string if they are all allowed.
+func (s *spaceScope) violation(ctx context.Context, argument func(name string) string) (string, error) {
+	for _, name := range []string{"stack_id", "module_id", "context_id", "policy_id", "blueprint_id", "worker_pool_id", "space_id"} {
+		id := argument(name)
+		if id == "" {
+			continue
+		}
+
+		spaceID, err := entitySpace(ctx, name, id)
+		if err != nil {
+			return "", err
+		}
+
+		inScope, err := s.contains(ctx, spaceID)
+		if err != nil {
+			return "", err
+		}
+
+		if !inScope {
+			return fmt.Sprintf("%s %q is in space %q, this server is restricted to the spaces %s", entityName(name), id, spaceID, strings.Join(s.allowed, ", ")), nil
+		}
+	}
+
+	return "", nil
+}
+
+func (s *spaceScope) contains(ctx context.Context, spaceID string) (bool, error) {
+	if slices.Contains(s.allowed, spaceID) {
+		return true, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.parents == nil || s.now().Sub(s.fetchedAt) >= spaceTreeTTL {
+		parents, err := s.fetch(ctx)
+		if err != nil {
+			return false, err
+		}
+
+		s.parents, s.fetchedAt = parents, s.now()
+	}
+
+	return isWithinSpaces(spaceID, s.allowed, s.parents), nil
+}
+
+func entitySpace(ctx context.Context, argument, id string) (string, error) {
+	if argument == "space_id" {
+		return id, nil
+	}
+
+	type entity struct {
+		Space string `graphql:"space"`
+	}
+
+	var found *entity
+	variables := map[string]any{"id": graphql.ID(id)}
+
+	switch argument {
+	case "stack_id":
+		var query struct {
+			Entity *entity `graphql:"stack(id: $id)"`
+		}
+		if err := authenticated.Client(ctx).Query(ctx, &query, variables); err != nil {
+			return "", fmt.Errorf("failed to look up the space of stack %q: %w", id, err)
+		}
+		found = query.Entity
+	case "module_id":
+		var query struct {
+			Entity *entity `graphql:"module(id: $id)"`
+		}
+		if err := authenticated.Client(ctx).Query(ctx, &query, variables); err != nil {
+			return "", fmt.Errorf("failed to look up the space of module %q: %w", id, err)
+		}
+		found = query.Entity
+	case "context_id":
+		var query struct {
+			Entity *entity `graphql:"context(id: $id)"`
+		}
+		if err := authenticated.Client(ctx).Query(ctx, &query, variables); err != nil {
+			return "", fmt.Errorf("failed to look up the space of context %q: %w", id, err)
+		}
+		found = query.Entity
+	case "policy_id":
+		var query struct {
+			Entity *entity `graphql:"policy(id: $id)"`
+		}
+		if err := authenticated.Client(ctx).Query(ctx, &query, variables); err != nil {
+			return "", fmt.Errorf("failed to look up the space of policy %q: %w", id, err)
+		}
+		found = query.Entity
+	case "blueprint_id":
+		var query struct {
+			Entity *struct {
+				Space struct {
+					ID string `graphql:"id"`
+				} `graphql:"space"`
+			} `graphql:"blueprint(id: $id)"`
+		}
+		if err := authenticated.Client(ctx).Query(ctx, &query, variables); err != nil {
+			return "", fmt.Errorf("failed to look up the space of blueprint %q: %w", id, err)
+		}
+		if query.Entity != nil {
+			found = &entity{Space: query.Entity.Space.ID}
+		}
+	case "worker_pool_id":
+		var query struct {
+			Entity *struct {
+				Space struct {
+					ID string `graphql:"id"`
+				} `graphql:"spaceDetails"`
+			} `graphql:"workerPool(id: $id)"`
+		}
+		if err := authenticated.Client(ctx).Query(ctx, &query, variables); err != nil {
+			return "", fmt.Errorf("failed to look up the space of worker pool %q: %w", id, err)
+		}
+		if query.Entity != nil {
+			found = &entity{Space: query.Entity.Space.ID}
+		}
+	}
+
+	if found == nil {
+		return "", fmt.Errorf("%s %q not found", entityName(argument), id)
+	}
+
+	return found.Space, nil
+}
+
+// entityName returns the name of the entity an argument refers to, for
+// example "worker pool" for worker_pool_id.
+func entityName(argument string) string {
+	return strings.ReplaceAll(strings.TrimSuffix(argument, "_id"), "_", " ")
+}
+
+// querySpaceParents returns the parent of every space which has one.
+func querySpaceParents(ctx context.Context) (map[string]string, error) {
+	var query struct {
+		Spaces []struct {
+			ID          string  `graphql:"id"`
+			ParentSpace *string `graphql:"parentSpace"`
+		} `graphql:"spaces"`
+	}
+
+	if err := authenticated.Client(ctx).Query(ctx, &query, map[string]any{}); err != nil {
+		return nil, fmt.Errorf("failed to query spaces: %w", err)
+	}
+
+	parents := make(map[string]string, len(query.Spaces))
+	for _, space := range query.Spaces {
+		if space.ParentSpace != nil {
+			parents[space.ID] = *space.ParentSpace
+		}
+	}
+
+	return parents, nil
+}
+
+// isWithinSpaces tells whether the space is one of the allowed ones or one of
+// their descendants.
+func isWithinSpaces(spaceID string, allowed []string, parents map[string]string) bool {
+	seen := map[string]bool{}
+
+	for current := spaceID; current != "" && !seen[current]; current = parents[current] {
+		if slices.Contains(allowed, current) {
+			return true
+		}
+		seen[current] = true
+	}
+
+	return false
+}
@@ -0,0 +1,331 @@
+package mcp
+
+import (
+	"context"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+
+	"github.com/spacelift-io/spacectl/client/fakeapi"
+	"github.com/spacelift-io/spacectl/internal/cmd/apikey"
+	"github.com/spacelift-io/spacectl/internal/cmd/authenticated"
+	"github.com/spacelift-io/spacectl/internal/cmd/blueprint"
+	"github.com/spacelift-io/spacectl/internal/cmd/space"
+	"github.com/spacelift-io/spacectl/internal/cmd/stack"
+	"github.com/spacelift-io/spacectl/internal/cmd/workerpool"
+)
+
+func safetyTestServer() *server.MCPServer {
+	s := server.NewMCPServer("Test MCP Server", "1.0.0")
+
+	handler := func(_ context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("done " + request.Params.Name), nil
+	}
+
+	s.AddTool(mcp.NewTool("list_stacks", mcp.WithToolAnnotation(mcp.ToolAnnotation{ReadOnlyHint: new(true)})), handler)
+	s.AddTool(mcp.NewTool("get_stack_run", mcp.WithToolAnnotation(mcp.ToolAnnotation{ReadOnlyHint: new(true)})), handler)
+	s.AddTool(mcp.NewTool("confirm_stack_run", mcp.WithToolAnnotation(mcp.ToolAnnotation{DestructiveHint: new(true)}), mcp.WithString("run_id")), handler)
+	s.AddTool(mcp.NewTool("local_preview", mcp.WithToolAnnotation(mcp.ToolAnnotation{DestructiveHint: new(false)})), handler)
+
+	return s
+}
+
+func TestApplySafetyFiltersTools(t *testing.T) {
+	cases := map[string]struct {
+		options  safetyOptions
+		expected []string
+	}{
+		"everything":    {expected: []string{"confirm_stack_run", "get_stack_run", "list_stacks", "local_preview"}},
+		"read-only":     {options: safetyOptions{readOnly: true}, expected: []string{"get_stack_run", "list_stacks"}},
+		"allowlist":     {options: safetyOptions{tools: []string{"list_*", "local_preview"}}, expected: []string{"list_stacks", "local_preview"}},
+		"exclusions":    {options: safetyOptions{excludeTools: []string{"*_stack_run"}}, expected: []string{"list_stacks", "local_preview"}},
+		"all combined":  {options: safetyOptions{readOnly: true, tools: []string{"*stack*"}, excludeTools: []string{"list_*"}}, expected: []string{"get_stack_run"}},
+		"nothing left":  {options: safetyOptions{readOnly: true, tools: []string{"local_preview"}}},
+		"confirmations": {options: safetyOptions{requireConfirmation: true}, expected: []string{"confirm_stack_run", "get_stack_run", "list_stacks", "local_preview"}},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			s := safetyTestServer()
+			require.NoError(t, applySafety(s, c.options))
+
+			assert.Equal(t, c.expected, slices.Sorted(maps.Keys(s.ListTools())))
+		})
+	}
+
+	err := applySafety(safetyTestServer(), safetyOptions{tools: []string{"list_stack"}})
+	assert.ErrorContains(t, err, `no tool matches "list_stack"`)
+}
+
+func TestApplySafetyRequiresConfirmation(t *testing.T) {
+	s := safetyTestServer()
+	require.NoError(t, applySafety(s, safetyOptions{requireConfirmation: true}))
+
+	assert.NotContains(t, s.GetTool("local_preview").Tool.InputSchema.Properties, confirmationTokenArgument, "local_preview is not destructive")
+
+	confirm := s.GetTool("confirm_stack_run")
+	require.Contains(t, confirm.Tool.InputSchema.Properties, confirmationTokenArgument)
+
+	call := func(arguments map[string]any) string {
+		request := mcp.CallToolRequest{}
+		request.Params.Name = "confirm_stack_run"
+		request.Params.Arguments = arguments
+
+		result, err := confirm.Handler(t.Context(), request)
+		require.NoError(t, err)
+
+		return result.Content[0].(mcp.TextContent).Text
+	}
+
+	first := call(map[string]any{"run_id": "run-1"})
+	assert.Contains(t, first, "needs confirmation")
+
+	token := regexp.MustCompile(`"confirmation_token": "([^"]+)"`).FindStringSubmatch(first)
+	require.Len(t, token, 2)
+
+	assert.Contains(t, call(map[string]any{"run_id": "run-2", confirmationTokenArgument: token[1]}), "invalid confirmation token")
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "confirm_stack_run"
+	request.Params.Arguments = map[string]any{"run_id": "run-1", confirmationTokenArgument: token[1]}
+	result, err := confirm.Handler(authenticated.WithCredentials(t.Context(), "someone-else"), request)
+	require.NoError(t, err)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "invalid confirmation token", "other callers can't use the token")
+
+	assert.Equal(t, "done confirm_stack_run", call(map[string]any{"run_id": "run-1", confirmationTokenArgument: token[1]}))
+	assert.Contains(t, call(map[string]any{"run_id": "run-1", confirmationTokenArgument: token[1]}), "used already")
+}
+
+func TestConfirmationSignerRedeem(t *testing.T) {
+	signer := newConfirmationSigner()
+	arguments := map[string]any{"run_id": "run-1"}
+
+	token, err := signer.sign("session-1", "confirm_stack_run", arguments, signer.now().Add(confirmationTokenTTL).Unix())
+	require.NoError(t, err)
+
+	assert.ErrorContains(t, signer.redeem("session-2", "confirm_stack_run", arguments, token), "invalid", "tokens are bound to the caller")
+	require.NoError(t, signer.redeem("session-1", "confirm_stack_run", arguments, token))
+	assert.ErrorContains(t, signer.redeem("session-1", "confirm_stack_run", arguments, token), "used already")
+	assert.ErrorContains(t, signer.redeem("session-1", "confirm_stack_run", arguments, "garbage"), "invalid")
+
+	signer.now = func() time.Time { return time.Now().Add(2 * confirmationTokenTTL) }
+	assert.ErrorContains(t, signer.redeem("session-1", "confirm_stack_run", arguments, token), "expired")
+
+	other, err := signer.sign("session-1", "confirm_stack_run", arguments, signer.now().Add(confirmationTokenTTL).Unix())
+	require.NoError(t, err)
+	require.NoError(t, signer.redeem("session-1", "confirm_stack_run", arguments, other))
+	assert.NotContains(t, signer.redeemed, token, "expired tokens are forgotten")
+}
+
+func TestIsWithinSpaces(t *testing.T) {
+	parents := map[string]string{
+		"prod":     "root",
+		"prod-eu":  "prod",
+		"dev":      "root",
+		"loop-a":   "loop-b",
+		"loop-b":   "loop-a",
+		"orphaned": "missing",
+	}
+
+	assert.True(t, isWithinSpaces("dev", []string{"dev"}, parents))
+	assert.True(t, isWithinSpaces("prod-eu", []string{"dev", "prod"}, parents))
+	assert.False(t, isWithinSpaces("prod-eu", []string{"dev"}, parents))
+	assert.False(t, isWithinSpaces("root", []string{"prod"}, parents), "parents are not in scope")
+	assert.False(t, isWithinSpaces("loop-a", []string{"prod"}, parents))
+	assert.False(t, isWithinSpaces("orphaned", []string{"prod"}, parents))
+}
+
+func TestSafetyOptionsRequireConfirmation(t *testing.T) {
+	cases := map[string]struct {
+		args     []string
+		expected bool
+	}{
+		"stdio":          {expected: false},
+		"HTTP":           {args: []string{"--listen", ":8080"}, expected: true},
+		"stdio, enabled": {args: []string{"--require-confirmation"}, expected: true},
+		"HTTP, disabled": {args: []string{"--listen", ":8080", "--require-confirmation=false"}, expected: false},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			var options safetyOptions
+			command := &cli.Command{
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: flagListen.Name},
+					&cli.BoolFlag{Name: flagRequireConfirmation.Name},
+				},
+				Action: func(_ context.Context, cliCmd *cli.Command) error {
+					options = safetyOptionsFromFlags(cliCmd)
+					return nil
+				},
+			}
+
+			require.NoError(t, command.Run(t.Context(), append([]string{"mcp"}, c.args...)))
+			assert.Equal(t, c.expected, options.requireConfirmation)
+		})
+	}
+}
+
+func TestRestrictToSpaces(t *testing.T) {
+	now := time.Unix(1000, 0)
+	fetches := 0
+
+	scope := newSpaceScope([]string{"prod"})
+	scope.now = func() time.Time { return now }
+	scope.fetch = func(context.Context) (map[string]string, error) {
+		fetches++
+		return map[string]string{"prod": "root", "prod-eu": "prod", "dev": "root"}, nil
+	}
+
+	handler := restrictToSpaces(scope, func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		spaces, err := authenticated.FilterBySpace(ctx, []string{"prod", "prod-eu", "dev"}, func(space string) string { return space })
+		if err != nil {
+			return nil, err
+		}
+
+		return mcp.NewToolResultText(strings.Join(spaces, ",")), nil
+	})
+
+	call := func() string {
+		result, err := handler(t.Context(), mcp.CallToolRequest{})
+		require.NoError(t, err)
+
+		return result.Content[0].(mcp.TextContent).Text
+	}
+
+	assert.Equal(t, "prod,prod-eu", call(), "lists leave out the other spaces")
+	assert.Equal(t, "prod,prod-eu", call())
+	assert.Equal(t, 1, fetches, "the space tree is reused")
+
+	now = now.Add(spaceTreeTTL)
+	call()
+	assert.Equal(t, 2, fetches, "the space tree is refreshed")
+
+	spaces, err := authenticated.FilterBySpace(t.Context(), []string{"prod", "dev"}, func(space string) string { return space })
+	require.NoError(t, err)
+	assert.Equal(t, []string{"prod", "dev"}, spaces, "nothing is filtered without a scope")
+}
+
+func TestSafetyOptionsCheckStack(t *testing.T) {
+	assert.Nil(t, safetyOptions{}.checkStack(), "nothing to check without a space restriction")
+
+	api, err := fakeapi.NewServer(fakeapi.Fixtures{Stacks: []fakeapi.Object{
+		{"id": "app", "space": "prod-eu"},
+		{"id": "tools", "space": "dev"},
+	}})
+	require.NoError(t, err)
+	t.Cleanup(api.Close)
+
+	options := safetyOptions{scope: newSpaceScope([]string{"prod"})}
+	options.scope.fetch = func(context.Context) (map[string]string, error) {
+		return map[string]string{"prod": "root", "prod-eu": "prod", "dev": "root"}, nil
+	}
+
+	ctx := authenticated.WithClient(t.Context(), api.Client())
+	check := options.checkStack()
+
+	require.NoError(t, check(ctx, "app"))
+	assert.EqualError(t, check(ctx, "tools"), `stack "tools" is in space "dev", this server is restricted to the spaces prod`)
+	assert.EqualError(t, check(ctx, "missing"), `stack "missing" not found`)
+}
+
+func TestSpaceRestrictedTools(t *testing.T) {
+	api, err := fakeapi.NewServer(fakeapi.Fixtures{
+		Stacks: []fakeapi.Object{
+			{"id": "app", "name": "App", "space": "prod-eu", "labels": []any{}, "entities": []any{}},
+			{"id": "tools", "name": "Tools", "space": "dev", "labels": []any{}, "entities": []any{}},
+		},
+		Spaces: []fakeapi.Object{
+			{"id": "root", "name": "root"},
+			{"id": "prod", "name": "prod", "parentSpace": "root"},
+			{"id": "prod-eu", "name": "prod-eu", "parentSpace": "prod"},
+			{"id": "dev", "name": "dev", "parentSpace": "root"},
+		},
+		Blueprints: []fakeapi.Object{
+			{"id": "prod-blueprint", "name": "Prod blueprint", "space": fakeapi.Object{"id": "prod", "name": "prod"}},
+			{"id": "dev-blueprint", "name": "Dev blueprint", "space": fakeapi.Object{"id": "dev", "name": "dev"}},
+		},
+		WorkerPools: []fakeapi.Object{
+			{"id": "prod-pool", "name": "Prod pool", "spaceDetails": fakeapi.Object{"id": "prod-eu", "name": "prod-eu"}},
+			{"id": "dev-pool", "name": "Dev pool", "spaceDetails": fakeapi.Object{"id": "dev", "name": "dev"}},
+		},
+	})
+	require.NoError(t, err)
+	t.Cleanup(api.Close)
+
+	s := server.NewMCPServer("Test MCP Server", "1.0.0")
+	stack.RegisterMCPTools(s, stack.McpOptions{})
+	space.RegisterMCPTools(s)
+	blueprint.RegisterMCPTools(s)
+	workerpool.RegisterMCPTools(s)
+	apikey.RegisterMCPTools(s)
+
+	require.NoError(t, applySafety(s, safetyOptions{scope: newSpaceScope([]string{"prod"})}))
+
+	ctx := authenticated.WithClient(t.Context(), api.Client())
+	call := func(tool string, arguments map[string]any) *mcp.CallToolResult {
+		t.Helper()
+
+		request := mcp.CallToolRequest{}
+		request.Params.Name = tool
+		request.Params.Arguments = arguments
+
+		result, err := s.GetTool(tool).Handler(ctx, request)
+		require.NoError(t, err)
+
+		return result
+	}
+	text := func(result *mcp.CallToolResult) string {
+		return result.Content[0].(mcp.TextContent).Text
+	}
+
+	t.Run("resources of all stacks", func(t *testing.T) {
+		output := text(call("list_resources", nil))
+		assert.Contains(t, output, `"id":"app"`)
+		assert.NotContains(t, output, `"id":"tools"`)
+	})
+
+	t.Run("spaces", func(t *testing.T) {
+		output := text(call("list_spaces", nil))
+		assert.Contains(t, output, "Found 2 spaces")
+		assert.NotContains(t, output, `"id":"dev"`)
+
+		result := call("get_space", map[string]any{"space_id": "dev"})
+		assert.True(t, result.IsError)
+		assert.False(t, call("get_space", map[string]any{"space_id": "prod-eu"}).IsError)
+	})
+
+	t.Run("blueprints", func(t *testing.T) {
+		output := text(call("list_blueprints", nil))
+		assert.Contains(t, output, "prod-blueprint")
+		assert.NotContains(t, output, "dev-blueprint")
+
+		result := call("get_blueprint", map[string]any{"blueprint_id": "dev-blueprint"})
+		assert.True(t, result.IsError)
+		assert.Equal(t, `blueprint "dev-blueprint" is in space "dev", this server is restricted to the spaces prod`, text(result))
+	})
+
+	t.Run("worker pools", func(t *testing.T) {
+		output := text(call("list_worker_pools", nil))
+		assert.Contains(t, output, "prod-pool")
+		assert.NotContains(t, output, "dev-pool")
+
+		result := call("get_worker_pool", map[string]any{"worker_pool_id": "dev-pool"})
+		assert.True(t, result.IsError)
+		assert.Equal(t, `worker pool "dev-pool" is in space "dev", this server is restricted to the spaces prod`, text(result))
+	})
+
+	t.Run("API keys", func(t *testing.T) {
+		assert.Nil(t, s.GetTool("list_api_keys"))
+		assert.Nil(t, s.GetTool("get_api_key"))
+	})
+}
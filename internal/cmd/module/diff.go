@@ -0,0 +1,335 @@
+package module
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v3"
+
+	"github.com/spacelift-io/spacectl/internal/cmd"
+	"github.com/spacelift-io/spacectl/internal/cmd/authenticated"
+)
+
+// versionDetail is a module version together with its interface and consumers.
+type versionDetail struct {
+	ID       string `graphql:"id"`
+	Number   string `graphql:"number"`
+	Metadata *struct {
+		Root *versionInterface `graphql:"root"`
+	} `graphql:"metadata"`
+	Consumers []moduleVersionConsumer `graphql:"consumers"`
+}
+
+// versionInterface holds the inputs (variables) and outputs of the root module.
+type versionInterface struct {
+	Inputs  []moduleInput  `graphql:"inputs"`
+	Outputs []moduleOutput `graphql:"outputs"`
+}
+
+// changelog describes what changed in a module between two versions.
+type changelog struct {
+	Module  string            `json:"module"`
+	From    string            `json:"from"`
+	To      string            `json:"to"`
+	Commits []changelogCommit `json:"commits"`
+	Inputs  interfaceChanges  `json:"inputs"`
+	Outputs interfaceChanges  `json:"outputs"`
+	// Consumers are the stacks still using the version the diff starts from.
+	Consumers []moduleVersionConsumer `json:"consumers"`
+}
+
+type changelogCommit struct {
+	Version     string `json:"version"`
+	Hash        string `json:"hash"`
+	Message     string `json:"message"`
+	AuthorName  string `json:"authorName"`
+	AuthorLogin string `json:"authorLogin,omitempty"`
+	Timestamp   int    `json:"timestamp"`
+	URL         string `json:"url,omitempty"`
+}
+
+type interfaceChanges struct {
+	Added   []interfaceChange `json:"added"`
+	Removed []interfaceChange `json:"removed"`
+	Changed []interfaceChange `json:"changed"`
+}
+
+type interfaceChange struct {
+	Name string `json:"name"`
+	// Details list the changed attributes, for example `type: string -> number`.
+	Details []string `json:"details,omitempty"`
+}
+
+func (c interfaceChanges) isEmpty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0 && len(c.Changed) == 0
+}
+
+func diffVersions(ctx context.Context, cliCmd *cli.Command) error {
+	moduleID := cliCmd.String(flagModuleID.Name)
+
+	outputFormat, formatter, err := cmd.GetOutputFormat(cliCmd)
+	if err != nil {
+		return err
+	}
+
+	versions, err := getModuleVersions(ctx, cliCmd, moduleVersionsJSONLimit)
+	if err != nil {
+		return err
+	}
+
+	from, to := cliCmd.String(flagFromVersion.Name), cliCmd.String(flagToVersion.Name)
+
+	commits, err := commitsBetween(versions, from, to)
+	if err != nil {
+		return err
+	}
+
+	fromDetail, err := getVersionDetail(ctx, moduleID, versionIDFor(versions, from))
+	if err != nil {
+		return err
+	}
+
+	toDetail, err := getVersionDetail(ctx, moduleID, versionIDFor(versions, to))
+	if err != nil {
+		return err
+	}
+
+	log := buildChangelog(moduleID, fromDetail, toDetail, commits)
+
+	// A changelog does not fit in a table, so the default output is Markdown.
+	if outputFormat == cmd.OutputFormatTable {
+		return log.WriteMarkdown(os.Stdout)
+	}
+
+	return cmd.Output(formatter, log)
+}
+
+// commitsBetween returns the commits of the versions created after "from", up
+// to and including "to". Versions are matched by ID or number and are expected
+// in the newest-first order returned by getModuleVersions.
+func commitsBetween(versions []version, from, to string) ([]changelogCommit, error) {
+	matches := func(ref string) func(version) bool {
+		return func(v version) bool { return v.ID == ref || v.Number == ref }
+	}
+
+	fromIdx := slices.IndexFunc(versions, matches(from))
+	if fromIdx < 0 {
+		return nil, fmt.Errorf("version %q not found", from)
+	}
+
+	toIdx := slices.IndexFunc(versions, matches(to))
+	if toIdx < 0 {
+		return nil, fmt.Errorf("version %q not found", to)
+	}
+
+	if toIdx > fromIdx {
+		return nil, fmt.Errorf("version %q is older than version %q", versions[toIdx].Number, versions[fromIdx].Number)
+	}
+
+	commits := make([]changelogCommit, 0, fromIdx-toIdx)
+	for _, v := range versions[toIdx:fromIdx] {
+		commits = append(commits, changelogCommit{
+			Version:     v.Number,
+			Hash:        v.Commit.Hash,
+			Message:     v.Commit.Message,
+			AuthorName:  v.Commit.AuthorName,
+			AuthorLogin: v.Commit.AuthorLogin,
+			Timestamp:   v.Commit.Timestamp,
+			URL:         v.Commit.URL,
+		})
+	}
+
+	return commits, nil
+}
+
+func versionIDFor(versions []version, ref string) string {
+	for _, v := range versions {
+		if v.ID == ref || v.Number == ref {
+			return v.ID
+		}
+	}
+
+	return ref
+}
+
+func getVersionDetail(ctx context.Context, moduleID, versionID string) (versionDetail, error) {
+	var query struct {
+		Module *struct {
+			Version *versionDetail `graphql:"version(id: $versionId)"`
+		} `graphql:"module(id: $moduleId)"`
+	}
+
+	variables := map[string]any{
+		"moduleId":  moduleID,
+		"versionId": versionID,
+	}
+
+	if err := authenticated.Client(ctx).Query(ctx, &query, variables); err != nil {
+		return versionDetail{}, errors.Wrapf(err, "failed to query version %q of module %q", versionID, moduleID)
+	}
+
+	if query.Module == nil {
+		return versionDetail{}, fmt.Errorf("module %q not found", moduleID)
+	}
+
+	if query.Module.Version == nil {
+		return versionDetail{}, fmt.Errorf("version %q of module %q not found", versionID, moduleID)
+	}
+
+	return *query.Module.Version, nil
+}
+
+func buildChangelog(moduleID string, from, to versionDetail, commits []changelogCommit) changelog {
+	fromInputs, fromOutputs := from.interfaces()
+	toInputs, toOutputs := to.interfaces()
+
+	consumers := from.Consumers
+	if consumers == nil {
+		consumers = []moduleVersionConsumer{}
+	}
+
+	return changelog{
+		Module:  moduleID,
+		From:    from.Number,
+		To:      to.Number,
+		Commits: commits,
+		Inputs: diffInterface(fromInputs, toInputs, func(i moduleInput) string { return i.Name }, func(a, b moduleInput) []string {
+			var details []string
+			details = appendIfChanged(details, "type", a.Type, b.Type)
+			details = appendIfChanged(details, "default", a.Default, b.Default)
+			details = appendIfChanged(details, "required", fmt.Sprint(a.Required), fmt.Sprint(b.Required))
+			return appendIfChanged(details, "description", a.Description, b.Description)
+		}),
+		Outputs: diffInterface(fromOutputs, toOutputs, func(o moduleOutput) string { return o.Name }, func(a, b moduleOutput) []string {
+			return appendIfChanged(nil, "description", a.Description, b.Description)
+		}),
+		Consumers: consumers,
+	}
+}
+
+func (v versionDetail) interfaces() ([]moduleInput, []moduleOutput) {
+	if v.Metadata == nil || v.Metadata.Root == nil {
+		return nil, nil
+	}
+
+	return v.Metadata.Root.Inputs, v.Metadata.Root.Outputs
+}
+
+// diffInterface compares two lists of named module inputs or outputs.
+func diffInterface[T any](from, to []T, name func(T) string, compare func(a, b T) []string) interfaceChanges {
+	changes := interfaceChanges{
+		Added:   []interfaceChange{},
+		Removed: []interfaceChange{},
+		Changed: []interfaceChange{},
+	}
+
+	fromByName := make(map[string]T, len(from))
+	for _, item := range from {
+		fromByName[name(item)] = item
+	}
+
+	toByName := make(map[string]T, len(to))
+	for _, item := range to {
+		toByName[name(item)] = item
+	}
+
+	for _, item := range to {
+		old, found := fromByName[name(item)]
+		if !found {
+			changes.Added = append(changes.Added, interfaceChange{Name: name(item)})
+			continue
+		}
+
+		if details := compare(old, item); len(details) > 0 {
+			changes.Changed = append(changes.Changed, interfaceChange{Name: name(item), Details: details})
+		}
+	}
+
+	for _, item := range from {
+		if _, found := toByName[name(item)]; !found {
+			changes.Removed = append(changes.Removed, interfaceChange{Name: name(item)})
+		}
+	}
+
+	sortChanges := func(items []interfaceChange) {
+		slices.SortFunc(items, func(a, b interfaceChange) int { return strings.Compare(a.Name, b.Name) })
+	}
+	sortChanges(changes.Added)
+	sortChanges(changes.Removed)
+	sortChanges(changes.Changed)
+
+	return changes
+}
+
+func appendIfChanged(details []string, attribute, from, to string) []string {
+	if from == to {
+		return details
+	}
+
+	return append(details, fmt.Sprintf("%s: %s -> %s", attribute, orNone(from), orNone(to)))
+}
+
+func orNone(value string) string {
+	if value == "" {
+		return "(none)"
+	}
+
+	return value
+}
+
+// WriteMarkdown writes the changelog ready to be pasted in release notes or
+// a pull request.
+func (c changelog) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s: %s → %s\n", c.Module, c.From, c.To)
+
+	b.WriteString("\n## Commits\n\n")
+	if len(c.Commits) == 0 {
+		b.WriteString("No commits.\n")
+	}
+	for _, commit := range c.Commits {
+		message, _, _ := strings.Cut(commit.Message, "\n")
+		fmt.Fprintf(&b, "- %s `%s` %s (%s)\n", commit.Version, cmd.HumanizeGitHash(commit.Hash), message, commit.AuthorName)
+	}
+
+	writeInterfaceChangesMarkdown(&b, "Inputs", c.Inputs)
+	writeInterfaceChangesMarkdown(&b, "Outputs", c.Outputs)
+
+	fmt.Fprintf(&b, "\n## Consumers of %s\n\n", c.From)
+	if len(c.Consumers) == 0 {
+		b.WriteString("No consumers.\n")
+	}
+	for _, consumer := range c.Consumers {
+		fmt.Fprintf(&b, "- %s (`%s`)\n", consumer.Name, consumer.ID)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeInterfaceChangesMarkdown(b *strings.Builder, title string, changes interfaceChanges) {
+	fmt.Fprintf(b, "\n## %s\n\n", title)
+
+	if changes.isEmpty() {
+		b.WriteString("No changes.\n")
+		return
+	}
+
+	for _, c := range changes.Added {
+		fmt.Fprintf(b, "- Added `%s`\n", c.Name)
+	}
+
+	for _, c := range changes.Removed {
+		fmt.Fprintf(b, "- Removed `%s`\n", c.Name)
+	}
+
+	for _, c := range changes.Changed {
+		fmt.Fprintf(b, "- Changed `%s`: %s\n", c.Name, strings.Join(c.Details, ", "))
+	}
+}
@@ -0,0 +1,76 @@
+package module
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacelift-io/spacectl/internal/cmd"
+)
+
+func TestCommitsBetween(t *testing.T) {
+	versions := make([]version, 0, 4)
+	for _, number := range []string{"1.3.0", "1.2.0", "1.1.0", "1.0.0"} {
+		v := version{ID: "id-" + number, Number: number}
+		v.Commit.Hash = "sha-" + number
+		versions = append(versions, v)
+	}
+
+	t.Run("range", func(t *testing.T) {
+		commits, err := commitsBetween(versions, "1.0.0", "id-1.2.0")
+		require.NoError(t, err)
+		require.Len(t, commits, 2)
+		assert.Equal(t, "1.2.0", commits[0].Version)
+		assert.Equal(t, "sha-1.1.0", commits[1].Hash)
+	})
+
+	t.Run("reversed range", func(t *testing.T) {
+		_, err := commitsBetween(versions, "1.2.0", "1.0.0")
+		assert.EqualError(t, err, `version "1.0.0" is older than version "1.2.0"`)
+	})
+
+	t.Run("unknown version", func(t *testing.T) {
+		_, err := commitsBetween(versions, "0.9.0", "1.0.0")
+		assert.EqualError(t, err, `version "0.9.0" not found`)
+	})
+}
+
+func TestBuildChangelog(t *testing.T) {
+	from := versionDetail{Number: "1.0.0", Consumers: []moduleVersionConsumer{{ID: "prod", Name: "Production"}}}
+	to := versionDetail{Number: "2.0.0"}
+
+	setInterface(&from, []moduleInput{
+		{Name: "region", Type: "string"},
+		{Name: "size", Type: "number", Default: "1"},
+	}, []moduleOutput{{Name: "id"}})
+	setInterface(&to, []moduleInput{
+		{Name: "region", Type: "string"},
+		{Name: "size", Type: "number", Required: true},
+		{Name: "tags", Type: "map(string)"},
+	}, nil)
+
+	log := buildChangelog("vpc", from, to, []changelogCommit{{Version: "2.0.0", Hash: "0123456789abcdef", Message: "Require size\n\nBody", AuthorName: "Jane"}})
+
+	assert.Equal(t, []interfaceChange{{Name: "tags"}}, log.Inputs.Added)
+	assert.Empty(t, log.Inputs.Removed)
+	assert.Equal(t, []interfaceChange{{Name: "size", Details: []string{"default: 1 -> (none)", "required: false -> true"}}}, log.Inputs.Changed)
+	assert.Equal(t, []interfaceChange{{Name: "id"}}, log.Outputs.Removed)
+
+	var b strings.Builder
+	require.Implements(t, (*cmd.MarkdownWriter)(nil), log)
+	require.NoError(t, log.WriteMarkdown(&b))
+
+	assert.Contains(t, b.String(), "# vpc: 1.0.0 → 2.0.0\n")
+	assert.Contains(t, b.String(), "- 2.0.0 `01234567` Require size (Jane)\n")
+	assert.Contains(t, b.String(), "- Changed `size`: default: 1 -> (none), required: false -> true\n")
+	assert.Contains(t, b.String(), "- Removed `id`\n")
+	assert.Contains(t, b.String(), "- Production (`prod`)\n")
+}
+
+func setInterface(v *versionDetail, inputs []moduleInput, outputs []moduleOutput) {
+	v.Metadata = &struct {
+		Root *versionInterface `graphql:"root"`
+	}{Root: &versionInterface{Inputs: inputs, Outputs: outputs}}
+}
@@ -67,3 +67,15 @@ var flagNoAnimation = &cli.BoolFlag{
 	Usage: "[Optional] Disable animated output and print plain text status updates instead. Automatically enabled when the output is not a terminal.",
 	Value: isatty.IsTerminal(os.Stdout.Fd()),
 }
+
+var flagFromVersion = &cli.StringFlag{
+	Name:     "from",
+	Usage:    "[Required] `VERSION` to compare from, either its number or its ID",
+	Required: true,
+}
+
+var flagToVersion = &cli.StringFlag{
+	Name:     "to",
+	Usage:    "[Required] `VERSION` to compare to, either its number or its ID",
+	Required: true,
+}
@@ -3,12 +3,13 @@ package module
 import (
 	"context"
 	"fmt"
-	"slices"
+	"iter"
 
 	"github.com/pkg/errors"
 	"github.com/shurcooL/graphql"
 	"github.com/urfave/cli/v3"
 
+	"github.com/spacelift-io/spacectl/client"
 	"github.com/spacelift-io/spacectl/client/structs"
 	"github.com/spacelift-io/spacectl/internal/cmd"
 	"github.com/spacelift-io/spacectl/internal/cmd/authenticated"
@@ -16,86 +17,50 @@ import (
 
 func listModules() cli.ActionFunc {
 	return func(ctx context.Context, cliCmd *cli.Command) error {
-		outputFormat, err := cmd.GetOutputFormat(cliCmd)
+		outputFormat, formatter, err := cmd.GetOutputFormat(cliCmd)
 		if err != nil {
 			return err
 		}
 
-		var limit *uint
-		if cliCmd.IsSet(cmd.FlagLimit.Name) {
-			limit = new(cliCmd.Uint(cmd.FlagLimit.Name))
+		opts, err := cmd.GetPaginateOptions(cliCmd)
+		if err != nil {
+			return err
 		}
 
-		var search *string
+		var input structs.SearchInput
 		if cliCmd.IsSet(cmd.FlagSearch.Name) {
-			search = new(cliCmd.String(cmd.FlagSearch.Name))
+			input.FullTextSearch = new(graphql.String(cliCmd.String(cmd.FlagSearch.Name)))
 		}
 
 		switch outputFormat {
 		case cmd.OutputFormatTable:
-			return listModulesTable(ctx, search, limit)
-		case cmd.OutputFormatJSON:
-			return listModulesJSON(ctx, search, limit)
+			return listModulesTable(ctx, input, opts, cliCmd.IsSet(cmd.FlagLimit.Name))
+		default:
+			return cmd.OutputStream(outputFormat, formatter, paginateModules(ctx, input, opts))
 		}
-
-		return fmt.Errorf("unknown output format: %v", outputFormat)
-	}
-}
-
-func listModulesJSON(ctx context.Context, search *string, limit *uint) error {
-	var first *graphql.Int
-	if limit != nil {
-		first = new(graphql.Int(*limit)) //nolint: gosec
-	}
-
-	var fullTextSearch *graphql.String
-	if search != nil {
-		fullTextSearch = new(graphql.String(*search))
-	}
-
-	modules, err := searchAllModules(ctx, structs.SearchInput{
-		First:          first,
-		FullTextSearch: fullTextSearch,
-	})
-	if err != nil {
-		return err
 	}
-
-	return cmd.OutputJSON(modules)
 }
 
-func listModulesTable(ctx context.Context, search *string, limit *uint) error {
+func listModulesTable(ctx context.Context, input structs.SearchInput, opts client.PaginateOptions, limitSet bool) error {
 	const defaultLimit = 20
 
-	var first *graphql.Int
-	if limit != nil {
-		first = new(graphql.Int(*limit)) //nolint: gosec
-	} else {
-		first = graphql.NewInt(graphql.Int(defaultLimit))
+	if !limitSet {
+		opts.Limit = defaultLimit
 	}
 
-	var fullTextSearch *graphql.String
-	if search != nil {
-		fullTextSearch = new(graphql.String(*search))
+	input.OrderBy = &structs.QueryOrder{
+		Field:     "name",
+		Direction: "DESC",
 	}
 
-	input := structs.SearchInput{
-		First:          first,
-		FullTextSearch: fullTextSearch,
-		OrderBy: &structs.QueryOrder{
-			Field:     "name",
-			Direction: "DESC",
-		},
-	}
-
-	modules, err := searchAllModules(ctx, input)
-	if err != nil {
-		return err
-	}
 	columns := []string{"Name", "ID", "Current Version", "Number", "State", "Yanked"}
 
 	tableData := [][]string{columns}
-	for _, module := range modules {
+	for module, err := range paginateModules(ctx, input, opts) {
+		if err != nil {
+			return err
+		}
+
 		row := []string{
 			module.Name,
 			module.ID,
@@ -112,53 +77,20 @@ func listModulesTable(ctx context.Context, search *string, limit *uint) error {
 		return err
 	}
 
-	if limit == nil {
+	if !limitSet {
 		fmt.Printf("Showing first %d modules. Use --limit to show more or less.\n", defaultLimit)
 	}
 
 	return nil
 }
 
-func searchAllModules(ctx context.Context, input structs.SearchInput) ([]module, error) {
-	const maxPageSize = 50
-
-	var limit int
-	if input.First != nil {
-		limit = int(*input.First)
-	}
-	fetchAll := limit == 0
-
-	out := []module{}
-	pageInput := structs.SearchInput{
-		First:          graphql.NewInt(maxPageSize),
-		FullTextSearch: input.FullTextSearch,
-	}
-	for {
-		if !fetchAll {
-			// Fetch exactly the number of items requested
-			pageInput.First = new(
-				//nolint: gosec
-				graphql.Int(
-					slices.Min([]int{maxPageSize, limit - len(out)}),
-				),
-			)
-		}
-
-		result, err := searchModules(ctx, pageInput)
-		if err != nil {
-			return nil, err
-		}
-
-		out = append(out, result.Modules...)
-
-		if result.PageInfo.HasNextPage && (fetchAll || limit > len(out)) {
-			pageInput.After = new(graphql.String(result.PageInfo.EndCursor))
-		} else {
-			break
-		}
-	}
-
-	return out, nil
+// paginateModules iterates over the modules matching the input, fetching
+// pages as they are consumed.
+func paginateModules(ctx context.Context, input structs.SearchInput, opts client.PaginateOptions) iter.Seq2[module, error] {
+	return client.Paginate(ctx, input, opts, func(ctx context.Context, input structs.SearchInput) (client.Page[module], error) {
+		result, err := searchModules(ctx, input)
+		return client.Page[module]{Items: result.Modules, PageInfo: result.PageInfo}, err
+	})
 }
 
 type searchModulesResult struct {
@@ -211,7 +143,7 @@ func searchModules(ctx context.Context, input structs.SearchInput) (searchModule
 		} `graphql:"searchModules(input: $input)"`
 	}
 
-	if err := authenticated.Client().Query(
+	if err := authenticated.Client(ctx).Query(
 		ctx,
 		&query,
 		map[string]any{"input": input},
@@ -0,0 +1,40 @@
+package module
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacelift-io/spacectl/client"
+	"github.com/spacelift-io/spacectl/client/fakeapi"
+	"github.com/spacelift-io/spacectl/client/structs"
+	"github.com/spacelift-io/spacectl/internal/cmd/authenticated"
+)
+
+func TestPaginateModules(t *testing.T) {
+	server, err := fakeapi.NewServer(fakeapi.Fixtures{
+		Viewer: fakeapi.Object{"id": "fake-user"},
+		Modules: []fakeapi.Object{
+			{"id": "terraform-aws-vpc", "name": "vpc"},
+			{"id": "terraform-aws-eks", "name": "eks"},
+			{"id": "terraform-aws-rds", "name": "rds"},
+		},
+	})
+	require.NoError(t, err)
+	t.Cleanup(server.Close)
+
+	ctx := authenticated.WithClient(t.Context(), server.Client())
+
+	ids := func(input structs.SearchInput, opts client.PaginateOptions) []string {
+		var out []string
+		for m, err := range paginateModules(ctx, input, opts) {
+			require.NoError(t, err)
+			out = append(out, m.ID)
+		}
+		return out
+	}
+
+	assert.Equal(t, []string{"terraform-aws-vpc", "terraform-aws-eks", "terraform-aws-rds"}, ids(structs.SearchInput{}, client.PaginateOptions{PageSize: 2}))
+	assert.Equal(t, []string{"terraform-aws-vpc"}, ids(structs.SearchInput{}, client.PaginateOptions{PageSize: 2, Limit: 1}))
+}
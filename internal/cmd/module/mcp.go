@@ -199,6 +199,11 @@ func registerListModulesTool(s *server.MCPServer) {
 			return nil, errors.Wrap(err, "failed to search modules")
 		}
 
+		result.Modules, err = authenticated.FilterBySpace(ctx, result.Modules, func(m module) string { return m.SpaceDetails.ID })
+		if err != nil {
+			return nil, err
+		}
+
 		modulesJSON, err := json.Marshal(result.Modules)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to marshal modules to JSON")
@@ -243,7 +248,7 @@ func registerGetModuleTool(s *server.MCPServer) {
 			Module *moduleDetailQuery `graphql:"module(id: $moduleId)"`
 		}
 
-		if err := authenticated.Client().Query(ctx, &query, map[string]any{"moduleId": moduleID}); err != nil {
+		if err := authenticated.Client(ctx).Query(ctx, &query, map[string]any{"moduleId": moduleID}); err != nil {
 			return nil, errors.Wrap(err, "failed to query module")
 		}
 
@@ -292,7 +297,7 @@ func registerListModuleVersionsTool(s *server.MCPServer) {
 			"includeFailed": graphql.Boolean(includeFailed),
 		}
 
-		if err := authenticated.Client().Query(ctx, &query, variables); err != nil {
+		if err := authenticated.Client(ctx).Query(ctx, &query, variables); err != nil {
 			return nil, errors.Wrap(err, "failed to query module versions")
 		}
 
@@ -347,7 +352,7 @@ func registerGetModuleVersionTool(s *server.MCPServer) {
 			"versionId": versionID,
 		}
 
-		if err := authenticated.Client().Query(ctx, &query, variables); err != nil {
+		if err := authenticated.Client(ctx).Query(ctx, &query, variables); err != nil {
 			return nil, errors.Wrap(err, "failed to query module version")
 		}
 
@@ -452,6 +457,11 @@ func registerSearchModulesTool(s *server.MCPServer) {
 			return nil, errors.Wrap(err, "failed to search modules")
 		}
 
+		result.Modules, err = authenticated.FilterBySpace(ctx, result.Modules, func(m module) string { return m.SpaceDetails.ID })
+		if err != nil {
+			return nil, err
+		}
+
 		modulesJSON, err := json.Marshal(result.Modules)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to marshal modules to JSON")
@@ -598,7 +608,7 @@ func searchModulesMCP(ctx context.Context, input structs.SearchInput) (*mcpSearc
 
 	variables := map[string]any{"input": input}
 
-	if err := authenticated.Client().Query(ctx, &query, variables); err != nil {
+	if err := authenticated.Client(ctx).Query(ctx, &query, variables); err != nil {
 		return nil, errors.Wrap(err, "failed to execute modules search query")
 	}
 
@@ -111,6 +111,7 @@ func Command() cmd.Command {
 							Flags: []cli.Flag{
 								cmd.FlagOutputFormat,
 								cmd.FlagLimit,
+								cmd.FlagPageSize,
 								cmd.FlagSearch,
 							},
 							Action:    listModules(),
@@ -123,7 +124,7 @@ func Command() cmd.Command {
 			{
 				Category: "Module management",
 				Name:     "list-versions",
-				Usage:    "List 20 latest non failed versions for a module",
+				Usage:    "List the latest non failed versions for a module, 20 by default in the table output",
 				Versions: []cmd.VersionedCommand{
 					{
 						EarliestVersion: cmd.SupportedVersionAll,
@@ -131,6 +132,8 @@ func Command() cmd.Command {
 							Flags: []cli.Flag{
 								flagModuleID,
 								cmd.FlagOutputFormat,
+								cmd.FlagLimit,
+								cmd.FlagPageSize,
 							},
 							Action:    listVersions(),
 							Before:    authenticated.Ensure,
@@ -139,6 +142,27 @@ func Command() cmd.Command {
 					},
 				},
 			},
+			{
+				Category: "Module management",
+				Name:     "diff",
+				Usage:    "Show a changelog between two versions of a module: commits, changed inputs and outputs, and consumers",
+				Versions: []cmd.VersionedCommand{
+					{
+						EarliestVersion: cmd.SupportedVersionAll,
+						Command: &cli.Command{
+							Flags: []cli.Flag{
+								flagModuleID,
+								flagFromVersion,
+								flagToVersion,
+								cmd.FlagOutputFormat,
+							},
+							Action:    diffVersions,
+							Before:    authenticated.Ensure,
+							ArgsUsage: cmd.EmptyArgsUsage,
+						},
+					},
+				},
+			},
 		},
 	}
 }
@@ -3,48 +3,50 @@ package module
 import (
 	"context"
 	"fmt"
-	"slices"
+	"iter"
 
 	"github.com/pkg/errors"
 	"github.com/shurcooL/graphql"
 	"github.com/urfave/cli/v3"
 
+	"github.com/spacelift-io/spacectl/client"
+	"github.com/spacelift-io/spacectl/client/modules"
 	"github.com/spacelift-io/spacectl/client/structs"
 	"github.com/spacelift-io/spacectl/internal/cmd"
 	"github.com/spacelift-io/spacectl/internal/cmd/authenticated"
 )
 
 const (
-	maxSearchModuleVersionsPageSize = 50
-	moduleVersionsTableLimit        = 20
-	moduleVersionsJSONLimit         = 0 // no limit
+	moduleVersionsTableLimit = 20
+	moduleVersionsJSONLimit  = 0 // no limit
 )
 
 func listVersions() cli.ActionFunc {
 	return func(ctx context.Context, cliCmd *cli.Command) error {
-		outputFormat, err := cmd.GetOutputFormat(cliCmd)
+		outputFormat, formatter, err := cmd.GetOutputFormat(cliCmd)
+		if err != nil {
+			return err
+		}
+
+		opts, err := cmd.GetPaginateOptions(cliCmd)
 		if err != nil {
 			return err
 		}
 
 		switch outputFormat {
 		case cmd.OutputFormatTable:
-			versions, err := getModuleVersions(ctx, cliCmd, moduleVersionsTableLimit)
-			if err != nil {
-				return err
+			if !cliCmd.IsSet(cmd.FlagLimit.Name) {
+				opts.Limit = moduleVersionsTableLimit
 			}
 
-			return formatModuleVersionsTable(versions)
-		case cmd.OutputFormatJSON:
-			versions, err := getModuleVersions(ctx, cliCmd, moduleVersionsJSONLimit)
-			if err != nil {
-				return err
+			return formatModuleVersionsTable(searchModuleVersions(ctx, cliCmd, opts))
+		default:
+			if !cliCmd.IsSet(cmd.FlagLimit.Name) {
+				opts.Limit = moduleVersionsJSONLimit
 			}
 
-			return formatModuleVersionsJSON(versions)
+			return cmd.OutputStream(outputFormat, formatter, searchModuleVersions(ctx, cliCmd, opts))
 		}
-
-		return fmt.Errorf("unknown output format: %v", outputFormat)
 	}
 }
 
@@ -53,92 +55,46 @@ func getModuleVersions(ctx context.Context, cliCmd *cli.Command, limit int) ([]v
 		return nil, errors.New("limit must be greater or equal to 0")
 	}
 
-	var cursor string
 	var versions []version
-
-	fetchAll := limit == 0
-
-	var pageSize int
-
-	for {
-		if fetchAll {
-			pageSize = maxSearchModuleVersionsPageSize
-		} else {
-			pageSize = slices.Min([]int{maxSearchModuleVersionsPageSize, limit - len(versions)})
-		}
-
-		result, err := getSearchModuleVersions(ctx, cliCmd, cursor, pageSize)
+	for v, err := range searchModuleVersions(ctx, cliCmd, client.PaginateOptions{Limit: limit}) {
 		if err != nil {
 			return nil, err
 		}
-
-		for _, edge := range result.Edges {
-			versions = append(versions, edge.Node)
-		}
-
-		if result.PageInfo.HasNextPage && (fetchAll || limit > len(versions)) {
-			cursor = result.PageInfo.EndCursor
-		} else {
-			break
-		}
+		versions = append(versions, v)
 	}
 
 	return versions, nil
 }
 
-func getSearchModuleVersions(ctx context.Context, cliCmd *cli.Command, cursor string, limit int) (searchModuleVersions, error) {
-	if limit <= 0 || limit > maxSearchModuleVersionsPageSize {
-		return searchModuleVersions{}, errors.New("limit must be between 1 and 50")
-	}
-
-	var query struct {
-		Module struct {
-			SearchModuleVersions searchModuleVersions `graphql:"searchModuleVersions(input: $input)"`
-		} `graphql:"module(id: $id)"`
-	}
-
-	var after *graphql.String
-	if cursor != "" {
-		after = new(graphql.String(cursor))
-	}
-
-	if err := authenticated.Client().Query(ctx, &query, map[string]any{
-		"id": cliCmd.String(flagModuleID.Name),
-		"input": structs.SearchInput{
-			First: new(graphql.Int(int32(limit))),
-			After: after,
-			OrderBy: &structs.QueryOrder{
-				Field:     "createdAt",
-				Direction: "DESC",
-			},
-			Predicates: &[]structs.QueryPredicate{
-				{
-					Field:   "state",
-					Exclude: true,
-					Constraint: structs.QueryFieldConstraint{
-						EnumEquals: &[]graphql.String{
-							"FAILED",
-						},
+func searchModuleVersions(ctx context.Context, cliCmd *cli.Command, opts client.PaginateOptions) iter.Seq2[version, error] {
+	return modules.New(authenticated.Client(ctx)).Versions(ctx, cliCmd.String(flagModuleID.Name), structs.SearchInput{
+		OrderBy: &structs.QueryOrder{
+			Field:     "createdAt",
+			Direction: "DESC",
+		},
+		Predicates: &[]structs.QueryPredicate{
+			{
+				Field:   "state",
+				Exclude: true,
+				Constraint: structs.QueryFieldConstraint{
+					EnumEquals: &[]graphql.String{
+						"FAILED",
 					},
 				},
 			},
 		},
-	}); err != nil {
-		return searchModuleVersions{}, errors.Wrap(err, "failed to query list of modules")
-	}
-
-	return query.Module.SearchModuleVersions, nil
-}
-
-func formatModuleVersionsJSON(versions []version) error {
-	return cmd.OutputJSON(versions)
+	}, opts)
 }
 
-func formatModuleVersionsTable(versions []version) error {
+func formatModuleVersionsTable(versions iter.Seq2[version, error]) error {
 	columns := []string{"ID", "Author", "Message", "Number", "State", "Tests", "Timestamp"}
 	tableData := [][]string{columns}
 
-	for _, v := range versions {
+	for v, err := range versions {
+		if err != nil {
+			return err
+		}
+
 		tableData = append(tableData, []string{
 			v.ID,
 			v.Commit.AuthorName,
@@ -153,29 +109,4 @@ func formatModuleVersionsTable(versions []version) error {
 	return cmd.OutputTable(tableData, true)
 }
 
-type searchModuleVersions struct {
-	PageInfo structs.PageInfo          `graphql:"pageInfo"`
-	Edges    []searchModuleVersionEdge `graphql:"edges"`
-}
-
-type searchModuleVersionEdge struct {
-	Node version `graphql:"node"`
-}
-
-type version struct {
-	ID     string `json:"id" graphql:"id"`
-	Commit struct {
-		AuthorLogin string `json:"authorLogin" graphql:"authorLogin"`
-		AuthorName  string `json:"authorName" graphql:"authorName"`
-		Hash        string `json:"hash" graphql:"hash"`
-		Message     string `json:"message" graphql:"message"`
-		Timestamp   int    `json:"timestamp" graphql:"timestamp"`
-		URL         string `json:"url" graphql:"url"`
-	} `json:"commit" graphql:"commit"`
-	DownloadLink any    `json:"downloadLink" graphql:"downloadLink"`
-	Number       string `json:"number" graphql:"number"`
-	SourceURL    string `json:"sourceURL" graphql:"sourceURL"`
-	State        string `json:"state" graphql:"state"`
-	VersionCount int    `json:"versionCount" graphql:"versionCount"`
-	Yanked       bool   `json:"yanked" graphql:"yanked"`
-}
+type version = modules.Version
@@ -3,6 +3,8 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"iter"
 	"os"
 	"strings"
 
@@ -19,23 +21,100 @@ const (
 
 	// OutputFormatJSON represents the output formatted as JSON.
 	OutputFormatJSON OutputFormat = "json"
+
+	// OutputFormatYAML represents the output formatted as YAML.
+	OutputFormatYAML OutputFormat = "yaml"
+
+	// OutputFormatCSV represents the output formatted as CSV, one row per item.
+	OutputFormatCSV OutputFormat = "csv"
+
+	// OutputFormatJSONL represents the output formatted as JSON Lines, one line per item.
+	OutputFormatJSONL OutputFormat = "jsonl"
+
+	// OutputFormatMarkdown represents the output formatted as Markdown.
+	OutputFormatMarkdown OutputFormat = "markdown"
+
+	// OutputFormatTemplate represents the output rendered with a Go template,
+	// given as -o template=TEMPLATE.
+	OutputFormatTemplate OutputFormat = "template"
+
+	// OutputFormatJSONPath represents the output of a JSONPath expression,
+	// given as -o jsonpath=EXPRESSION.
+	OutputFormatJSONPath OutputFormat = "jsonpath"
 )
 
 // AvailableOutputFormatStrings returns all the output formats available to users.
-var AvailableOutputFormatStrings = []string{string(OutputFormatTable), string(OutputFormatJSON)}
+var AvailableOutputFormatStrings = []string{
+	string(OutputFormatTable),
+	string(OutputFormatJSON),
+	string(OutputFormatYAML),
+	string(OutputFormatCSV),
+	string(OutputFormatJSONL),
+	string(OutputFormatMarkdown),
+	string(OutputFormatTemplate) + "=TEMPLATE",
+	string(OutputFormatJSONPath) + "=EXPRESSION",
+}
+
+// OutputFormatter writes data in a structured output format.
+type OutputFormatter func(w io.Writer, data any) error
+
+// OutputFormatterFactory creates a formatter. The argument is the part of the
+// --output value after the "=" sign, for formats accepting one.
+type OutputFormatterFactory func(argument string) (OutputFormatter, error)
+
+type outputFormatRegistration struct {
+	factory       OutputFormatterFactory
+	takesArgument bool
+}
+
+var outputFormatters = map[OutputFormat]outputFormatRegistration{
+	OutputFormatJSON:     {factory: staticFormatter(formatJSON)},
+	OutputFormatYAML:     {factory: staticFormatter(formatYAML)},
+	OutputFormatCSV:      {factory: staticFormatter(formatCSV)},
+	OutputFormatJSONL:    {factory: staticFormatter(formatJSONL)},
+	OutputFormatMarkdown: {factory: staticFormatter(formatMarkdown)},
+	OutputFormatTemplate: {factory: newTemplateFormatter, takesArgument: true},
+	OutputFormatJSONPath: {factory: newJSONPathFormatter, takesArgument: true},
+}
+
+// RegisterOutputFormat makes a structured output format available to all the
+// commands supporting the --output flag.
+func RegisterOutputFormat(format OutputFormat, factory OutputFormatterFactory, takesArgument bool) {
+	outputFormatters[format] = outputFormatRegistration{factory: factory, takesArgument: takesArgument}
+}
+
+// GetOutputFormat gets the selected output format based on the CLI args. Any
+// format other than table is structured: commands write their data by passing
+// the returned formatter to Output or OutputStream. The formatter is nil for
+// the table format.
+func GetOutputFormat(cliCmd *cli.Command) (OutputFormat, OutputFormatter, error) {
+	value := cliCmd.String(FlagOutputFormat.Name)
+	if value == "" || strings.EqualFold(value, string(OutputFormatTable)) {
+		return OutputFormatTable, nil, nil
+	}
+
+	name, argument, hasArgument := strings.Cut(value, "=")
+	format := OutputFormat(strings.ToLower(name))
+
+	registration, found := outputFormatters[format]
+	if !found {
+		return OutputFormatTable, nil, fmt.Errorf("unknown output format: %s", value)
+	}
+
+	if registration.takesArgument && !hasArgument {
+		return OutputFormatTable, nil, fmt.Errorf("output format %s requires an argument, example: -o %s=...", format, format)
+	}
 
-// GetOutputFormat gets the selected output format based on the CLI args.
-func GetOutputFormat(cliCmd *cli.Command) (OutputFormat, error) {
-	format := cliCmd.String(FlagOutputFormat.Name)
-	if format == "" || strings.EqualFold(format, string(OutputFormatTable)) {
-		return OutputFormatTable, nil
+	if !registration.takesArgument && hasArgument {
+		return OutputFormatTable, nil, fmt.Errorf("output format %s does not accept an argument", format)
 	}
 
-	if strings.EqualFold(format, string(OutputFormatJSON)) {
-		return OutputFormatJSON, nil
+	formatter, err := registration.factory(argument)
+	if err != nil {
+		return OutputFormatTable, nil, fmt.Errorf("invalid %s output format: %w", format, err)
 	}
 
-	return OutputFormatTable, fmt.Errorf("unknown output format: %s", format)
+	return format, formatter, nil
 }
 
 // OutputTable outputs the specified data as a table.
@@ -51,9 +130,66 @@ func OutputTable(data [][]string, hasHeader bool) error {
 	return printer.Render()
 }
 
+// Output outputs the specified object with the formatter returned by
+// GetOutputFormat, JSON if it is nil.
+func Output(formatter OutputFormatter, v any) error {
+	if formatter == nil {
+		formatter = formatJSON
+	}
+
+	return formatter(os.Stdout, v)
+}
+
+// OutputStream outputs the items in the format returned by GetOutputFormat.
+// With JSON Lines each item is written as soon as it arrives, other formats
+// need the whole list and collect the items first.
+func OutputStream[T any](format OutputFormat, formatter OutputFormatter, items iter.Seq2[T, error]) error {
+	if formatter == nil {
+		formatter = formatJSON
+	}
+
+	return outputStream(os.Stdout, format, formatter, items)
+}
+
+func outputStream[T any](w io.Writer, format OutputFormat, formatter OutputFormatter, items iter.Seq2[T, error]) error {
+	if format == OutputFormatJSONL {
+		for item, err := range items {
+			if err != nil {
+				return err
+			}
+
+			if err := formatJSONL(w, item); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	collected := []T{}
+	for item, err := range items {
+		if err != nil {
+			return err
+		}
+		collected = append(collected, item)
+	}
+
+	return formatter(w, collected)
+}
+
 // OutputJSON outputs the specified object as JSON.
 func OutputJSON(v any) error {
-	encoder := json.NewEncoder(os.Stdout)
+	return formatJSON(os.Stdout, v)
+}
+
+func staticFormatter(formatter OutputFormatter) OutputFormatterFactory {
+	return func(string) (OutputFormatter, error) {
+		return formatter, nil
+	}
+}
+
+func formatJSON(w io.Writer, v any) error {
+	encoder := json.NewEncoder(w)
 	encoder.SetIndent("", "  ")
 
 	if err := encoder.Encode(v); err != nil {
@@ -0,0 +1,233 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+type formatTestItem struct {
+	ID     string            `json:"id"`
+	Name   string            `json:"name"`
+	Count  int               `json:"count"`
+	Labels []string          `json:"labels"`
+	Space  formatTestSpace   `json:"space"`
+	Extra  map[string]string `json:"extra,omitempty"`
+}
+
+type formatTestSpace struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+var formatTestItems = []formatTestItem{
+	{ID: "vpc", Name: "VPC", Count: 2, Labels: []string{"network", "prod"}, Space: formatTestSpace{ID: "root", Name: "Root"}},
+	{ID: "app", Name: "App: frontend", Count: 1, Labels: []string{}, Space: formatTestSpace{ID: "prod", Name: "Production"}, Extra: map[string]string{"on": "true"}},
+}
+
+type markdownTestReport struct{}
+
+func (markdownTestReport) WriteMarkdown(w io.Writer) error {
+	_, err := io.WriteString(w, "# Report\n")
+	return err
+}
+
+func TestGetOutputFormat(t *testing.T) {
+	cases := []struct {
+		value    string
+		expected OutputFormat
+		err      string
+	}{
+		{value: "", expected: OutputFormatTable},
+		{value: "TABLE", expected: OutputFormatTable},
+		{value: "json", expected: OutputFormatJSON},
+		{value: "yaml", expected: OutputFormatYAML},
+		{value: "csv", expected: OutputFormatCSV},
+		{value: "jsonl", expected: OutputFormatJSONL},
+		{value: "markdown", expected: OutputFormatMarkdown},
+		{value: "template={{.id}}", expected: OutputFormatTemplate},
+		{value: "jsonpath={.id}", expected: OutputFormatJSONPath},
+		{value: "xml", err: "unknown output format: xml"},
+		{value: "template", err: "output format template requires an argument, example: -o template=..."},
+		{value: "yaml=x", err: "output format yaml does not accept an argument"},
+		{value: "template={{.id", err: "invalid template output format"},
+		{value: "jsonpath={.id", err: "invalid jsonpath output format"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.value, func(t *testing.T) {
+			var format OutputFormat
+			var formatter OutputFormatter
+			var err error
+
+			command := &cli.Command{
+				Flags: []cli.Flag{FlagOutputFormat},
+				Action: func(_ context.Context, cliCmd *cli.Command) error {
+					format, formatter, err = GetOutputFormat(cliCmd)
+					return nil
+				},
+			}
+			require.NoError(t, command.Run(t.Context(), []string{"test", "--output", c.value}))
+
+			if c.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), c.err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, format)
+			assert.Equal(t, format != OutputFormatTable, formatter != nil)
+		})
+	}
+}
+
+func TestFormatters(t *testing.T) {
+	format := func(t *testing.T, formatter OutputFormatter, v any) string {
+		t.Helper()
+
+		var b strings.Builder
+		require.NoError(t, formatter(&b, v))
+		return b.String()
+	}
+
+	t.Run("yaml keeps the field order", func(t *testing.T) {
+		assert.Equal(t, `- id: vpc
+  name: VPC
+  count: 2
+  labels:
+    - network
+    - prod
+  space:
+    id: root
+    name: Root
+- id: app
+  name: 'App: frontend'
+  count: 1
+  labels: []
+  space:
+    id: prod
+    name: Production
+  extra:
+    on: "true"
+`, format(t, formatYAML, formatTestItems))
+	})
+
+	t.Run("csv flattens objects", func(t *testing.T) {
+		assert.Equal(t, `id,name,count,labels,space.id,space.name,extra.on
+vpc,VPC,2,"[""network"",""prod""]",root,Root,
+app,App: frontend,1,[],prod,Production,true
+`, format(t, formatCSV, formatTestItems))
+	})
+
+	t.Run("csv of a single object", func(t *testing.T) {
+		assert.Equal(t, "id,name\nroot,Root\n", format(t, formatCSV, formatTestItems[0].Space))
+	})
+
+	t.Run("jsonl", func(t *testing.T) {
+		assert.Equal(t, `{"id":"vpc","name":"VPC","count":2,"labels":["network","prod"],"space":{"id":"root","name":"Root"}}
+{"id":"app","name":"App: frontend","count":1,"labels":[],"space":{"id":"prod","name":"Production"},"extra":{"on":"true"}}
+`, format(t, formatJSONL, formatTestItems))
+	})
+
+	t.Run("markdown table", func(t *testing.T) {
+		assert.Equal(t, `| id | name | count | labels | space.id | space.name | extra.on |
+| --- | --- | --- | --- | --- | --- | --- |
+| vpc | VPC | 2 | ["network","prod"] | root | Root |  |
+| app | App: frontend | 1 | [] | prod | Production | true |
+`, format(t, formatMarkdown, formatTestItems))
+	})
+
+	t.Run("markdown with its own layout", func(t *testing.T) {
+		assert.Equal(t, "# Report\n", format(t, formatMarkdown, markdownTestReport{}))
+	})
+
+	t.Run("template", func(t *testing.T) {
+		formatter, err := newTemplateFormatter(`{{range .}}{{.id}} {{.space.name}} {{json .labels}}{{"\n"}}{{end}}`)
+		require.NoError(t, err)
+
+		assert.Equal(t, "vpc Root [\"network\",\"prod\"]\napp Production []\n", format(t, formatter, formatTestItems))
+	})
+}
+
+func TestJSONPath(t *testing.T) {
+	cases := map[string]string{
+		"{[*].id}":                          "vpc app",
+		"[*].id":                            "vpc app",
+		"{$[1]['space'].name}":              "Production",
+		`{[0]["space"]["id"]}{"}"}`:         "root}",
+		"{[0].labels[-1]}":                  "prod",
+		"{[0].labels[5]}":                   "",
+		"{[0].space.*}":                     "root Root",
+		"{[*].space.id}":                    "root prod",
+		"{[0].labels}":                      `["network","prod"]`,
+		"{[1].extra.on}":                    "true",
+		"{[0].missing.deeper}":              "",
+		`{[0].id}{"\t"}{[0].count}`:         "vpc\t2",
+		`{[0].space.name} ({[0].space.id})`: "Root (root)",
+	}
+
+	data, err := toOrdered(formatTestItems)
+	require.NoError(t, err)
+
+	for expression, expected := range cases {
+		t.Run(expression, func(t *testing.T) {
+			path, err := parseJSONPath(expression)
+			require.NoError(t, err)
+
+			assert.Equal(t, expected, path.execute(data))
+		})
+	}
+
+	for _, expression := range []string{"{.id", "{.id[}", "{.a[x]}", "{..}", `{"unterminated}`, "{id}"} {
+		_, err := parseJSONPath(expression)
+		assert.Error(t, err, expression)
+	}
+}
+
+func TestOutputStream(t *testing.T) {
+	items := func(yield func(formatTestItem, error) bool) {
+		for _, item := range formatTestItems {
+			if !yield(item, nil) {
+				return
+			}
+		}
+	}
+
+	t.Run("jsonl writes the items as they arrive", func(t *testing.T) {
+		var b strings.Builder
+		failing := func(yield func(formatTestItem, error) bool) {
+			for _, item := range formatTestItems {
+				if !yield(item, nil) {
+					return
+				}
+				assert.Contains(t, b.String(), `{"id":"`+item.ID+`"`)
+			}
+			yield(formatTestItem{}, errors.New("failure"))
+		}
+
+		assert.EqualError(t, outputStream(&b, OutputFormatJSONL, formatJSONL, failing), "failure")
+		assert.Equal(t, 2, strings.Count(b.String(), "\n"))
+	})
+
+	t.Run("other formats get the whole list", func(t *testing.T) {
+		var b strings.Builder
+		require.NoError(t, outputStream(&b, OutputFormatCSV, formatCSV, items))
+		assert.Equal(t, `id,name,count,labels,space.id,space.name,extra.on
+vpc,VPC,2,"[""network"",""prod""]",root,Root,
+app,App: frontend,1,[],prod,Production,true
+`, b.String())
+	})
+
+	t.Run("no items", func(t *testing.T) {
+		var b strings.Builder
+		require.NoError(t, outputStream(&b, OutputFormatJSON, formatJSON, func(func(formatTestItem, error) bool) {}))
+		assert.Equal(t, "[]\n", b.String())
+	})
+}
@@ -0,0 +1,398 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// orderedObject is a JSON object decoded with its keys in their original
+// order, so that formatters output fields in the order of the Go structs.
+type orderedObject struct {
+	keys   []string
+	values map[string]any
+}
+
+// toOrdered converts v to its JSON representation made of orderedObject,
+// []any, string, json.Number, bool and nil values.
+func toOrdered(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	return decodeOrdered(decoder)
+}
+
+func decodeOrdered(decoder *json.Decoder) (any, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch token {
+	case json.Delim('{'):
+		object := orderedObject{values: map[string]any{}}
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+
+			value, err := decodeOrdered(decoder)
+			if err != nil {
+				return nil, err
+			}
+
+			object.keys = append(object.keys, key.(string))
+			object.values[key.(string)] = value
+		}
+
+		_, err := decoder.Token()
+		return object, err
+	case json.Delim('['):
+		list := []any{}
+		for decoder.More() {
+			value, err := decodeOrdered(decoder)
+			if err != nil {
+				return nil, err
+			}
+
+			list = append(list, value)
+		}
+
+		_, err := decoder.Token()
+		return list, err
+	}
+
+	return token, nil
+}
+
+// toPlain converts v to its JSON representation made of map[string]any, []any
+// and scalars, the way the fields are named in the JSON output.
+func toPlain(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var out any
+	err = json.Unmarshal(data, &out)
+	return out, err
+}
+
+func formatYAML(w io.Writer, v any) error {
+	value, err := toOrdered(v)
+	if err != nil {
+		return err
+	}
+
+	node, err := yamlNode(value)
+	if err != nil {
+		return err
+	}
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(node); err != nil {
+		return err
+	}
+
+	return encoder.Close()
+}
+
+func yamlNode(value any) (*yaml.Node, error) {
+	switch v := value.(type) {
+	case orderedObject:
+		node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		for _, key := range v.keys {
+			child, err := yamlNode(v.values[key])
+			if err != nil {
+				return nil, err
+			}
+
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, child)
+		}
+		return node, nil
+	case []any:
+		node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for _, item := range v {
+			child, err := yamlNode(item)
+			if err != nil {
+				return nil, err
+			}
+
+			node.Content = append(node.Content, child)
+		}
+		return node, nil
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			value = i
+		} else if f, err := v.Float64(); err == nil {
+			value = f
+		}
+	}
+
+	node := &yaml.Node{}
+	if err := node.Encode(value); err != nil {
+		return nil, err
+	}
+
+	return node, nil
+}
+
+// formatCSV writes one row per item of a list, or a single row for any other
+// value. Nested objects are flattened into dot-separated columns and lists are
+// written as compact JSON.
+func formatCSV(w io.Writer, v any) error {
+	columns, rows, err := flattenRows(v)
+	if err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	if len(columns) > 0 {
+		if err := writer.Write(columns); err != nil {
+			return err
+		}
+	}
+
+	for _, row := range rows {
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// flattenRows returns the columns and rows of the CSV representation of v.
+func flattenRows(v any) ([]string, [][]string, error) {
+	value, err := toOrdered(v)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	items, isList := value.([]any)
+	if !isList {
+		items = []any{value}
+	}
+
+	var columns []string
+	seen := map[string]bool{}
+	flattened := make([]map[string]string, 0, len(items))
+
+	for _, item := range items {
+		row := map[string]string{}
+		flattenCSV("", item, row, func(column string) {
+			if !seen[column] {
+				seen[column] = true
+				columns = append(columns, column)
+			}
+		})
+		flattened = append(flattened, row)
+	}
+
+	rows := make([][]string, 0, len(flattened))
+	for _, row := range flattened {
+		record := make([]string, len(columns))
+		for i, column := range columns {
+			record[i] = row[column]
+		}
+		rows = append(rows, record)
+	}
+
+	return columns, rows, nil
+}
+
+func flattenCSV(prefix string, value any, row map[string]string, addColumn func(string)) {
+	if object, ok := value.(orderedObject); ok {
+		for _, key := range object.keys {
+			column := key
+			if prefix != "" {
+				column = prefix + "." + key
+			}
+			flattenCSV(column, object.values[key], row, addColumn)
+		}
+		return
+	}
+
+	column := prefix
+	if column == "" {
+		column = "value"
+	}
+
+	addColumn(column)
+	row[column] = scalarString(value)
+}
+
+// scalarString formats scalars as plain text, and everything else as compact JSON.
+func scalarString(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return fmt.Sprint(v)
+	}
+
+	var b strings.Builder
+	writeCompactJSON(&b, value)
+	return b.String()
+}
+
+func writeCompactJSON(b *strings.Builder, value any) {
+	switch v := value.(type) {
+	case orderedObject:
+		b.WriteByte('{')
+		for i, key := range v.keys {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			data, _ := json.Marshal(key)
+			b.Write(data)
+			b.WriteByte(':')
+			writeCompactJSON(b, v.values[key])
+		}
+		b.WriteByte('}')
+	case []any:
+		b.WriteByte('[')
+		for i, item := range v {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			writeCompactJSON(b, item)
+		}
+		b.WriteByte(']')
+	default:
+		data, _ := json.Marshal(v)
+		b.Write(data)
+	}
+}
+
+// MarkdownWriter is implemented by the data of commands with their own
+// Markdown layout, for example a report with several sections.
+type MarkdownWriter interface {
+	WriteMarkdown(w io.Writer) error
+}
+
+// formatMarkdown writes data implementing MarkdownWriter with its own layout,
+// and anything else as a table with the columns of the CSV output.
+func formatMarkdown(w io.Writer, v any) error {
+	if writer, ok := v.(MarkdownWriter); ok {
+		return writer.WriteMarkdown(w)
+	}
+
+	columns, rows, err := flattenRows(v)
+	if err != nil {
+		return err
+	}
+
+	if len(columns) == 0 {
+		return nil
+	}
+
+	return WriteMarkdownTable(w, append([][]string{columns}, rows...))
+}
+
+// WriteMarkdownTable writes the rows as a Markdown table, the first one being
+// the header.
+func WriteMarkdownTable(w io.Writer, rows [][]string) error {
+	var b strings.Builder
+
+	for i, row := range rows {
+		cells := make([]string, len(row))
+		for j, cell := range row {
+			cells[j] = strings.ReplaceAll(strings.ReplaceAll(cell, "|", `\|`), "\n", " ")
+		}
+
+		fmt.Fprintf(&b, "| %s |\n", strings.Join(cells, " | "))
+
+		if i == 0 {
+			fmt.Fprintf(&b, "|%s\n", strings.Repeat(" --- |", len(row)))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// formatJSONL writes every item of a list as a JSON document on its own line,
+// or any other value as a single line.
+func formatJSONL(w io.Writer, v any) error {
+	value, err := toOrdered(v)
+	if err != nil {
+		return err
+	}
+
+	items, isList := value.([]any)
+	if !isList {
+		items = []any{value}
+	}
+
+	for _, item := range items {
+		var b strings.Builder
+		writeCompactJSON(&b, item)
+		b.WriteByte('\n')
+
+		if _, err := io.WriteString(w, b.String()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// newTemplateFormatter renders the data with a Go template. Fields are
+// accessed by their JSON names, for example: -o template='{{range .}}{{.id}}{{"\n"}}{{end}}'.
+func newTemplateFormatter(text string) (OutputFormatter, error) {
+	tmpl, err := template.New("output").Funcs(template.FuncMap{
+		"json": func(v any) (string, error) {
+			data, err := json.Marshal(v)
+			return string(data), err
+		},
+	}).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(w io.Writer, v any) error {
+		data, err := toPlain(v)
+		if err != nil {
+			return err
+		}
+
+		return tmpl.Execute(w, data)
+	}, nil
+}
+
+func newJSONPathFormatter(expression string) (OutputFormatter, error) {
+	path, err := parseJSONPath(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(w io.Writer, v any) error {
+		data, err := toOrdered(v)
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintln(w, path.execute(data))
+		return err
+	}, nil
+}
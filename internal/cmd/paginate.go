@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/spacelift-io/spacectl/client"
+)
+
+// GetPaginateOptions gets the pagination options set with the --limit and
+// --page-size flags. An unset limit means all the items are returned.
+func GetPaginateOptions(cliCmd *cli.Command) (client.PaginateOptions, error) {
+	var opts client.PaginateOptions
+
+	if cliCmd.IsSet(FlagLimit.Name) {
+		limit := cliCmd.Uint(FlagLimit.Name)
+		if limit >= math.MaxInt32 {
+			return opts, fmt.Errorf("limit must be less than %d", math.MaxInt32)
+		}
+
+		opts.Limit = int(limit) //nolint: gosec
+	}
+
+	if cliCmd.IsSet(FlagPageSize.Name) {
+		pageSize := cliCmd.Uint(FlagPageSize.Name)
+		if pageSize == 0 || pageSize > client.DefaultPageSize {
+			return opts, fmt.Errorf("page size must be between 1 and %d", client.DefaultPageSize)
+		}
+
+		opts.PageSize = int(pageSize) //nolint: gosec
+	}
+
+	return opts, nil
+}
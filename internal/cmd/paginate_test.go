@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+
+	"github.com/spacelift-io/spacectl/client"
+)
+
+func TestGetPaginateOptions(t *testing.T) {
+	cases := []struct {
+		args     []string
+		expected client.PaginateOptions
+		err      string
+	}{
+		{expected: client.PaginateOptions{}},
+		{args: []string{"--limit", "120", "--page-size", "10"}, expected: client.PaginateOptions{Limit: 120, PageSize: 10}},
+		{args: []string{"--limit", "0"}, expected: client.PaginateOptions{}},
+		{args: []string{"--limit", "2147483647"}, err: "limit must be less than 2147483647"},
+		{args: []string{"--page-size", "0"}, err: "page size must be between 1 and 50"},
+		{args: []string{"--page-size", "51"}, err: "page size must be between 1 and 50"},
+	}
+
+	for _, c := range cases {
+		var opts client.PaginateOptions
+		var err error
+
+		// Fresh flags, the shared ones keep their state between runs.
+		command := &cli.Command{
+			Flags: []cli.Flag{&cli.UintFlag{Name: FlagLimit.Name}, &cli.UintFlag{Name: FlagPageSize.Name}},
+			Action: func(_ context.Context, cliCmd *cli.Command) error {
+				opts, err = GetPaginateOptions(cliCmd)
+				return nil
+			},
+		}
+		require.NoError(t, command.Run(t.Context(), append([]string{"test"}, c.args...)))
+
+		if c.err != "" {
+			assert.EqualError(t, err, c.err, c.args)
+			continue
+		}
+
+		require.NoError(t, err, c.args)
+		assert.Equal(t, c.expected, opts, c.args)
+	}
+}
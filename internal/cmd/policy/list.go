@@ -2,14 +2,13 @@ package policy
 
 import (
 	"context"
-	"fmt"
-	"slices"
+	"iter"
 	"strings"
 
-	"github.com/pkg/errors"
 	"github.com/shurcooL/graphql"
 	"github.com/urfave/cli/v3"
 
+	"github.com/spacelift-io/spacectl/client/policies"
 	"github.com/spacelift-io/spacectl/client/structs"
 	"github.com/spacelift-io/spacectl/internal/cmd"
 	"github.com/spacelift-io/spacectl/internal/cmd/authenticated"
@@ -18,65 +17,48 @@ import (
 type listCommand struct{}
 
 func (c *listCommand) list(ctx context.Context, cliCmd *cli.Command) error {
-	outputFormat, err := cmd.GetOutputFormat(cliCmd)
+	outputFormat, formatter, err := cmd.GetOutputFormat(cliCmd)
 	if err != nil {
 		return err
 	}
 
-	var limit *uint
-	if cliCmd.IsSet(cmd.FlagLimit.Name) {
-		limit = new(cliCmd.Uint(cmd.FlagLimit.Name))
+	opts, err := cmd.GetPaginateOptions(cliCmd)
+	if err != nil {
+		return err
 	}
 
-	var search *string
+	var input structs.SearchInput
 	if cliCmd.IsSet(cmd.FlagSearch.Name) {
-		search = new(cliCmd.String(cmd.FlagSearch.Name))
+		input.FullTextSearch = new(graphql.String(cliCmd.String(cmd.FlagSearch.Name)))
 	}
 
 	switch outputFormat {
 	case cmd.OutputFormatTable:
-		return c.listTable(ctx, cliCmd, search, limit)
-	case cmd.OutputFormatJSON:
-		return c.listJSON(ctx, search, limit)
-	}
-
-	return fmt.Errorf("unknown output format: %v", outputFormat)
-}
-
-func (c *listCommand) listTable(ctx context.Context, cliCmd *cli.Command, search *string, limit *uint) error {
-	var first *graphql.Int
-	if limit != nil {
-		first = new(graphql.Int(*limit)) //nolint: gosec
-	}
-
-	var fullTextSearch *graphql.String
-	if search != nil {
-		fullTextSearch = new(graphql.String(*search))
-	}
-
-	input := structs.SearchInput{
-		First:          first,
-		FullTextSearch: fullTextSearch,
-		OrderBy: &structs.QueryOrder{
+		input.OrderBy = &structs.QueryOrder{
 			Field:     "name",
 			Direction: "DESC",
-		},
-	}
+		}
 
-	policies, err := c.searchAllPolicies(ctx, input)
-	if err != nil {
-		return err
+		return c.listTable(cliCmd, policies.New(authenticated.Client(ctx)).All(ctx, input, opts))
+	default:
+		return cmd.OutputStream(outputFormat, formatter, policies.New(authenticated.Client(ctx)).All(ctx, input, opts))
 	}
+}
 
+func (c *listCommand) listTable(cliCmd *cli.Command, found iter.Seq2[policies.Policy, error]) error {
 	columns := []string{"Name", "ID", "Description", "Type", "Space", "Updated At", "Labels"}
 	tableData := [][]string{columns}
 
-	for _, b := range policies {
+	for b, err := range found {
+		if err != nil {
+			return err
+		}
+
 		row := []string{
 			b.Name,
 			b.ID,
 			b.Description,
-			b.Type,
+			string(b.Type),
 			b.Space.ID,
 			cmd.HumanizeUnixSeconds(b.UpdatedAt),
 			strings.Join(b.Labels, ", "),
@@ -91,116 +73,6 @@ func (c *listCommand) listTable(ctx context.Context, cliCmd *cli.Command, search
 	return cmd.OutputTable(tableData, true)
 }
 
-func (c *listCommand) listJSON(ctx context.Context, search *string, limit *uint) error {
-	var first *graphql.Int
-	if limit != nil {
-		first = new(graphql.Int(*limit)) //nolint: gosec
-	}
-
-	var fullTextSearch *graphql.String
-	if search != nil {
-		fullTextSearch = new(graphql.String(*search))
-	}
-
-	policies, err := c.searchAllPolicies(ctx, structs.SearchInput{
-		First:          first,
-		FullTextSearch: fullTextSearch,
-	})
-	if err != nil {
-		return err
-	}
-
-	return cmd.OutputJSON(policies)
-}
-
-func (c *listCommand) searchAllPolicies(ctx context.Context, input structs.SearchInput) ([]policyNode, error) {
-	const maxPageSize = 50
-
-	var limit int
-	if input.First != nil {
-		limit = int(*input.First)
-	}
-	fetchAll := limit == 0
-
-	out := []policyNode{}
-	pageInput := structs.SearchInput{
-		First:          graphql.NewInt(maxPageSize),
-		FullTextSearch: input.FullTextSearch,
-	}
-	for {
-		if !fetchAll {
-			// Fetch exactly the number of items requested
-			pageInput.First = new(
-				//nolint: gosec
-				graphql.Int(
-					slices.Min([]int{maxPageSize, limit - len(out)}),
-				),
-			)
-		}
-
-		result, err := searchPolicies(ctx, pageInput)
-		if err != nil {
-			return nil, err
-		}
-
-		out = append(out, result.Policies...)
-
-		if result.PageInfo.HasNextPage && (fetchAll || limit > len(out)) {
-			pageInput.After = new(graphql.String(result.PageInfo.EndCursor))
-		} else {
-			break
-		}
-	}
-
-	return out, nil
-}
-
-type policyNode struct {
-	ID          string `graphql:"id" json:"id"`
-	Name        string `graphql:"name" json:"name"`
-	Description string `graphql:"description" json:"description"`
-	Body        string `graphql:"body" json:"body"`
-	Space       struct {
-		ID          string `graphql:"id" json:"id"`
-		Name        string `graphql:"name" json:"name"`
-		AccessLevel string `graphql:"accessLevel" json:"accessLevel"`
-	} `graphql:"spaceDetails" json:"spaceDetails"`
-	CreatedAt int      `graphql:"createdAt" json:"createdAt"`
-	UpdatedAt int      `graphql:"updatedAt" json:"updatedAt"`
-	Type      string   `graphql:"type" json:"type"`
-	Labels    []string `graphql:"labels" json:"labels"`
-}
-
-type searchPoliciesResult struct {
-	Policies []policyNode
-	PageInfo structs.PageInfo
-}
-
-func searchPolicies(ctx context.Context, input structs.SearchInput) (searchPoliciesResult, error) {
-	var query struct {
-		SearchPoliciesOutput struct {
-			Edges []struct {
-				Node policyNode `graphql:"node"`
-			} `graphql:"edges"`
-			PageInfo structs.PageInfo `graphql:"pageInfo"`
-		} `graphql:"searchPolicies(input: $input)"`
-	}
-
-	if err := authenticated.Client().Query(
-		ctx,
-		&query,
-		map[string]any{"input": input},
-	); err != nil {
-		return searchPoliciesResult{}, errors.Wrap(err, "failed search for policies")
-	}
-
-	nodes := make([]policyNode, 0)
-	for _, q := range query.SearchPoliciesOutput.Edges {
-		nodes = append(nodes, q.Node)
-	}
-
-	return searchPoliciesResult{
-		Policies: nodes,
-		PageInfo: query.SearchPoliciesOutput.PageInfo,
-	}, nil
+func searchPolicies(ctx context.Context, input structs.SearchInput) (policies.Page, error) {
+	return policies.New(authenticated.Client(ctx)).Search(ctx, input)
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/shurcooL/graphql"
 
+	"github.com/spacelift-io/spacectl/client/policies"
 	"github.com/spacelift-io/spacectl/client/structs"
 	"github.com/spacelift-io/spacectl/internal/cmd/authenticated"
 )
@@ -23,6 +24,41 @@ func RegisterMCPTools(s *server.MCPServer) {
 	registerListPolicySamplesIndexedTool(s)
 }
 
+// RegisterMCPResources registers the policy documents.
+func RegisterMCPResources(s *server.MCPServer) {
+	template := mcp.NewResourceTemplate("spacelift://policy/{policy_id}", "Policy",
+		mcp.WithTemplateDescription("A Spacelift policy with its Rego body, type, space and labels."),
+		mcp.WithTemplateMIMEType("application/json"),
+	)
+
+	s.AddResourceTemplate(template, func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		if _, err := authenticated.Ensure(ctx, nil); err != nil {
+			return nil, err
+		}
+
+		policyID, _ := request.Params.Arguments["policy_id"].(string)
+		if ids, ok := request.Params.Arguments["policy_id"].([]string); ok && len(ids) > 0 {
+			policyID = ids[0]
+		}
+
+		policy, found, err := getPolicyByID(ctx, policyID)
+		if err != nil {
+			return nil, err
+		}
+
+		if !found {
+			return nil, fmt.Errorf("policy %q not found", policyID)
+		}
+
+		policyJSON, err := json.Marshal(policy)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal policy to JSON")
+		}
+
+		return []mcp.ResourceContents{mcp.TextResourceContents{URI: request.Params.URI, MIMEType: "application/json", Text: string(policyJSON)}}, nil
+	})
+}
+
 func registerListPoliciesTool(s *server.MCPServer) {
 	policiesTool := mcp.NewTool("list_policies",
 		mcp.WithDescription(`Retrieve a paginated list of Spacelift policies. Returns policies you have access to with their metadata.`),
@@ -60,6 +96,11 @@ func registerListPoliciesTool(s *server.MCPServer) {
 			return nil, errors.Wrap(err, "failed to search policies")
 		}
 
+		result.Policies, err = authenticated.FilterBySpace(ctx, result.Policies, func(p policies.Policy) string { return p.Space.ID })
+		if err != nil {
+			return nil, err
+		}
+
 		policiesJSON, err := json.Marshal(result.Policies)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to marshal policies to JSON")
@@ -143,7 +184,7 @@ func registerListPolicySamplesTool(s *server.MCPServer) {
 			"policyId": graphql.ID(policyID),
 		}
 
-		if err := authenticated.Client().Query(ctx, &query, variables); err != nil {
+		if err := authenticated.Client(ctx).Query(ctx, &query, variables); err != nil {
 			return nil, errors.Wrapf(err, "failed to query for policy ID %q", policyID)
 		}
 
@@ -199,7 +240,7 @@ func registerGetPolicySampleTool(s *server.MCPServer) {
 			"key":      graphql.String(sampleKey),
 		}
 
-		if err := authenticated.Client().Query(ctx, &query, variables); err != nil {
+		if err := authenticated.Client(ctx).Query(ctx, &query, variables); err != nil {
 			return nil, errors.Wrapf(err, "failed to query for policy sample")
 		}
 
@@ -29,6 +29,7 @@ func Command() cmd.Command {
 							Flags: []cli.Flag{
 								cmd.FlagOutputFormat,
 								cmd.FlagLimit,
+								cmd.FlagPageSize,
 								cmd.FlagSearch,
 							},
 							Action: (&listCommand{}).list,
@@ -128,6 +129,7 @@ func Command() cmd.Command {
 								cmd.FlagNoColor,
 								flagRequiredPolicyID,
 								cmd.FlagLimit,
+								cmd.FlagPageSize,
 								flagOutcomeFilter,
 							},
 							Action:    samplesIndexed(),
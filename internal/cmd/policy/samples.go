@@ -26,7 +26,7 @@ type samplesCommand struct{}
 func (c *samplesCommand) list(ctx context.Context, cliCmd *cli.Command) error {
 	policyID := cliCmd.String(flagRequiredPolicyID.Name)
 
-	outputFormat, err := cmd.GetOutputFormat(cliCmd)
+	outputFormat, formatter, err := cmd.GetOutputFormat(cliCmd)
 	if err != nil {
 		return err
 	}
@@ -43,11 +43,9 @@ func (c *samplesCommand) list(ctx context.Context, cliCmd *cli.Command) error {
 	switch outputFormat {
 	case cmd.OutputFormatTable:
 		return c.samplesPolicyTable(b)
-	case cmd.OutputFormatJSON:
-		return cmd.OutputJSON(b)
+	default:
+		return cmd.Output(formatter, b)
 	}
-
-	return fmt.Errorf("unknown output format: %v", outputFormat)
 }
 
 func (c *samplesCommand) getSamplesPolicyByID(ctx context.Context, policyID string) (policyEvaluation, bool, error) {
@@ -59,7 +57,7 @@ func (c *samplesCommand) getSamplesPolicyByID(ctx context.Context, policyID stri
 		"policyId": graphql.ID(policyID),
 	}
 
-	if err := authenticated.Client().Query(ctx, &query, variables); err != nil {
+	if err := authenticated.Client(ctx).Query(ctx, &query, variables); err != nil {
 		return policyEvaluation{}, false, errors.Wrapf(err, "failed to query for policyEvaluation ID %q", policyID)
 	}
 
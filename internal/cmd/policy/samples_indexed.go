@@ -2,13 +2,13 @@ package policy
 
 import (
 	"context"
-	"fmt"
-	"slices"
+	"iter"
 
 	"github.com/pkg/errors"
 	"github.com/shurcooL/graphql"
 	"github.com/urfave/cli/v3"
 
+	"github.com/spacelift-io/spacectl/client"
 	"github.com/spacelift-io/spacectl/client/structs"
 	"github.com/spacelift-io/spacectl/internal/cmd"
 	"github.com/spacelift-io/spacectl/internal/cmd/authenticated"
@@ -18,111 +18,53 @@ func samplesIndexed() cli.ActionFunc {
 	return func(ctx context.Context, cliCmd *cli.Command) error {
 		policyID := cliCmd.String(flagRequiredPolicyID.Name)
 
-		outputFormat, err := cmd.GetOutputFormat(cliCmd)
+		outputFormat, formatter, err := cmd.GetOutputFormat(cliCmd)
 		if err != nil {
 			return err
 		}
 
-		var limit *uint
-		if cliCmd.IsSet(cmd.FlagLimit.Name) {
-			limit = new(cliCmd.Uint(cmd.FlagLimit.Name))
+		opts, err := cmd.GetPaginateOptions(cliCmd)
+		if err != nil {
+			return err
 		}
 
-		var outcome *string
+		// Build predicates for outcome filter
+		var predicates []structs.QueryPredicate
 		if cliCmd.IsSet(flagOutcomeFilter.Name) {
-			outcome = new(cliCmd.String(flagOutcomeFilter.Name))
+			predicates = append(predicates, structs.QueryPredicate{
+				Field: graphql.String("outcome"),
+				Constraint: structs.QueryFieldConstraint{
+					StringMatches: &[]graphql.String{graphql.String(cliCmd.String(flagOutcomeFilter.Name))},
+				},
+			})
 		}
 
+		records := paginateEvaluationRecords(ctx, policyID, structs.SearchInput{
+			Predicates: &predicates,
+			OrderBy: &structs.QueryOrder{
+				Field:     "createdAt",
+				Direction: "DESC",
+			},
+		}, opts)
+
 		switch outputFormat {
 		case cmd.OutputFormatTable:
-			return samplesIndexedTable(ctx, policyID, outcome, limit)
-		case cmd.OutputFormatJSON:
-			return samplesIndexedJSON(ctx, policyID, outcome, limit)
+			return samplesIndexedTable(records)
+		default:
+			return cmd.OutputStream(outputFormat, formatter, records)
 		}
-
-		return fmt.Errorf("unknown output format: %v", outputFormat)
 	}
 }
 
-func samplesIndexedJSON(
-	ctx context.Context,
-	policyID string,
-	outcome *string,
-	limit *uint,
-) error {
-	var first *graphql.Int
-	if limit != nil {
-		first = new(graphql.Int(*limit)) //nolint: gosec
-	}
-
-	// Build predicates for outcome filter
-	var predicates []structs.QueryPredicate
-	if outcome != nil {
-		predicates = append(predicates, structs.QueryPredicate{
-			Field: graphql.String("outcome"),
-			Constraint: structs.QueryFieldConstraint{
-				StringMatches: &[]graphql.String{graphql.String(*outcome)},
-			},
-		})
-	}
-
-	records, err := searchAllEvaluationRecords(ctx, policyID, structs.SearchInput{
-		First:          first,
-		FullTextSearch: nil,
-		Predicates:     &predicates,
-		OrderBy: &structs.QueryOrder{
-			Field:     "createdAt",
-			Direction: "DESC",
-		},
-	})
-	if err != nil {
-		return err
-	}
-
-	return cmd.OutputJSON(records)
-}
-
-func samplesIndexedTable(
-	ctx context.Context,
-	policyID string,
-	outcome *string,
-	limit *uint,
-) error {
-	var first *graphql.Int
-	if limit != nil {
-		first = new(graphql.Int(*limit)) //nolint: gosec
-	}
-
-	// Build predicates for outcome filter
-	var predicates []structs.QueryPredicate
-	if outcome != nil {
-		predicates = append(predicates, structs.QueryPredicate{
-			Field: graphql.String("outcome"),
-			Constraint: structs.QueryFieldConstraint{
-				StringMatches: &[]graphql.String{graphql.String(*outcome)},
-			},
-		})
-	}
-
-	input := structs.SearchInput{
-		First:          first,
-		FullTextSearch: nil,
-		Predicates:     &predicates,
-		OrderBy: &structs.QueryOrder{
-			Field:     "createdAt",
-			Direction: "DESC",
-		},
-	}
-
-	records, err := searchAllEvaluationRecords(ctx, policyID, input)
-	if err != nil {
-		return err
-	}
-
+func samplesIndexedTable(records iter.Seq2[evaluationRecordNode, error]) error {
 	columns := []string{"Key", "Outcome", "Timestamp"}
 	tableData := [][]string{columns}
 
-	for _, record := range records {
+	for record, err := range records {
+		if err != nil {
+			return err
+		}
+
 		row := []string{
 			record.Key,
 			record.Outcome,
@@ -135,48 +77,11 @@ func samplesIndexedTable(
 	return cmd.OutputTable(tableData, true)
 }
 
-func searchAllEvaluationRecords(ctx context.Context, policyID string, input structs.SearchInput) ([]evaluationRecordNode, error) {
-	const maxPageSize = 50
-
-	var limit int
-	if input.First != nil {
-		limit = int(*input.First)
-	}
-	fetchAll := limit == 0
-
-	out := []evaluationRecordNode{}
-	pageInput := structs.SearchInput{
-		First:          graphql.NewInt(maxPageSize),
-		FullTextSearch: input.FullTextSearch,
-		OrderBy:        input.OrderBy,
-		Predicates:     input.Predicates,
-	}
-	for {
-		if !fetchAll {
-			// Fetch exactly the number of items requested
-			pageInput.First = new(
-				//nolint: gosec
-				graphql.Int(
-					slices.Min([]int{maxPageSize, limit - len(out)}),
-				),
-			)
-		}
-
-		result, err := searchEvaluationRecords(ctx, policyID, pageInput)
-		if err != nil {
-			return nil, err
-		}
-
-		out = append(out, result.Records...)
-
-		if result.PageInfo.HasNextPage && (fetchAll || limit > len(out)) {
-			pageInput.After = new(graphql.String(result.PageInfo.EndCursor))
-		} else {
-			break
-		}
-	}
-
-	return out, nil
+func paginateEvaluationRecords(ctx context.Context, policyID string, input structs.SearchInput, opts client.PaginateOptions) iter.Seq2[evaluationRecordNode, error] {
+	return client.Paginate(ctx, input, opts, func(ctx context.Context, input structs.SearchInput) (client.Page[evaluationRecordNode], error) {
+		result, err := searchEvaluationRecords(ctx, policyID, input)
+		return client.Page[evaluationRecordNode]{Items: result.Records, PageInfo: result.PageInfo}, err
+	})
 }
 
 type evaluationRecordNode struct {
@@ -208,7 +113,7 @@ func searchEvaluationRecords(ctx context.Context, policyID string, input structs
 		"input": input,
 	}
 
-	if err := authenticated.Client().Query(ctx, &query, variables); err != nil {
+	if err := authenticated.Client(ctx).Query(ctx, &query, variables); err != nil {
 		return searchEvaluationRecordsResult{}, errors.Wrap(err, "failed to search evaluation records")
 	}
 
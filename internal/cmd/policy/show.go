@@ -7,37 +7,21 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/pterm/pterm"
-	"github.com/shurcooL/graphql"
 	"github.com/urfave/cli/v3"
 
+	"github.com/spacelift-io/spacectl/client/policies"
 	"github.com/spacelift-io/spacectl/internal/cmd"
 	"github.com/spacelift-io/spacectl/internal/cmd/authenticated"
 )
 
-type PolicyType string
-
-type policy struct {
-	ID          string `graphql:"id" json:"id"`
-	Name        string `graphql:"name" json:"name"`
-	Description string `graphql:"description" json:"description"`
-	Body        string `graphql:"body" json:"body"`
-	Space       struct {
-		ID          string `graphql:"id" json:"id"`
-		Name        string `graphql:"name" json:"name"`
-		AccessLevel string `graphql:"accessLevel" json:"accessLevel"`
-	} `graphql:"spaceDetails" json:"spaceDetails"`
-	CreatedAt int        `graphql:"createdAt" json:"createdAt"`
-	UpdatedAt int        `graphql:"updatedAt" json:"updatedAt"`
-	Type      PolicyType `graphql:"type" json:"type"`
-	Labels    []string   `graphql:"labels" json:"labels"`
-}
+type policy = policies.Policy
 
 type showCommand struct{}
 
 func (c *showCommand) show(ctx context.Context, cliCmd *cli.Command) error {
 	policyID := cliCmd.String(flagRequiredPolicyID.Name)
 
-	outputFormat, err := cmd.GetOutputFormat(cliCmd)
+	outputFormat, formatter, err := cmd.GetOutputFormat(cliCmd)
 	if err != nil {
 		return err
 	}
@@ -54,31 +38,21 @@ func (c *showCommand) show(ctx context.Context, cliCmd *cli.Command) error {
 	switch outputFormat {
 	case cmd.OutputFormatTable:
 		return c.showPolicyTable(b)
-	case cmd.OutputFormatJSON:
-		return cmd.OutputJSON(b)
+	default:
+		return cmd.Output(formatter, b)
 	}
-
-	return fmt.Errorf("unknown output format: %v", outputFormat)
 }
 
 func getPolicyByID(ctx context.Context, policyID string) (policy, bool, error) {
-	var query struct {
-		Policy *policy `graphql:"policy(id: $policyId)" json:"policy,omitempty"`
-	}
-
-	variables := map[string]any{
-		"policyId": graphql.ID(policyID),
-	}
-
-	if err := authenticated.Client().Query(ctx, &query, variables); err != nil {
-		return policy{}, false, errors.Wrapf(err, "failed to query for policy ID %q", policyID)
-	}
-
-	if query.Policy == nil {
+	found, err := policies.New(authenticated.Client(ctx)).Get(ctx, policyID)
+	if errors.Is(err, policies.ErrNotFound) {
 		return policy{}, false, nil
 	}
+	if err != nil {
+		return policy{}, false, err
+	}
 
-	return *query.Policy, true, nil
+	return *found, true, nil
 }
 
 func (c *showCommand) showPolicyTable(input policy) error {
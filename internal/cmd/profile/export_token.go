@@ -4,11 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"net/http"
 
 	"github.com/urfave/cli/v3"
 
+	"github.com/spacelift-io/spacectl/client"
 	"github.com/spacelift-io/spacectl/internal/cmd"
+	"github.com/spacelift-io/spacectl/internal/cmd/authenticated"
 )
 
 func exportTokenCommand() *cli.Command {
@@ -23,7 +24,12 @@ func exportTokenCommand() *cli.Command {
 				return errors.New("no account is currently selected")
 			}
 
-			session, err := currentProfile.Credentials.Session(ctx, http.DefaultClient)
+			httpClient := client.GetHTTPClient()
+			if err := authenticated.ConfigureHTTPClient(httpClient, currentProfile.Network); err != nil {
+				return err
+			}
+
+			session, err := currentProfile.Credentials.Session(ctx, httpClient)
 			if err != nil {
 				return fmt.Errorf("could not get session: %w", err)
 			}
@@ -79,6 +79,44 @@ var flagNoBrowser = &cli.BoolFlag{
 	Sources:     cli.EnvVars("SPACECTL_NO_BROWSER"),
 }
 
+var flagProxy = &cli.StringFlag{
+	Name:     "proxy",
+	Usage:    "[Optional] `URL` of the HTTP(S) proxy to use for all requests made with this profile",
+	Required: false,
+	Sources:  cli.EnvVars("SPACECTL_LOGIN_PROXY"),
+}
+
+var flagNoProxy = &cli.StringFlag{
+	Name:     "no-proxy",
+	Usage:    "[Optional] comma-separated list of hosts that should bypass the proxy",
+	Required: false,
+	Sources:  cli.EnvVars("SPACECTL_LOGIN_NO_PROXY"),
+}
+
+var flagTLSCA = &cli.StringFlag{
+	Name:      "tls-ca",
+	Usage:     "[Optional] `PATH` to a PEM bundle used to verify the Spacelift endpoint, stored in the profile",
+	Required:  false,
+	Sources:   cli.EnvVars("SPACECTL_LOGIN_TLS_CA"),
+	TakesFile: true,
+}
+
+var flagTLSCert = &cli.StringFlag{
+	Name:      "tls-cert",
+	Usage:     "[Optional] `PATH` to a PEM client certificate used for mutual TLS, stored in the profile",
+	Required:  false,
+	Sources:   cli.EnvVars("SPACECTL_LOGIN_TLS_CERT"),
+	TakesFile: true,
+}
+
+var flagTLSKey = &cli.StringFlag{
+	Name:      "tls-key",
+	Usage:     "[Optional] `PATH` to the PEM private key of the client certificate, stored in the profile",
+	Required:  false,
+	Sources:   cli.EnvVars("SPACECTL_LOGIN_TLS_KEY"),
+	TakesFile: true,
+}
+
 const (
 	usageViewCSVTimeFormat   = "2006-01-02"
 	usageViewCSVDefaultRange = time.Duration(-1*30*24) * time.Hour
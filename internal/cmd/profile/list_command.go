@@ -2,7 +2,6 @@ package profile
 
 import (
 	"context"
-	"fmt"
 	"sort"
 
 	"github.com/urfave/cli/v3"
@@ -38,9 +37,8 @@ func listCommand() *cli.Command {
 				return profiles[i].Alias < profiles[j].Alias
 			})
 
-			var outputFormat cmd.OutputFormat
-			var err error
-			if outputFormat, err = cmd.GetOutputFormat(cliCmd); err != nil {
+			outputFormat, formatter, err := cmd.GetOutputFormat(cliCmd)
+			if err != nil {
 				return err
 			}
 
@@ -58,7 +56,7 @@ func listCommand() *cli.Command {
 
 				return cmd.OutputTable(tableData, true)
 
-			case cmd.OutputFormatJSON:
+			default:
 				var profileList []profileListOutput
 
 				for _, profile := range profiles {
@@ -70,10 +68,8 @@ func listCommand() *cli.Command {
 					})
 				}
 
-				return cmd.OutputJSON(&profileList)
+				return cmd.Output(formatter, &profileList)
 			}
-
-			return fmt.Errorf("unknown output format: %v", outputFormat)
 		},
 	}
 }
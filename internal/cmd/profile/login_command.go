@@ -19,6 +19,7 @@ import (
 	"github.com/spacelift-io/spacectl/browserauth"
 	"github.com/spacelift-io/spacectl/client"
 	"github.com/spacelift-io/spacectl/client/session"
+	"github.com/spacelift-io/spacectl/internal/cmd/authenticated"
 )
 
 func loginCommand() *cli.Command {
@@ -34,6 +35,11 @@ func loginCommand() *cli.Command {
 			flagBindPort,
 			flagEndpoint,
 			flagNoBrowser,
+			flagProxy,
+			flagNoProxy,
+			flagTLSCA,
+			flagTLSCert,
+			flagTLSKey,
 		},
 	}
 }
@@ -43,13 +49,25 @@ func loginAction(ctx context.Context, cliCmd *cli.Command) error {
 
 	var storedCredentials session.StoredCredentials
 
+	network, err := readNetworkSettings(cliCmd)
+	if err != nil {
+		return err
+	}
+
 	// Let's try to re-authenticate user.
 	if apiTokenProfile != nil {
 		storedCredentials.Endpoint = apiTokenProfile.Credentials.Endpoint
 		storedCredentials.Type = apiTokenProfile.Credentials.Type
 		profileAlias = apiTokenProfile.Alias
+	}
 
-		return loginUsingWebBrowser(ctx, cliCmd, &storedCredentials)
+	// Logging in again must not lose the proxy and TLS settings of the profile.
+	if network, err = mergeStoredNetworkSettings(network); err != nil {
+		return err
+	}
+
+	if apiTokenProfile != nil {
+		return loginUsingWebBrowser(ctx, cliCmd, &storedCredentials, network)
 	}
 
 	reader := bufio.NewReader(os.Stdin)
@@ -76,17 +94,102 @@ func loginAction(ctx context.Context, cliCmd *cli.Command) error {
 			return err
 		}
 	case session.CredentialsTypeAPIToken:
-		return loginUsingWebBrowser(ctx, cliCmd, &storedCredentials)
+		return loginUsingWebBrowser(ctx, cliCmd, &storedCredentials, network)
 	default:
 		return fmt.Errorf("invalid selection (%s), please try again", storedCredentials.Type)
 	}
 
+	httpClient := client.GetHTTPClient()
+	if err := authenticated.ConfigureHTTPClient(httpClient, network); err != nil {
+		return err
+	}
+
 	// Check if the credentials are valid before we try persisting them.
-	if _, err := storedCredentials.Session(ctx, client.GetHTTPClient()); err != nil {
+	if _, err := storedCredentials.Session(ctx, httpClient); err != nil {
 		return fmt.Errorf("credentials look invalid: %w", err)
 	}
 
-	return persistAccessCredentials(&storedCredentials)
+	return persistAccessCredentials(&storedCredentials, network)
+}
+
+// readNetworkSettings builds the profile network settings from the login flags,
+// reading the TLS material from the referenced files so that the profile does
+// not depend on them afterwards.
+func readNetworkSettings(cliCmd *cli.Command) (*session.NetworkSettings, error) {
+	network := &session.NetworkSettings{
+		ProxyURL: cliCmd.String(flagProxy.Name),
+		NoProxy:  cliCmd.String(flagNoProxy.Name),
+	}
+
+	files := []struct {
+		flag   *cli.StringFlag
+		target *string
+	}{
+		{flagTLSCA, &network.CACertificate},
+		{flagTLSCert, &network.ClientCertificate},
+		{flagTLSKey, &network.ClientKey},
+	}
+
+	for _, file := range files {
+		path := cliCmd.String(file.flag.Name)
+		if path == "" {
+			continue
+		}
+
+		// #nosec G304
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("could not read --%s file: %w", file.flag.Name, err)
+		}
+
+		*file.target = string(data)
+	}
+
+	if err := network.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid network settings: %w", err)
+	}
+
+	if network.IsEmpty() {
+		return nil, nil
+	}
+
+	return network, nil
+}
+
+// mergeStoredNetworkSettings fills the network settings not given as flags
+// with the ones stored on the profile being logged in to, if it exists.
+func mergeStoredNetworkSettings(network *session.NetworkSettings) (*session.NetworkSettings, error) {
+	existing, err := manager.Get(profileAlias)
+	if err != nil {
+		return nil, err
+	}
+
+	if existing == nil || existing.Network.IsEmpty() {
+		return network, nil
+	}
+
+	merged := *existing.Network
+	if network != nil {
+		if network.ProxyURL != "" {
+			merged.ProxyURL = network.ProxyURL
+		}
+		if network.NoProxy != "" {
+			merged.NoProxy = network.NoProxy
+		}
+		if network.CACertificate != "" {
+			merged.CACertificate = network.CACertificate
+		}
+		if network.ClientCertificate != "" {
+			merged.ClientCertificate = network.ClientCertificate
+			merged.ClientKey = network.ClientKey
+		}
+	}
+
+	if err := merged.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid network settings: %w", err)
+	}
+
+	return &merged, nil
 }
 
 func getCredentialsType(cliCmd *cli.Command) (session.CredentialsType, error) {
@@ -183,7 +286,24 @@ func loginUsingGitHubAccessToken(creds *session.StoredCredentials) error {
 	return nil
 }
 
-func loginUsingWebBrowser(ctx context.Context, _ *cli.Command, creds *session.StoredCredentials) error {
+func loginUsingWebBrowser(ctx context.Context, _ *cli.Command, creds *session.StoredCredentials, network *session.NetworkSettings) error {
+	if err := waitForBrowserLogin(ctx, creds); err != nil {
+		return err
+	}
+
+	// Save the shiny new token
+	if err := persistAccessCredentials(creds, network); err != nil {
+		return err
+	}
+
+	fmt.Println("Done!")
+
+	return nil
+}
+
+// waitForBrowserLogin runs the interactive browser auth flow, storing the
+// token in the credentials. It is a variable so that tests can replace it.
+var waitForBrowserLogin = func(ctx context.Context, creds *session.StoredCredentials) error {
 	// Begin the interactive browser auth flow
 	handler, err := browserauth.BeginWithBindAddress(ctx, creds, bindHost, bindPort)
 	if err != nil {
@@ -207,24 +327,14 @@ func loginUsingWebBrowser(ctx context.Context, _ *cli.Command, creds *session.St
 	defer cancel()
 
 	// Wait for the timeout or an auth callback
-	if err := handler.Wait(waitCtx); err != nil {
-		return err
-	}
-
-	// Save the shiny new token
-	if err := persistAccessCredentials(creds); err != nil {
-		return err
-	}
-
-	fmt.Println("Done!")
-
-	return nil
+	return handler.Wait(waitCtx)
 }
 
-func persistAccessCredentials(creds *session.StoredCredentials) error {
+func persistAccessCredentials(creds *session.StoredCredentials, network *session.NetworkSettings) error {
 	return manager.Create(&session.Profile{
 		Alias:       profileAlias,
 		Credentials: creds,
+		Network:     network,
 	})
 }
 
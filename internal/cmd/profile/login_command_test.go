@@ -0,0 +1,86 @@
+package profile
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacelift-io/spacectl/client/fakeapi"
+	"github.com/spacelift-io/spacectl/client/session"
+)
+
+func TestLoginKeepsNetworkSettings(t *testing.T) {
+	server, err := fakeapi.NewServer(fakeapi.DefaultFixtures())
+	require.NoError(t, err)
+	t.Cleanup(server.Close)
+
+	// The proxy is unreachable, so the credentials can only be checked if the
+	// stored settings are used, bypassing it for the fake API.
+	stored := &session.NetworkSettings{ProxyURL: "http://127.0.0.1:1", NoProxy: "127.0.0.1"}
+
+	previousWait := waitForBrowserLogin
+	waitForBrowserLogin = func(_ context.Context, creds *session.StoredCredentials) error {
+		creds.AccessToken = "token"
+		return nil
+	}
+	t.Cleanup(func() { waitForBrowserLogin = previousWait })
+
+	login := func(t *testing.T, existing *session.StoredCredentials, args ...string) *session.Profile {
+		t.Helper()
+
+		dir := t.TempDir()
+		t.Setenv(session.EnvSpaceliftConfigDirectory, dir)
+		t.Cleanup(func() { apiTokenProfile = nil })
+
+		profiles, err := session.NewProfileManager(dir)
+		require.NoError(t, err)
+		require.NoError(t, profiles.Create(&session.Profile{Alias: "account", Credentials: existing, Network: stored}))
+
+		require.NoError(t, Command().Run(t.Context(), append([]string{"profile", "login"}, args...)))
+
+		profiles, err = session.NewProfileManager(dir)
+		require.NoError(t, err)
+		profile, err := profiles.Get("account")
+		require.NoError(t, err)
+
+		return profile
+	}
+
+	apiToken := &session.StoredCredentials{Type: session.CredentialsTypeAPIToken, Endpoint: server.URL, AccessToken: "old"}
+
+	t.Run("API key", func(t *testing.T) {
+		t.Setenv(session.EnvSpaceliftAPIKeyID, "key-id")
+		t.Setenv(session.EnvSpaceliftAPIKeySecret, "key-secret")
+
+		profile := login(t, apiToken, "account", "--endpoint", server.URL, "--method", methodAPI)
+		assert.Equal(t, session.CredentialsTypeAPIKey, profile.Credentials.Type)
+		assert.Equal(t, stored, profile.Network)
+	})
+
+	t.Run("GitHub token", func(t *testing.T) {
+		t.Setenv(session.EnvSpaceliftAPIGitHubToken, "github-token")
+
+		profile := login(t, apiToken, "account", "--endpoint", server.URL, "--method", methodGithub)
+		assert.Equal(t, session.CredentialsTypeGitHubToken, profile.Credentials.Type)
+		assert.Equal(t, stored, profile.Network)
+	})
+
+	t.Run("browser", func(t *testing.T) {
+		profile := login(t, apiToken, "account", "--endpoint", server.URL, "--method", methodBrowser)
+		assert.Equal(t, "token", profile.Credentials.AccessToken)
+		assert.Equal(t, stored, profile.Network)
+	})
+
+	t.Run("browser re-login of the current profile", func(t *testing.T) {
+		profile := login(t, apiToken)
+		assert.Equal(t, "token", profile.Credentials.AccessToken)
+		assert.Equal(t, stored, profile.Network)
+	})
+
+	t.Run("flags override the stored settings", func(t *testing.T) {
+		profile := login(t, apiToken, "account", "--endpoint", server.URL, "--method", methodBrowser, "--proxy", "http://proxy.example:3128")
+		assert.Equal(t, &session.NetworkSettings{ProxyURL: "http://proxy.example:3128", NoProxy: "127.0.0.1"}, profile.Network)
+	})
+}
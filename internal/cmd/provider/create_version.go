@@ -88,7 +88,7 @@ func createVersion(useHeadersFromAPI bool) cli.ActionFunc {
 				} `graphql:"terraformProviderVersionCreate(provider: $provider, input: $input)"`
 			}
 
-			if err := authenticated.Client().Mutate(ctx, &createMutation, variables); err != nil {
+			if err := authenticated.Client(ctx).Mutate(ctx, &createMutation, variables); err != nil {
 				return err
 			}
 
@@ -110,7 +110,7 @@ func createVersion(useHeadersFromAPI bool) cli.ActionFunc {
 				} `graphql:"terraformProviderVersionCreate(provider: $provider, input: $input)"`
 			}
 
-			if err := authenticated.Client().Mutate(ctx, &createMutation, variables); err != nil {
+			if err := authenticated.Client(ctx).Mutate(ctx, &createMutation, variables); err != nil {
 				return err
 			}
 
@@ -171,7 +171,7 @@ func createVersion(useHeadersFromAPI bool) cli.ActionFunc {
 
 		log("Uploading the changelog\n")
 
-		if err := authenticated.Client().Mutate(ctx, &changelogMutation, variables); err != nil {
+		if err := authenticated.Client(ctx).Mutate(ctx, &changelogMutation, variables); err != nil {
 			return errors.Wrap(err, "could not update changelog")
 		}
 
@@ -205,7 +205,7 @@ func registerPlatform(ctx context.Context, dir string, versionID string, artifac
 		},
 	}
 
-	if err := authenticated.Client().Mutate(ctx, &mutation, variables); err != nil {
+	if err := authenticated.Client(ctx).Mutate(ctx, &mutation, variables); err != nil {
 		return err
 	}
 
@@ -246,7 +246,7 @@ func registerPlatformV2(ctx context.Context, dir string, versionID string, artif
 		},
 	}
 
-	if err := authenticated.Client().Mutate(ctx, &mutation, variables); err != nil {
+	if err := authenticated.Client(ctx).Mutate(ctx, &mutation, variables); err != nil {
 		return err
 	}
 
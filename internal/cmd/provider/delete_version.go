@@ -21,7 +21,7 @@ func deleteVersion() cli.ActionFunc {
 
 		variables := map[string]any{"version": graphql.ID(versionID)}
 
-		if err := authenticated.Client().Mutate(ctx, &deleteMutation, variables); err != nil {
+		if err := authenticated.Client(ctx).Mutate(ctx, &deleteMutation, variables); err != nil {
 			return fmt.Errorf("could not delete Terraform provider version: %w", err)
 		}
 
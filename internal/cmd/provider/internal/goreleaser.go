@@ -14,6 +14,8 @@ import (
 	"strings"
 
 	"github.com/pkg/errors"
+
+	"github.com/spacelift-io/spacectl/client"
 )
 
 // GoReleaserVersionData contains the data we get from GoReleaser's distribution
@@ -161,7 +163,7 @@ func (a *GoReleaserArtifact) Upload(ctx context.Context, dir string, url string,
 		request.Header.Set(k, header.Get(k))
 	}
 
-	response, err := http.DefaultClient.Do(request)
+	response, err := client.GetTransferHTTPClient().Do(request)
 	if err != nil {
 		return errors.Wrapf(err, "could not upload %s", a.Name)
 	}
@@ -2,7 +2,6 @@ package provider
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/urfave/cli/v3"
 
@@ -13,7 +12,7 @@ import (
 
 func listGPGKeys() cli.ActionFunc {
 	return func(ctx context.Context, cliCmd *cli.Command) error {
-		outputFormat, err := cmd.GetOutputFormat(cliCmd)
+		outputFormat, formatter, err := cmd.GetOutputFormat(cliCmd)
 		if err != nil {
 			return err
 		}
@@ -22,13 +21,11 @@ func listGPGKeys() cli.ActionFunc {
 			GPGKeys internal.GPGKeys `graphql:"gpgKeys"`
 		}
 
-		if err := authenticated.Client().Query(ctx, &query, nil); err != nil {
+		if err := authenticated.Client(ctx).Query(ctx, &query, nil); err != nil {
 			return err
 		}
 
 		switch outputFormat {
-		case cmd.OutputFormatJSON:
-			return cmd.OutputJSON(query)
 		case cmd.OutputFormatTable:
 			rows := [][]string{query.GPGKeys.Headers()}
 
@@ -38,7 +35,7 @@ func listGPGKeys() cli.ActionFunc {
 
 			return cmd.OutputTable(rows, true)
 		default:
-			return fmt.Errorf("unknown output format: %s", outputFormat)
+			return cmd.Output(formatter, query)
 		}
 	}
 }
@@ -14,7 +14,7 @@ import (
 
 func listVersions() cli.ActionFunc {
 	return func(ctx context.Context, cliCmd *cli.Command) (err error) {
-		outputFormat, err := cmd.GetOutputFormat(cliCmd)
+		outputFormat, formatter, err := cmd.GetOutputFormat(cliCmd)
 		if err != nil {
 			return err
 		}
@@ -28,7 +28,7 @@ func listVersions() cli.ActionFunc {
 		providerType := cliCmd.String(flagProviderType.Name)
 
 		variables := map[string]any{"id": graphql.ID(providerType)}
-		if err := authenticated.Client().Query(ctx, &query, variables); err != nil {
+		if err := authenticated.Client(ctx).Query(ctx, &query, variables); err != nil {
 			return fmt.Errorf("could not list Terraform provider versions: %w", err)
 		}
 
@@ -39,8 +39,6 @@ func listVersions() cli.ActionFunc {
 		versions := query.TerraformProvider.Versions
 
 		switch outputFormat {
-		case cmd.OutputFormatJSON:
-			return cmd.OutputJSON(map[string]any{"versions": versions})
 		case cmd.OutputFormatTable:
 			rows := [][]string{versions.Headers()}
 			for _, version := range versions {
@@ -49,7 +47,7 @@ func listVersions() cli.ActionFunc {
 
 			return cmd.OutputTable(rows, true)
 		default:
-			return fmt.Errorf("unknown output format: %s", outputFormat)
+			return cmd.Output(formatter, map[string]any{"versions": versions})
 		}
 	}
 }
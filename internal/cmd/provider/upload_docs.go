@@ -40,7 +40,7 @@ func uploadDocs() cli.ActionFunc {
 			"archive": graphql.String(encoded),
 		}
 
-		if err := authenticated.Client().Mutate(ctx, &mutation, variables); err != nil {
+		if err := authenticated.Client(ctx).Mutate(ctx, &mutation, variables); err != nil {
 			return fmt.Errorf("could not upload docs for provider version: %w", err)
 		}
 
@@ -31,7 +31,7 @@ func markRunExternalDependencyAsCompleted(ctx context.Context, cliCmd *cli.Comma
 		"status":     RunExternalDependencyStatus(strings.ToUpper(status)),
 	}
 
-	if err := authenticated.Client().Mutate(ctx, &mutation, variables); err != nil {
+	if err := authenticated.Client(ctx).Mutate(ctx, &mutation, variables); err != nil {
 		return err
 	}
 
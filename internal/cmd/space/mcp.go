@@ -44,10 +44,16 @@ func registerListSpacesTool(s *server.MCPServer) {
 			Spaces []spaceNode `graphql:"spaces"`
 		}
 
-		if err := authenticated.Client().Query(ctx, &query, map[string]any{}); err != nil {
+		if err := authenticated.Client(ctx).Query(ctx, &query, map[string]any{}); err != nil {
 			return nil, errors.Wrap(err, "failed to query spaces")
 		}
 
+		spaces, err := authenticated.FilterBySpace(ctx, query.Spaces, func(s spaceNode) string { return s.ID })
+		if err != nil {
+			return nil, err
+		}
+		query.Spaces = spaces
+
 		if len(query.Spaces) == 0 {
 			return mcp.NewToolResultText("No spaces found."), nil
 		}
@@ -87,7 +93,7 @@ func registerGetSpaceTool(s *server.MCPServer) {
 			"spaceId": graphql.ID(spaceID),
 		}
 
-		if err := authenticated.Client().Query(ctx, &query, variables); err != nil {
+		if err := authenticated.Client(ctx).Query(ctx, &query, variables); err != nil {
 			return nil, errors.Wrapf(err, "failed to query for space ID %q", spaceID)
 		}
 
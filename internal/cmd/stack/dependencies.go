@@ -2,7 +2,6 @@ package stack
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/pkg/errors"
 	"github.com/shurcooL/graphql"
@@ -13,7 +12,7 @@ import (
 )
 
 func dependenciesOn(ctx context.Context, cliCmd *cli.Command) error {
-	outputFormat, err := cmd.GetOutputFormat(cliCmd)
+	outputFormat, formatter, err := cmd.GetOutputFormat(cliCmd)
 	if err != nil {
 		return err
 	}
@@ -26,15 +25,13 @@ func dependenciesOn(ctx context.Context, cliCmd *cli.Command) error {
 	switch outputFormat {
 	case cmd.OutputFormatTable:
 		return cmd.OutputTable(got.dependsOnTableData(), true)
-	case cmd.OutputFormatJSON:
-		return cmd.OutputJSON(got.DependsOn)
+	default:
+		return cmd.Output(formatter, got.DependsOn)
 	}
-
-	return fmt.Errorf("unknown output format: %v", outputFormat)
 }
 
 func dependenciesOff(ctx context.Context, cliCmd *cli.Command) error {
-	outputFormat, err := cmd.GetOutputFormat(cliCmd)
+	outputFormat, formatter, err := cmd.GetOutputFormat(cliCmd)
 	if err != nil {
 		return err
 	}
@@ -47,11 +44,9 @@ func dependenciesOff(ctx context.Context, cliCmd *cli.Command) error {
 	switch outputFormat {
 	case cmd.OutputFormatTable:
 		return cmd.OutputTable(got.dependedOnByTableData(), true)
-	case cmd.OutputFormatJSON:
-		return cmd.OutputJSON(got.IsDependedOnBy)
+	default:
+		return cmd.Output(formatter, got.IsDependedOnBy)
 	}
-
-	return fmt.Errorf("unknown output format: %v", outputFormat)
 }
 
 func dependenciesListOneStack(ctx context.Context, cliCmd *cli.Command) (*stackWithDependencies, error) {
@@ -65,7 +60,7 @@ func dependenciesListOneStack(ctx context.Context, cliCmd *cli.Command) (*stackW
 	}
 
 	variables := map[string]any{"id": graphql.ID(id)}
-	if err := authenticated.Client().Query(ctx, &query, variables); err != nil {
+	if err := authenticated.Client(ctx).Query(ctx, &query, variables); err != nil {
 		return nil, errors.Wrap(err, "failed to query one stack")
 	}
 
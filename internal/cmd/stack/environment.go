@@ -13,26 +13,22 @@ import (
 	"github.com/shurcooL/graphql"
 	"github.com/urfave/cli/v3"
 
+	"github.com/spacelift-io/spacectl/client/stacks"
 	"github.com/spacelift-io/spacectl/internal/cmd"
 	"github.com/spacelift-io/spacectl/internal/cmd/authenticated"
 )
 
 // ConfigType is a type of configuration element.
-type ConfigType string
+type ConfigType = stacks.ConfigType
 
 const (
-	fileTypeConfig   = ConfigType("FILE_MOUNT")
-	envVarTypeConfig = ConfigType("ENVIRONMENT_VARIABLE")
+	fileTypeConfig   = stacks.ConfigTypeFileMount
+	envVarTypeConfig = stacks.ConfigTypeEnvironmentVariable
 )
 
 // ConfigInput represents the input required to create or update a config
 // element.
-type ConfigInput struct {
-	ID        graphql.ID      `json:"id"`
-	Type      ConfigType      `json:"type"`
-	Value     graphql.String  `json:"value"`
-	WriteOnly graphql.Boolean `json:"writeOnly"`
-}
+type ConfigInput = stacks.ConfigInput
 
 type configElement struct {
 	ID        string     `graphql:"id" json:"id,omitempty"`
@@ -80,6 +76,46 @@ type listEnvQuery struct {
 	} `graphql:"stack(id: $stack)" json:"stack"`
 }
 
+// elements returns the configuration elements the stack runs with, including
+// the ones coming from attached contexts.
+func (q *listEnvQuery) elements() ([]listEnvElementOutput, error) {
+	var elements []listEnvElementOutput
+	for _, config := range q.Stack.RuntimeConfig {
+		var contextName *string
+		var isAutoAttached *bool
+		if config.Context != nil {
+			contextName = &config.Context.ContextName
+
+			f := false
+			isAutoAttached = &f
+		}
+
+		if element, err := config.Element.toConfigElementOutput(contextName, isAutoAttached); err == nil {
+			elements = append(elements, element)
+		} else {
+			return nil, err
+		}
+	}
+
+	for _, spcCtx := range q.Stack.AttachedContexts {
+		// If the context is not autoattached, we will get it with the whole config.
+		// If it's autoattached, we have to specifically list and attach it.
+		if !spcCtx.IsAutoattached {
+			continue
+		}
+
+		for _, config := range spcCtx.Config {
+			if element, err := config.toConfigElementOutput(&spcCtx.Name, &spcCtx.IsAutoattached); err == nil {
+				elements = append(elements, element)
+			} else {
+				return nil, err
+			}
+		}
+	}
+
+	return elements, nil
+}
+
 type listEnvCommand struct{}
 
 func setVar(ctx context.Context, cliCmd *cli.Command) error {
@@ -95,41 +131,29 @@ func setVar(ctx context.Context, cliCmd *cli.Command) error {
 		return err
 	}
 
-	var mutation struct {
-		ConfigElement struct {
-			ID        string `graphql:"id"`
-			WriteOnly bool   `graphql:"writeOnly"`
-			Value     string `graphql:"value"`
-		} `graphql:"stackConfigAdd(stack: $stack, config: $config)"`
-	}
-
-	variables := map[string]any{
-		"stack": graphql.ID(stackID),
-		"config": ConfigInput{
-			ID:        graphql.ID(envName),
-			Type:      envVarTypeConfig,
-			Value:     graphql.String(envValue),
-			WriteOnly: graphql.Boolean(cliCmd.Bool(flagEnvironmentWriteOnly.Name)),
-		},
-	}
-
-	if err := authenticated.Client().Mutate(ctx, &mutation, variables); err != nil {
+	element, err := stacks.New(authenticated.Client(ctx)).AddConfig(ctx, stackID, ConfigInput{
+		ID:        graphql.ID(envName),
+		Type:      envVarTypeConfig,
+		Value:     graphql.String(envValue),
+		WriteOnly: graphql.Boolean(cliCmd.Bool(flagEnvironmentWriteOnly.Name)),
+	})
+	if err != nil {
 		return err
 	}
 
-	fmt.Printf("Environment variable (%s) has been set!\n", mutation.ConfigElement.ID)
-	if mutation.ConfigElement.WriteOnly {
+	fmt.Printf("Environment variable (%s) has been set!\n", element.ID)
+	if element.WriteOnly || element.Value == nil {
 		fmt.Printf("Value: %s \n", strings.Repeat("*", len(envValue)))
 	} else {
-		fmt.Printf("Value: %s \n", mutation.ConfigElement.Value)
+		fmt.Printf("Value: %s \n", *element.Value)
 	}
-	fmt.Printf("Write only: %t \n", mutation.ConfigElement.WriteOnly)
+	fmt.Printf("Write only: %t \n", element.WriteOnly)
 
 	return nil
 }
 
 func (e *listEnvCommand) listEnv(ctx context.Context, cliCmd *cli.Command) error {
-	outputFormat, err := cmd.GetOutputFormat(cliCmd)
+	outputFormat, formatter, err := cmd.GetOutputFormat(cliCmd)
 	if err != nil {
 		return err
 	}
@@ -144,51 +168,20 @@ func (e *listEnvCommand) listEnv(ctx context.Context, cliCmd *cli.Command) error
 		"stack": graphql.ID(stackID),
 	}
 
-	if err := authenticated.Client().Query(ctx, &query, variables); err != nil {
+	if err := authenticated.Client(ctx).Query(ctx, &query, variables); err != nil {
 		return err
 	}
 
-	var elements []listEnvElementOutput
-	for _, config := range query.Stack.RuntimeConfig {
-		var contextName *string
-		var isAutoAttached *bool
-		if config.Context != nil {
-			contextName = &config.Context.ContextName
-
-			f := false
-			isAutoAttached = &f
-		}
-
-		if element, err := config.Element.toConfigElementOutput(contextName, isAutoAttached); err == nil {
-			elements = append(elements, element)
-		} else {
-			return err
-		}
-	}
-
-	for _, spcCtx := range query.Stack.AttachedContexts {
-		// If the context is not autoattached, we will get it with the whole config.
-		// If it's autoattached, we have to specifically list and attach it.
-		if !spcCtx.IsAutoattached {
-			continue
-		}
-
-		for _, config := range spcCtx.Config {
-			if element, err := config.toConfigElementOutput(&spcCtx.Name, &spcCtx.IsAutoattached); err == nil {
-				elements = append(elements, element)
-			} else {
-				return err
-			}
-		}
+	elements, err := query.elements()
+	if err != nil {
+		return err
 	}
 
 	switch outputFormat {
 	case cmd.OutputFormatTable:
 		return e.showOutputsTable(elements)
-	case cmd.OutputFormatJSON:
-		return e.showOutputsJSON(elements)
 	default:
-		return fmt.Errorf("unknown output format: %v", outputFormat)
+		return e.showOutputsJSON(formatter, elements)
 	}
 }
 
@@ -232,8 +225,8 @@ func (e *listEnvCommand) showOutputsTable(outputs []listEnvElementOutput) error
 	return cmd.OutputTable(tableData, true)
 }
 
-func (e *listEnvCommand) showOutputsJSON(outputs []listEnvElementOutput) error {
-	return cmd.OutputJSON(outputs)
+func (e *listEnvCommand) showOutputsJSON(formatter cmd.OutputFormatter, outputs []listEnvElementOutput) error {
+	return cmd.Output(formatter, outputs)
 }
 
 func (e *configElement) toConfigElementOutput(contextName *string, isAutoAttached *bool) (listEnvElementOutput, error) {
@@ -308,30 +301,18 @@ func mountFile(ctx context.Context, cliCmd *cli.Command) error {
 		return fmt.Errorf("expected max two arguments to `environment mount` but got %d", nArgs)
 	}
 
-	var mutation struct {
-		ConfigElement struct {
-			ID        string     `graphql:"id"`
-			WriteOnly bool       `graphql:"writeOnly"`
-			Type      ConfigType `graphql:"type"`
-		} `graphql:"stackConfigAdd(stack: $stack, config: $config)"`
-	}
-
-	variables := map[string]any{
-		"stack": graphql.ID(stackID),
-		"config": ConfigInput{
-			ID:        graphql.ID(envName),
-			Type:      fileTypeConfig,
-			Value:     graphql.String(base64.StdEncoding.EncodeToString(fileContent)),
-			WriteOnly: graphql.Boolean(cliCmd.Bool(flagEnvironmentWriteOnly.Name)),
-		},
-	}
-
-	if err := authenticated.Client().Mutate(ctx, &mutation, variables); err != nil {
+	element, err := stacks.New(authenticated.Client(ctx)).AddConfig(ctx, stackID, ConfigInput{
+		ID:        graphql.ID(envName),
+		Type:      fileTypeConfig,
+		Value:     graphql.String(base64.StdEncoding.EncodeToString(fileContent)),
+		WriteOnly: graphql.Boolean(cliCmd.Bool(flagEnvironmentWriteOnly.Name)),
+	})
+	if err != nil {
 		return err
 	}
 
-	fmt.Printf("File has been mounted to /mnt/workspace/%s\n", mutation.ConfigElement.ID)
-	fmt.Printf("Write only: %t \n", mutation.ConfigElement.WriteOnly)
+	fmt.Printf("File has been mounted to /mnt/workspace/%s\n", element.ID)
+	fmt.Printf("Write only: %t \n", element.WriteOnly)
 
 	return nil
 }
@@ -348,25 +329,14 @@ func deleteEnvironment(ctx context.Context, cliCmd *cli.Command) error {
 
 	envName := cliCmd.Args().Get(0)
 
-	var mutation struct {
-		ConfigElement *struct {
-			ID string
-		} `graphql:"stackConfigDelete(stack: $stack, id: $id)"`
-	}
-
-	variables := map[string]any{
-		"stack": graphql.ID(stackID),
-		"id":    graphql.ID(envName),
+	err = stacks.New(authenticated.Client(ctx)).DeleteConfig(ctx, stackID, envName)
+	if errors.Is(err, stacks.ErrConfigNotFound) {
+		return fmt.Errorf("environment (%s) doesn't exist", envName)
 	}
-
-	if err := authenticated.Client().Mutate(ctx, &mutation, variables); err != nil {
+	if err != nil {
 		return err
 	}
 
-	if mutation.ConfigElement == nil {
-		return fmt.Errorf("environment (%s) doesn't exist", envName)
-	}
-
 	fmt.Printf("Environment (%s) has been deleted!", envName)
 
 	return nil
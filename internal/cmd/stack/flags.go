@@ -1,6 +1,10 @@
 package stack
 
-import "github.com/urfave/cli/v3"
+import (
+	"time"
+
+	"github.com/urfave/cli/v3"
+)
 
 // flagStackID is flag used for passing the ID for a stack.
 //
@@ -189,3 +193,180 @@ var flagOnlyEnabled = &cli.BoolFlag{
 	Usage: "[Optional] Only show stacks with local preview enabled",
 	Value: false,
 }
+
+var flagDriftThreshold = &cli.UintFlag{
+	Name:  "threshold",
+	Usage: "[Optional] Exit with a non-zero code when more than `N` resources have drifted",
+}
+
+var flagResourceType = &cli.StringSliceFlag{
+	Name:  "type",
+	Usage: "[Optional] Only show resources of the given `TYPE`, can be specified multiple times",
+}
+
+var flagResourceAddressGlob = &cli.StringFlag{
+	Name:  "address-glob",
+	Usage: "[Optional] Only show resources with an address matching the `GLOB`, example: 'module.vpc.*'",
+}
+
+var flagResourceTainted = &cli.BoolFlag{
+	Name:  "tainted",
+	Usage: "[Optional] Only show tainted resources",
+}
+
+var flagResourceDrifted = &cli.BoolFlag{
+	Name:  "drifted",
+	Usage: "[Optional] Only show drifted resources",
+}
+
+var flagResourceProvider = &cli.StringFlag{
+	Name:  "provider",
+	Usage: "[Optional] Only show resources managed by a provider containing `NAME`, example: hashicorp/aws",
+}
+
+var flagResourceAttribute = &cli.StringSliceFlag{
+	Name: "attribute",
+	Usage: "[Optional] Only show resources with a matching attribute, can be specified multiple times. " +
+		"Accepts PATH=VALUE, PATH!=VALUE and !PATH (attribute absent or empty), example: --attribute tags.env=prod",
+}
+
+var flagReviewQueueSpace = &cli.StringSliceFlag{
+	Name:  "space",
+	Usage: "[Optional] Only show runs of stacks in the space with the given `ID`, can be specified multiple times",
+}
+
+var flagReviewQueueLabel = &cli.StringSliceFlag{
+	Name:  "label",
+	Usage: "[Optional] Only show runs of stacks with the given `LABEL`, can be specified multiple times",
+}
+
+var flagReviewQueueStackGlob = &cli.StringFlag{
+	Name:  "stack-glob",
+	Usage: "[Optional] Only show runs of stacks with an ID matching the `GLOB`, example: 'prod-*'",
+}
+
+var flagReviewQueueMaxDeletes = &cli.IntFlag{
+	Name:  "max-deletes",
+	Usage: "[Optional] Only show runs deleting at most `N` resources",
+}
+
+var flagReviewQueueApprovalOutcome = &cli.StringFlag{
+	Name:  "approval-outcome",
+	Usage: "[Optional] Only show runs with an approval policy evaluation with the given `OUTCOME`, example: undecided",
+}
+
+var flagReviewQueueApprove = &cli.BoolFlag{
+	Name:  "approve",
+	Usage: "[Optional] Approve all the runs matching the filters, after confirmation unless --yes is set",
+}
+
+var flagReviewQueueYes = &cli.BoolFlag{
+	Name:  "yes",
+	Usage: "[Optional] Approve without asking for confirmation, and allow --approve without any filter",
+}
+
+var flagRunState = &cli.StringSliceFlag{
+	Name:  "state",
+	Usage: "[Optional] Only show runs in the given `STATE`, can be specified multiple times, example: FAILED",
+}
+
+var flagRunType = &cli.StringSliceFlag{
+	Name:  "type",
+	Usage: "[Optional] Only show runs of the given `TYPE`, can be specified multiple times, example: TRACKED",
+}
+
+var flagRunBranch = &cli.StringFlag{
+	Name:  "branch",
+	Usage: "[Optional] Only show runs on the given `BRANCH`",
+}
+
+var flagRunAuthor = &cli.StringFlag{
+	Name:  "author",
+	Usage: "[Optional] Only show runs for commits by the given `AUTHOR`, matching the login or the name",
+}
+
+var flagRunTriggeredBy = &cli.StringFlag{
+	Name:  "triggered-by",
+	Usage: "[Optional] Only show runs triggered by the given `USER`",
+}
+
+var flagRunSince = &cli.StringFlag{
+	Name:  "since",
+	Usage: "[Optional] Only show runs created after `TIME`, either a duration like 24h or an RFC3339 timestamp",
+}
+
+var flagRunUntil = &cli.StringFlag{
+	Name:  "until",
+	Usage: "[Optional] Only show runs created before `TIME`, either a duration like 1h or an RFC3339 timestamp",
+}
+
+var flagRunCursor = &cli.StringFlag{
+	Name:  "cursor",
+	Usage: "[Optional] Continue listing from the `CURSOR` printed at the end of the previous page",
+}
+
+var flagCommentBody = &cli.StringFlag{
+	Name:  "body",
+	Usage: "[Optional] Markdown `BODY` of the comment",
+}
+
+var flagCommentBodyFile = &cli.StringFlag{
+	Name:      "body-file",
+	Usage:     "[Optional] Read the markdown body of the comment from `FILE`, use - for STDIN",
+	TakesFile: true,
+}
+
+var flagWaitUntil = &cli.StringSliceFlag{
+	Name:  "until",
+	Usage: "[Optional] Wait until the run reaches one of the given `STATE`s, for example FINISHED or UNCONFIRMED, can be specified multiple times. By default waits until the run ends",
+}
+
+var flagWaitTimeout = &cli.DurationFlag{
+	Name:  "timeout",
+	Usage: "[Optional] Maximum `DURATION` to wait for",
+	Value: 30 * time.Minute,
+}
+
+var flagWaitInterval = &cli.DurationFlag{
+	Name:  "interval",
+	Usage: "[Optional] `DURATION` between two checks of the run state",
+	Value: 10 * time.Second,
+}
+
+var flagRunExportOutput = &cli.StringFlag{
+	Name:      "output",
+	Aliases:   []string{"o"},
+	Usage:     "[Optional] Path of the `ARCHIVE` to write, defaults to <run ID>.tar.gz",
+	TakesFile: true,
+}
+
+var flagGuardrailsFile = &cli.StringFlag{
+	Name:      "guardrails",
+	Usage:     "[Optional] YAML `FILE` with the guardrails checked before confirming, with the keys max_deletes, max_replaces, forbidden_types, forbidden_addresses and required_state",
+	TakesFile: true,
+}
+
+var flagGuardrailMaxDeletes = &cli.IntFlag{
+	Name:  "max-deletes",
+	Usage: "[Optional] Refuse to confirm runs deleting more than `N` resources",
+}
+
+var flagGuardrailMaxReplaces = &cli.IntFlag{
+	Name:  "max-replaces",
+	Usage: "[Optional] Refuse to confirm runs replacing more than `N` resources",
+}
+
+var flagGuardrailForbidType = &cli.StringSliceFlag{
+	Name:  "forbid-type",
+	Usage: "[Optional] Refuse to confirm runs deleting or replacing resources with a type matching the `GLOB`, can be specified multiple times, example: 'aws_db_*'",
+}
+
+var flagGuardrailForbidAddress = &cli.StringSliceFlag{
+	Name:  "forbid-address",
+	Usage: "[Optional] Refuse to confirm runs deleting or replacing resources with an address matching the `GLOB`, can be specified multiple times, example: 'module.prod.*'",
+}
+
+var flagGuardrailRequireState = &cli.StringFlag{
+	Name:  "require-state",
+	Usage: "[Optional] Refuse to confirm the run unless it is in the given `STATE`, example: UNCONFIRMED",
+}
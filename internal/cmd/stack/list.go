@@ -3,120 +3,69 @@ package stack
 import (
 	"context"
 	"fmt"
-	"math"
-	"slices"
+	"iter"
 	"strings"
 
 	"github.com/shurcooL/graphql"
 	"github.com/urfave/cli/v3"
 
+	"github.com/spacelift-io/spacectl/client"
+	"github.com/spacelift-io/spacectl/client/stacks"
 	"github.com/spacelift-io/spacectl/client/structs"
 	"github.com/spacelift-io/spacectl/internal/cmd"
 )
 
 func listStacks() cli.ActionFunc {
 	return func(ctx context.Context, cliCmd *cli.Command) error {
-		outputFormat, err := cmd.GetOutputFormat(cliCmd)
+		outputFormat, formatter, err := cmd.GetOutputFormat(cliCmd)
 		if err != nil {
 			return err
 		}
 
-		var limit *uint
-		if cliCmd.IsSet(cmd.FlagLimit.Name) {
-			if cliCmd.Uint(cmd.FlagLimit.Name) == 0 {
-				return fmt.Errorf("limit must be greater than 0")
-			}
-
-			if cliCmd.Uint(cmd.FlagLimit.Name) >= math.MaxInt32 {
-				return fmt.Errorf("limit must be less than %d", math.MaxInt32)
-			}
+		if cliCmd.IsSet(cmd.FlagLimit.Name) && cliCmd.Uint(cmd.FlagLimit.Name) == 0 {
+			return fmt.Errorf("limit must be greater than 0")
+		}
 
-			limit = new(cliCmd.Uint(cmd.FlagLimit.Name))
+		opts, err := cmd.GetPaginateOptions(cliCmd)
+		if err != nil {
+			return err
 		}
 
-		var search *string
+		var input structs.SearchInput
 		if cliCmd.IsSet(cmd.FlagSearch.Name) {
 			if cliCmd.String(cmd.FlagSearch.Name) == "" {
 				return fmt.Errorf("search must be non-empty")
 			}
 
-			search = new(cliCmd.String(cmd.FlagSearch.Name))
+			input.FullTextSearch = new(graphql.String(cliCmd.String(cmd.FlagSearch.Name)))
 		}
 
 		switch outputFormat {
 		case cmd.OutputFormatTable:
-			return listStacksTable(ctx, cliCmd, search, limit)
-		case cmd.OutputFormatJSON:
-			return listStacksJSON(ctx, search, limit)
-		}
-
-		return fmt.Errorf("unknown output format: %v", outputFormat)
-	}
-}
-
-func listStacksJSON(
-	ctx context.Context,
-	search *string,
-	limit *uint,
-) error {
-	var first *graphql.Int
-	if limit != nil {
-		first = new(graphql.Int(*limit)) //nolint: gosec
-	}
-
-	var fullTextSearch *graphql.String
-	if search != nil {
-		fullTextSearch = new(graphql.String(*search))
-	}
+			input.OrderBy = &structs.QueryOrder{
+				Field:     "starred",
+				Direction: "DESC",
+			}
 
-	stacks, err := searchAllStacks(ctx, structs.SearchInput{
-		First:          first,
-		FullTextSearch: fullTextSearch,
-	})
-	if err != nil {
-		return err
+			return listStacksTable(cliCmd, paginateStacks[stack](ctx, input, opts))
+		default:
+			return cmd.OutputStream(outputFormat, formatter, paginateStacks[stack](ctx, input, opts))
+		}
 	}
-
-	return cmd.OutputJSON(stacks)
 }
 
-func listStacksTable(
-	ctx context.Context,
-	cliCmd *cli.Command,
-	search *string,
-	limit *uint,
-) error {
-	var first *graphql.Int
-	if limit != nil {
-		first = new(graphql.Int(*limit)) //nolint: gosec
-	}
-
-	var fullTextSearch *graphql.String
-	if search != nil {
-		fullTextSearch = new(graphql.String(*search))
-	}
-
-	input := structs.SearchInput{
-		First:          first,
-		FullTextSearch: fullTextSearch,
-		OrderBy: &structs.QueryOrder{
-			Field:     "starred",
-			Direction: "DESC",
-		},
-	}
-
-	stacks, err := searchAllStacks(ctx, input)
-	if err != nil {
-		return err
-	}
-
+func listStacksTable(cliCmd *cli.Command, found iter.Seq2[stack, error]) error {
 	columns := []string{"Name", "ID", "Commit", "Author", "State", "Vendor", "Worker Pool", "Locked By"}
 	if cliCmd.Bool(cmd.FlagShowLabels.Name) {
 		columns = append(columns, "Labels")
 	}
 
 	tableData := [][]string{columns}
-	for _, s := range stacks {
+	for s, err := range found {
+		if err != nil {
+			return err
+		}
+
 		row := []string{
 			s.Name,
 			s.ID,
@@ -137,48 +86,13 @@ func listStacksTable(
 	return cmd.OutputTable(tableData, true)
 }
 
-// searchStacks returns a list of stacks based on the provided search input.
-// input.First limits the total number of returned stacks, if not provided all stacks are returned.
-func searchAllStacks(ctx context.Context, input structs.SearchInput) ([]stack, error) {
-	const maxPageSize = 50
-
-	var limit int
-	if input.First != nil {
-		limit = int(*input.First)
-	}
-	fetchAll := limit == 0
-
-	out := []stack{}
-	pageInput := structs.SearchInput{
-		First:          graphql.NewInt(maxPageSize),
-		FullTextSearch: input.FullTextSearch,
-	}
-	for {
-		if !fetchAll {
-			// Fetch exactly the number of items requested
-			pageInput.First = new(
-				//nolint: gosec
-				graphql.Int(
-					slices.Min([]int{maxPageSize, limit - len(out)}),
-				),
-			)
-		}
-
-		result, err := searchStacks[stack](ctx, pageInput)
-		if err != nil {
-			return nil, err
-		}
-
-		out = append(out, result.Stacks...)
-
-		if result.PageInfo.HasNextPage && (fetchAll || limit > len(out)) {
-			pageInput.After = new(graphql.String(result.PageInfo.EndCursor))
-		} else {
-			break
-		}
-	}
-
-	return out, nil
+// paginateStacks iterates over the stacks matching the input, fetching pages
+// as they are consumed.
+func paginateStacks[T hasIDAndName](ctx context.Context, input structs.SearchInput, opts client.PaginateOptions) iter.Seq2[T, error] {
+	return client.Paginate(ctx, input, opts, func(ctx context.Context, input structs.SearchInput) (client.Page[T], error) {
+		result, err := searchStacks[T](ctx, input)
+		return client.Page[T]{Items: result.Stacks, PageInfo: result.PageInfo}, err
+	})
 }
 
 type hasIDAndName interface {
@@ -199,72 +113,4 @@ func (s stackID) GetName() string {
 	return s.Name
 }
 
-type stack struct {
-	ID             string `graphql:"id" json:"id,omitempty"`
-	Administrative bool   `graphql:"administrative" json:"administrative,omitempty"`
-	Autodeploy     bool   `graphql:"autodeploy" json:"autodeploy,omitempty"`
-	Autoretry      bool   `graphql:"autoretry" json:"autoretry,omitempty"`
-	Blocker        struct {
-		ID string `graphql:"id" json:"id,omitempty"`
-	} `graphql:"blocker"`
-	AfterApply          []string `graphql:"afterApply" json:"afterApply,omitempty"`
-	BeforeApply         []string `graphql:"beforeApply" json:"beforeApply,omitempty"`
-	AfterInit           []string `graphql:"afterInit" json:"afterInit,omitempty"`
-	BeforeInit          []string `graphql:"beforeInit" json:"beforeInit,omitempty"`
-	AfterPlan           []string `graphql:"afterPlan" json:"afterPlan,omitempty"`
-	BeforePlan          []string `graphql:"beforePlan" json:"beforePlan,omitempty"`
-	AfterPerform        []string `graphql:"afterPerform" json:"afterPerform,omitempty"`
-	BeforePerform       []string `graphql:"beforePerform" json:"beforePerform,omitempty"`
-	AfterDestroy        []string `graphql:"afterDestroy" json:"afterDestroy,omitempty"`
-	BeforeDestroy       []string `graphql:"beforeDestroy" json:"beforeDestroy,omitempty"`
-	Branch              string   `graphql:"branch" json:"branch,omitempty"`
-	CanWrite            bool     `graphql:"canWrite" json:"canWrite,omitempty"`
-	CreatedAt           int64    `graphql:"createdAt" json:"createdAt,omitempty"`
-	Deleted             bool     `graphql:"deleted" json:"deleted,omitempty"`
-	Deleting            bool     `graphql:"deleting" json:"deleting,omitempty"`
-	Description         string   `graphql:"description" json:"description,omitempty"`
-	Labels              []string `graphql:"labels" json:"labels,omitempty"`
-	LocalPreviewEnabled bool     `graphql:"localPreviewEnabled" json:"localPreviewEnabled,omitempty"`
-	LockedBy            string   `graphql:"lockedBy" json:"lockedBy,omitempty"`
-	ManagesStateFile    bool     `graphql:"managesStateFile" json:"managesStateFile,omitempty"`
-	Name                string   `graphql:"name" json:"name,omitempty"`
-	Namespace           string   `graphql:"namespace" json:"namespace,omitempty"`
-	ProjectRoot         string   `graphql:"projectRoot" json:"projectRoot,omitempty"`
-	Provider            string   `graphql:"provider" json:"provider,omitempty"`
-	Repository          string   `graphql:"repository" json:"repository,omitempty"`
-	RunnerImage         string   `graphql:"runnerImage" json:"runnerImage,omitempty"`
-	Starred             bool     `graphql:"starred" json:"starred,omitempty"`
-	State               string   `graphql:"state" json:"state,omitempty"`
-	StateSetAt          int64    `graphql:"stateSetAt" json:"stateSetAt,omitempty"`
-	TerraformVersion    string   `graphql:"terraformVersion" json:"terraformVersion,omitempty"`
-	SpaceDetails        struct {
-		ID          string  `graphql:"id" json:"id,omitempty"`
-		Name        string  `graphql:"name" json:"name,omitempty"`
-		Description string  `graphql:"description" json:"description,omitempty"`
-		ParentSpace *string `graphql:"parentSpace" json:"parentSpace,omitempty"`
-	} `graphql:"spaceDetails" json:"spaceDetails"`
-	TrackedCommit struct {
-		AuthorLogin string `graphql:"authorLogin" json:"authorLogin,omitempty"`
-		AuthorName  string `graphql:"authorName" json:"authorName,omitempty"`
-		Hash        string `graphql:"hash" json:"hash,omitempty"`
-		Message     string `graphql:"message" json:"message,omitempty"`
-		Timestamp   int64  `graphql:"timestamp" json:"timestamp,omitempty"`
-		URL         string `graphql:"url" json:"url,omitempty"`
-	} `graphql:"trackedCommit" json:"trackedCommit"`
-	TrackedCommitSetBy string `graphql:"trackedCommitSetBy" json:"trackedCommitSetBy,omitempty"`
-	VendorConfig       struct {
-		Vendor string `graphql:"__typename" json:"vendor,omitempty"`
-	} `graphql:"vendorConfig" json:"vendorConfig"`
-	WorkerPool struct {
-		ID   string `graphql:"id" json:"id,omitempty"`
-		Name string `graphql:"name" json:"name,omitempty"`
-	} `graphql:"workerPool" json:"workerPool"`
-}
-
-func (s stack) GetID() string {
-	return s.ID
-}
-
-func (s stack) GetName() string {
-	return s.Name
-}
+type stack = stacks.Stack
@@ -25,7 +25,7 @@ func getStackForLocalPreview(ctx context.Context, cliCmd *cli.Command) (*stack,
 			return nil, err
 		}
 		if !s.LocalPreviewEnabled {
-			linkToStack := authenticated.Client().URL("/stack/%s", s.ID)
+			linkToStack := authenticated.Client(ctx).URL("/stack/%s", s.ID)
 			return nil, fmt.Errorf("local preview has not been enabled for this stack, please enable local preview in the stack settings: %s", linkToStack)
 		}
 
@@ -91,7 +91,7 @@ func localPreview(useHeaders bool) cli.ActionFunc {
 			return fmt.Errorf("failed to create local preview run: %w", err)
 		}
 
-		linkToRun := authenticated.Client().URL(
+		linkToRun := authenticated.Client(ctx).URL(
 			"/stack/%s/run/%s",
 			s.ID,
 			runID,
@@ -241,7 +241,7 @@ func createLocalPreviewRun(
 			UploadLocalWorkspace headersResponse `graphql:"uploadLocalWorkspace(stack: $stack)"`
 		}
 
-		if err := authenticated.Client().Mutate(ctx, &headersMutation, uploadVariables); err != nil {
+		if err := authenticated.Client(ctx).Mutate(ctx, &headersMutation, uploadVariables); err != nil {
 			return "", fmt.Errorf("failed to upload local workspace: %w", err)
 		}
 
@@ -254,7 +254,7 @@ func createLocalPreviewRun(
 			UploadLocalWorkspace basicResponse `graphql:"uploadLocalWorkspace(stack: $stack)"`
 		}
 
-		if err := authenticated.Client().Mutate(ctx, &basicMutation, uploadVariables); err != nil {
+		if err := authenticated.Client(ctx).Mutate(ctx, &basicMutation, uploadVariables); err != nil {
 			return "", fmt.Errorf("failed to upload local workspace: %w", err)
 		}
 
@@ -314,7 +314,7 @@ func createLocalPreviewRun(
 	}
 
 	fmt.Fprintln(writer, "Creating local preview run...")
-	if err = authenticated.Client().Mutate(ctx, &triggerMutation, triggerVariables, requestOpts...); err != nil {
+	if err = authenticated.Client(ctx).Mutate(ctx, &triggerMutation, triggerVariables, requestOpts...); err != nil {
 		return "", err
 	}
 
@@ -4,23 +4,12 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/shurcooL/graphql"
 	"github.com/urfave/cli/v3"
 
+	"github.com/spacelift-io/spacectl/client/stacks"
 	"github.com/spacelift-io/spacectl/internal/cmd/authenticated"
 )
 
-type stackLockMutation struct {
-	Stack struct {
-		ID string `graphql:"id"`
-	} `graphql:"stackLock(id: $stack, note: $note)"`
-}
-type stackUnlockMutation struct {
-	Stack struct {
-		ID string `graphql:"id"`
-	} `graphql:"stackUnlock(id: $stack)"`
-}
-
 var flagStackLockNote = &cli.StringFlag{
 	Name:     "note",
 	Usage:    "Description of why the lock was acquired.",
@@ -38,13 +27,7 @@ func lock(ctx context.Context, cliCmd *cli.Command) error {
 		return fmt.Errorf("expected zero arguments but got %d", nArgs)
 	}
 
-	var mutation stackLockMutation
-	variables := map[string]any{
-		"stack": graphql.ID(stackID),
-		"note":  graphql.String(note),
-	}
-
-	return authenticated.Client().Mutate(ctx, &mutation, variables)
+	return stacks.New(authenticated.Client(ctx)).Lock(ctx, stackID, note)
 }
 
 func unlock(ctx context.Context, cliCmd *cli.Command) error {
@@ -57,10 +40,5 @@ func unlock(ctx context.Context, cliCmd *cli.Command) error {
 		return fmt.Errorf("expected zero arguments but got %d", nArgs)
 	}
 
-	var mutation stackUnlockMutation
-	variables := map[string]any{
-		"stack": graphql.ID(stackID),
-	}
-
-	return authenticated.Client().Mutate(ctx, &mutation, variables)
+	return stacks.New(authenticated.Client(ctx)).Unlock(ctx, stackID)
 }
@@ -29,12 +29,19 @@ func RegisterMCPTools(s *server.MCPServer, options McpOptions) {
 	registerListStackProposedRunsTool(s)
 	registerGetStackRunTool(s)
 	registerGetStackRunLogsTool(s)
+	registerDiagnoseStackRunTool(s)
 	registerGetStackRunChangesTool(s)
 	registerTriggerStackRunTool(s)
 	registerDiscardStackRunTool(s)
 	registerConfirmStackRunTool(s)
 	registerListResourcesTool(s)
 	registerLocalPreviewTool(s, options)
+	registerGetStackEnvironmentTool(s)
+	registerSetStackEnvironmentVariableTool(s)
+	registerAttachStackContextTool(s)
+	registerDetachStackContextTool(s)
+	registerLockStackTool(s)
+	registerUnlockStackTool(s)
 }
 
 func registerListStacksTool(s *server.MCPServer) {
@@ -74,6 +81,11 @@ func registerListStacksTool(s *server.MCPServer) {
 			return nil, errors.Wrap(err, "failed to search stacks")
 		}
 
+		result.Stacks, err = authenticated.FilterBySpace(ctx, result.Stacks, func(s stack) string { return s.SpaceDetails.ID })
+		if err != nil {
+			return nil, err
+		}
+
 		stacksJSON, err := json.Marshal(result.Stacks)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to marshal stacks to JSON")
@@ -126,7 +138,7 @@ func registerListStackRunsTool(s *server.MCPServer) {
 			} `graphql:"stack(id: $stackId)"`
 		}
 
-		if err := authenticated.Client().Query(ctx, &query, map[string]any{"stackId": stackID, "before": before}); err != nil {
+		if err := authenticated.Client(ctx).Query(ctx, &query, map[string]any{"stackId": stackID, "before": before}); err != nil {
 			return nil, errors.Wrap(err, "failed to query run list")
 		}
 		if query.Stack == nil {
@@ -186,7 +198,7 @@ func registerListStackProposedRunsTool(s *server.MCPServer) {
 			} `graphql:"stack(id: $stackId)"`
 		}
 
-		if err := authenticated.Client().Query(ctx, &query, map[string]any{"stackId": stackID, "before": before}); err != nil {
+		if err := authenticated.Client(ctx).Query(ctx, &query, map[string]any{"stackId": stackID, "before": before}); err != nil {
 			return nil, errors.Wrap(err, "failed to query run list")
 		}
 		if query.Stack == nil {
@@ -246,7 +258,7 @@ func registerGetStackRunTool(s *server.MCPServer) {
 			} `graphql:"stack(id: $stackId)"`
 		}
 
-		if err := authenticated.Client().Query(ctx, &query, map[string]any{"stackId": stackID, "runId": runID}); err != nil {
+		if err := authenticated.Client(ctx).Query(ctx, &query, map[string]any{"stackId": stackID, "runId": runID}); err != nil {
 			return nil, errors.Wrap(err, "failed to query run list")
 		}
 		if query.Stack == nil {
@@ -303,37 +315,51 @@ func registerGetStackRunLogsTool(s *server.MCPServer) {
 			limit = &limitVal
 		}
 
-		logLines := make(chan string)
-		var allLogs []string
-		var terminal *structs.RunStateTransition
+		output, err := collectRunLogs(ctx, stackID, runID)
+		if err != nil {
+			return nil, err
+		}
 
-		go func() {
-			for line := range logLines {
-				allLogs = append(allLogs, line)
-			}
-		}()
+		output = trimStringByLines(output, skip, limit)
 
-		terminal, err = logs.NewExplorer(stackID, runID).RunFilteredStates(ctx, logLines)
-		close(logLines)
+		return mcp.NewToolResultText(output), nil
+	})
+}
 
-		if err != nil {
-			return nil, errors.Wrap(err, "failed to collect run logs")
+// collectRunLogs returns the logs of all the phases of a run, with a header
+// and the final state if the run is over.
+func collectRunLogs(ctx context.Context, stackID, runID string) (string, error) {
+	logLines := make(chan string)
+	done := make(chan struct{})
+	var allLogs []string
+
+	go func() {
+		defer close(done)
+		for line := range logLines {
+			allLogs = append(allLogs, line)
 		}
+	}()
 
-		output := fmt.Sprintf("Logs for run %s in stack %s:\n\n", runID, stackID)
+	terminal, err := logs.NewExplorer(stackID, runID).RunFilteredStates(ctx, logLines)
+	close(logLines)
+	<-done
 
-		for _, line := range allLogs {
-			output += line
-		}
+	if err != nil {
+		return "", errors.Wrap(err, "failed to collect run logs")
+	}
 
-		if terminal != nil {
-			output += fmt.Sprintf("\n\nRun completed with state: %s", terminal.State)
-		}
+	var output strings.Builder
+	fmt.Fprintf(&output, "Logs for run %s in stack %s:\n\n", runID, stackID)
 
-		output = trimStringByLines(output, skip, limit)
+	for _, line := range allLogs {
+		output.WriteString(line)
+	}
 
-		return mcp.NewToolResultText(output), nil
-	})
+	if terminal != nil {
+		fmt.Fprintf(&output, "\n\nRun completed with state: %s", terminal.State)
+	}
+
+	return output.String(), nil
 }
 
 func trimStringByLines(input string, skip *int, limit *int) string {
@@ -412,7 +438,8 @@ func registerTriggerStackRunTool(s *server.MCPServer) {
 	stackRunTriggerTool := mcp.NewTool("trigger_stack_run",
 		mcp.WithDescription(`Initiate a new run for a Spacelift stack. You can specify the run type (PROPOSED or TRACKED) and optionally provide a specific commit SHA to use for the run.`),
 		mcp.WithToolAnnotation(mcp.ToolAnnotation{
-			Title: "Trigger Stack Run",
+			Title:           "Trigger Stack Run",
+			DestructiveHint: new(true),
 		}),
 		mcp.WithString("stack_id", mcp.Description("The ID of the stack"), mcp.Required()),
 		mcp.WithString("commit_sha", mcp.Description("The commit SHA to use for the run")),
@@ -446,12 +473,12 @@ func registerTriggerStackRunTool(s *server.MCPServer) {
 			variables["sha"] = new(graphql.String(commitSha))
 		}
 
-		if err := authenticated.Client().Mutate(ctx, &mutation, variables); err != nil {
+		if err := authenticated.Client(ctx).Mutate(ctx, &mutation, variables); err != nil {
 			return nil, errors.Wrap(err, "failed to trigger run")
 		}
 
 		output := fmt.Sprintf("Successfully created a %s\n", runType)
-		output += fmt.Sprintf("The live run can be visited at %s", authenticated.Client().URL(
+		output += fmt.Sprintf("The live run can be visited at %s", authenticated.Client(ctx).URL(
 			"/stack/%s/run/%s",
 			stackID,
 			mutation.RunTrigger.ID,
@@ -465,7 +492,8 @@ func registerDiscardStackRunTool(s *server.MCPServer) {
 	stackRunDiscardTool := mcp.NewTool("discard_stack_run",
 		mcp.WithDescription(`Discard a pending or in-progress run for a Spacelift stack.`),
 		mcp.WithToolAnnotation(mcp.ToolAnnotation{
-			Title: "Discard Stack Run",
+			Title:           "Discard Stack Run",
+			DestructiveHint: new(true),
 		}),
 		mcp.WithString("stack_id", mcp.Description("The ID of the stack"), mcp.Required()),
 		mcp.WithString("run_id", mcp.Description("The ID of the run"), mcp.Required()),
@@ -494,12 +522,12 @@ func registerDiscardStackRunTool(s *server.MCPServer) {
 			"run":   graphql.ID(runID),
 		}
 
-		if err := authenticated.Client().Mutate(ctx, &mutation, variables); err != nil {
+		if err := authenticated.Client(ctx).Mutate(ctx, &mutation, variables); err != nil {
 			return nil, errors.Wrap(err, "failed to discard run")
 		}
 
 		output := "You have successfully discarded a deployment\n"
-		output += fmt.Sprintf("The run can be visited at %s", authenticated.Client().URL(
+		output += fmt.Sprintf("The run can be visited at %s", authenticated.Client(ctx).URL(
 			"/stack/%s/run/%s",
 			stackID,
 			mutation.RunDiscard.ID,
@@ -513,7 +541,8 @@ func registerConfirmStackRunTool(s *server.MCPServer) {
 	stackRunConfirmTool := mcp.NewTool("confirm_stack_run",
 		mcp.WithDescription(`Approve a run that is waiting for confirmation in a Spacelift stack. This allows the run to proceed with applying changes to your infrastructure.`),
 		mcp.WithToolAnnotation(mcp.ToolAnnotation{
-			Title: "Confirm Stack Run",
+			Title:           "Confirm Stack Run",
+			DestructiveHint: new(true),
 		}),
 		mcp.WithString("stack_id", mcp.Description("The ID of the stack"), mcp.Required()),
 		mcp.WithString("run_id", mcp.Description("The ID of the run"), mcp.Required()),
@@ -542,12 +571,12 @@ func registerConfirmStackRunTool(s *server.MCPServer) {
 			"run":   graphql.ID(runID),
 		}
 
-		if err := authenticated.Client().Mutate(ctx, &mutation, variables); err != nil {
+		if err := authenticated.Client(ctx).Mutate(ctx, &mutation, variables); err != nil {
 			return nil, errors.Wrap(err, "failed to confirm run")
 		}
 
 		output := "You have successfully confirmed a deployment\n"
-		output += fmt.Sprintf("The live run can be visited at %s", authenticated.Client().URL(
+		output += fmt.Sprintf("The live run can be visited at %s", authenticated.Client(ctx).URL(
 			"/stack/%s/run/%s",
 			stackID,
 			mutation.RunConfirm.ID,
@@ -581,7 +610,8 @@ func registerLocalPreviewTool(s *server.MCPServer, options McpOptions) {
 	var localPreviewTool = mcp.NewTool("local_preview",
 		mcp.WithDescription(`Start a preview (proposed run) based on the current project.`),
 		mcp.WithToolAnnotation(mcp.ToolAnnotation{
-			Title: "Local Preview",
+			Title:           "Local Preview",
+			DestructiveHint: new(false),
 		}),
 		mcp.WithString("stack_id", mcp.Description("The ID of the stack"), mcp.Required()),
 		mcp.WithObject("environment_variables", mcp.Description("Environment variables to set for the run")),
@@ -615,7 +645,7 @@ func registerLocalPreviewTool(s *server.MCPServer, options McpOptions) {
 		}
 
 		if !stack.LocalPreviewEnabled {
-			linkToStack := authenticated.Client().URL("/stack/%s", stack.ID)
+			linkToStack := authenticated.Client(ctx).URL("/stack/%s", stack.ID)
 			return mcp.NewToolResultText(fmt.Sprintf("Local preview has not been enabled for this stack, please enable local preview in the stack settings: %s", linkToStack)), nil
 		}
 
@@ -680,7 +710,7 @@ func registerLocalPreviewTool(s *server.MCPServer, options McpOptions) {
 		output := outputBuilder.String()
 
 		// Add run URL to the output
-		linkToRun := authenticated.Client().URL(
+		linkToRun := authenticated.Client(ctx).URL(
 			"/stack/%s/run/%s",
 			stackID,
 			runID,
@@ -738,7 +768,7 @@ func listResourcesForOneStack(ctx context.Context, id string) (*mcp.CallToolResu
 	}
 
 	variables := map[string]any{"id": graphql.ID(id)}
-	if err := authenticated.Client().Query(ctx, &query, variables); err != nil {
+	if err := authenticated.Client(ctx).Query(ctx, &query, variables); err != nil {
 		return nil, fmt.Errorf("failed to query stack resources: %w", err)
 	}
 
@@ -760,10 +790,16 @@ func listResourcesForAllStacks(ctx context.Context) (*mcp.CallToolResult, error)
 		Stacks []stackWithResources `graphql:"stacks" json:"stacks,omitempty"`
 	}
 
-	if err := authenticated.Client().Query(ctx, &query, map[string]any{}); err != nil {
+	if err := authenticated.Client(ctx).Query(ctx, &query, map[string]any{}); err != nil {
 		return nil, fmt.Errorf("failed to query all stacks resources: %w", err)
 	}
 
+	stacks, err := authenticated.FilterBySpace(ctx, query.Stacks, func(s stackWithResources) string { return s.Space })
+	if err != nil {
+		return nil, err
+	}
+	query.Stacks = stacks
+
 	resourcesJSON, err := json.Marshal(query.Stacks)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to marshal all stacks resources to JSON")
@@ -0,0 +1,274 @@
+package stack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/pkg/errors"
+
+	"github.com/spacelift-io/spacectl/client/structs"
+	"github.com/spacelift-io/spacectl/internal/cmd/authenticated"
+	"github.com/spacelift-io/spacectl/internal/logs"
+)
+
+const (
+	maxDiagnosedErrors     = 20
+	maxDiagnosedErrorLines = 15
+	diagnosedTailLines     = 20
+)
+
+var (
+	ansiEscape        = regexp.MustCompile(`\x1b\[[0-9;]*[A-Za-z]`)
+	terraformError    = regexp.MustCompile(`^Error: (.*)$`)
+	diagnosticSubject = regexp.MustCompile(`^\s*with ([^,]+),`)
+	diagnosticSource  = regexp.MustCompile(`^\s*on (\S+) line (\d+)`)
+	providerError     = regexp.MustCompile(`(?i)\bprovider\b|status ?code:? *\d{3}|\bapi error\b`)
+	policyDenial      = regexp.MustCompile(`(?i)\bpolic(y|ies)\b.*\b(deny|denied|denies|rejected)\b|\b(deny|denied|denies|rejected)\b.*\bpolic(y|ies)\b`)
+	genericError      = regexp.MustCompile(`(?i)^(error|fatal|panic)\b:?\s|\[(error|fatal)\]`)
+)
+
+// runDiagnosis is a compact summary of why a run failed.
+type runDiagnosis struct {
+	StackID      string     `json:"stackId"`
+	RunID        string     `json:"runId"`
+	State        string     `json:"state"`
+	Note         *string    `json:"note,omitempty"`
+	Phase        string     `json:"phase,omitempty"`
+	PhaseVersion int        `json:"phaseVersion,omitempty"`
+	LogLines     int        `json:"logLines"`
+	Errors       []logError `json:"errors"`
+	Truncated    bool       `json:"truncated,omitempty"`
+	Tail         []string   `json:"tail,omitempty"`
+	Message      string     `json:"message,omitempty"`
+}
+
+// logError is an error block found in the logs of a phase. Lines are
+// 1-based and refer to the logs of that phase only.
+type logError struct {
+	Kind     string   `json:"kind"`
+	Line     int      `json:"line"`
+	EndLine  int      `json:"endLine"`
+	Summary  string   `json:"summary"`
+	Resource string   `json:"resource,omitempty"`
+	Source   string   `json:"source,omitempty"`
+	Detail   []string `json:"detail,omitempty"`
+}
+
+// failingPhase returns the last phase with logs before the run ended, which
+// is where it failed. History is ordered newest first.
+func failingPhase(history []structs.RunStateTransition) *structs.RunStateTransition {
+	var terminal *structs.RunStateTransition
+	for i := range history {
+		transition := &history[i]
+		if transition.Terminal {
+			if terminal == nil && transition.HasLogs {
+				terminal = transition
+			}
+			continue
+		}
+
+		if transition.HasLogs {
+			return transition
+		}
+	}
+
+	return terminal
+}
+
+// cleanLogLine strips colours and the box drawing Terraform wraps its
+// diagnostics in.
+func cleanLogLine(line string) string {
+	line = ansiEscape.ReplaceAllString(line, "")
+	line = strings.TrimRight(line, " \r")
+
+	for _, prefix := range []string{"│ ", "│", "╷", "╵"} {
+		if rest, ok := strings.CutPrefix(line, prefix); ok {
+			return rest
+		}
+	}
+
+	return line
+}
+
+// extractLogErrors finds Terraform diagnostics, provider errors, policy
+// denials and other error lines in the logs of a phase.
+func extractLogErrors(phaseLogs string) (found []logError, truncated bool) {
+	lines := strings.Split(strings.TrimRight(phaseLogs, "\n"), "\n")
+	found = []logError{}
+
+	for i := 0; i < len(lines); i++ {
+		line := cleanLogLine(lines[i])
+
+		var block logError
+		switch {
+		case terraformError.MatchString(strings.TrimSpace(line)):
+			block, i = terraformDiagnostic(lines, i)
+		case policyDenial.MatchString(line):
+			block = logError{Kind: "policy", Line: i + 1, EndLine: i + 1, Summary: strings.TrimSpace(line)}
+		case genericError.MatchString(strings.TrimSpace(line)):
+			block = logError{Kind: "error", Line: i + 1, EndLine: i + 1, Summary: strings.TrimSpace(line)}
+		default:
+			continue
+		}
+
+		if len(found) == maxDiagnosedErrors {
+			return found, true
+		}
+		found = append(found, block)
+	}
+
+	return found, false
+}
+
+// terraformDiagnostic reads the diagnostic starting at the given line until
+// the end of its box or the first blank line, and returns the index of its
+// last line.
+func terraformDiagnostic(lines []string, start int) (logError, int) {
+	first := strings.TrimSpace(cleanLogLine(lines[start]))
+	block := logError{
+		Kind:    "terraform",
+		Line:    start + 1,
+		Summary: terraformError.FindStringSubmatch(first)[1],
+	}
+
+	end := start
+	for i := start + 1; i < len(lines); i++ {
+		raw := strings.TrimSpace(ansiEscape.ReplaceAllString(lines[i], ""))
+		if strings.HasPrefix(raw, "╵") {
+			break
+		}
+
+		line := cleanLogLine(lines[i])
+		boxed := strings.HasPrefix(raw, "│")
+		if !boxed && strings.TrimSpace(line) == "" {
+			break
+		}
+		if terraformError.MatchString(strings.TrimSpace(line)) {
+			break
+		}
+		end = i
+
+		if match := diagnosticSubject.FindStringSubmatch(line); match != nil {
+			block.Resource = match[1]
+		}
+		if match := diagnosticSource.FindStringSubmatch(line); match != nil {
+			block.Source = match[1] + ":" + match[2]
+		}
+		if len(block.Detail) < maxDiagnosedErrorLines && strings.TrimSpace(line) != "" {
+			block.Detail = append(block.Detail, strings.TrimSpace(line))
+		}
+	}
+	block.EndLine = end + 1
+
+	if block.Resource != "" || providerError.MatchString(block.Summary) {
+		block.Kind = "provider"
+	}
+	for _, line := range block.Detail {
+		if policyDenial.MatchString(line) {
+			block.Kind = "policy"
+		}
+	}
+
+	return block, end
+}
+
+// diagnose summarises the failing phase of a run from its logs.
+func (d *runDiagnosis) diagnose(phaseLogs string) {
+	cleaned := strings.Split(strings.TrimRight(phaseLogs, "\n"), "\n")
+	d.LogLines = len(cleaned)
+
+	d.Errors, d.Truncated = extractLogErrors(phaseLogs)
+	if len(d.Errors) > 0 {
+		return
+	}
+
+	// Without recognisable errors, the end of the logs is usually the most
+	// telling part.
+	from := max(len(cleaned)-diagnosedTailLines, 0)
+	for _, line := range cleaned[from:] {
+		d.Tail = append(d.Tail, cleanLogLine(line))
+	}
+}
+
+func registerDiagnoseStackRunTool(s *server.MCPServer) {
+	tool := mcp.NewTool("diagnose_stack_run",
+		mcp.WithDescription(`Explain why a run of a Spacelift stack failed. Finds the phase the run failed in and returns a compact summary of the errors in its logs (Terraform diagnostics, provider errors, policy denials) with line references into that phase's logs. Prefer this over get_stack_run_logs to investigate failures.`),
+		mcp.WithToolAnnotation(mcp.ToolAnnotation{
+			Title:        "Diagnose Stack Run",
+			ReadOnlyHint: new(true),
+		}),
+		mcp.WithString("stack_id", mcp.Description("The ID of the stack"), mcp.Required()),
+		mcp.WithString("run_id", mcp.Description("The ID of the run"), mcp.Required()),
+		mcp.WithString("phase", mcp.Description("The phase to diagnose, for example PLANNING or APPLYING. Defaults to the phase the run failed in.")),
+	)
+
+	s.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if _, err := authenticated.Ensure(ctx, nil); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		stackID, err := request.RequireString("stack_id")
+		if err != nil {
+			return nil, err
+		}
+
+		runID, err := request.RequireString("run_id")
+		if err != nil {
+			return nil, err
+		}
+
+		run, err := getWaitedRun(ctx, stackID, runID)
+		if err != nil {
+			return nil, err
+		}
+
+		diagnosis := runDiagnosis{StackID: stackID, RunID: runID, State: run.State, Errors: []logError{}}
+		if len(run.History) > 0 && run.History[0].Terminal {
+			diagnosis.Note = run.History[0].Note
+		}
+
+		var phase *structs.RunStateTransition
+		switch requested := strings.ToUpper(request.GetString("phase", "")); {
+		case requested != "":
+			for i := range run.History {
+				if string(run.History[i].State) == requested {
+					phase = &run.History[i]
+					break
+				}
+			}
+			if phase == nil {
+				return mcp.NewToolResultError(fmt.Sprintf("run %s did not go through the %s phase", runID, requested)), nil
+			}
+		case run.State == "FINISHED":
+			diagnosis.Message = "The run finished successfully, there is nothing to diagnose."
+		default:
+			if len(run.History) == 0 || !run.History[0].Terminal {
+				diagnosis.Message = "The run has not ended yet, diagnosing its current phase."
+			}
+			phase = failingPhase(run.History)
+		}
+
+		if phase != nil && phase.HasLogs {
+			diagnosis.Phase = string(phase.State)
+			diagnosis.PhaseVersion = phase.StateVersion
+
+			phaseLogs, err := logs.StateLogs(ctx, stackID, runID, phase.State, phase.StateVersion)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to get the logs of the %s phase", phase.State)
+			}
+			diagnosis.diagnose(phaseLogs)
+		}
+
+		diagnosisJSON, err := json.Marshal(diagnosis)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal diagnosis to JSON")
+		}
+
+		return mcp.NewToolResultText(string(diagnosisJSON)), nil
+	})
+}
@@ -0,0 +1,115 @@
+package stack
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacelift-io/spacectl/client/fakeapi"
+	"github.com/spacelift-io/spacectl/client/structs"
+	"github.com/spacelift-io/spacectl/internal/cmd/authenticated"
+)
+
+func TestFailingPhase(t *testing.T) {
+	history := []structs.RunStateTransition{
+		{State: "FAILED", Terminal: true},
+		{State: "APPLYING", HasLogs: true, StateVersion: 2},
+		{State: "CONFIRMED"},
+		{State: "PLANNING", HasLogs: true},
+	}
+
+	phase := failingPhase(history)
+	require.NotNil(t, phase)
+	assert.Equal(t, structs.RunState("APPLYING"), phase.State)
+	assert.Equal(t, 2, phase.StateVersion)
+
+	assert.Nil(t, failingPhase([]structs.RunStateTransition{{State: "FAILED", Terminal: true}}))
+}
+
+func TestExtractLogErrors(t *testing.T) {
+	phaseLogs := "Initializing...\n" +
+		"\x1b[31m╷\x1b[0m\x1b[0m\n" +
+		"\x1b[31m│\x1b[0m \x1b[0m\x1b[1m\x1b[31mError: \x1b[0m\x1b[0m\x1b[1mcreating EC2 Instance: UnauthorizedOperation\x1b[0m\n" +
+		"\x1b[31m│\x1b[0m \x1b[0m\n" +
+		"\x1b[31m│\x1b[0m \x1b[0m  with aws_instance.web,\n" +
+		"\x1b[31m│\x1b[0m \x1b[0m  on main.tf line 12, in resource \"aws_instance\" \"web\":\n" +
+		"\x1b[31m│\x1b[0m \x1b[0m  12: resource \"aws_instance\" \"web\" {\n" +
+		"\x1b[31m╵\x1b[0m\x1b[0m\n" +
+		"╷\n" +
+		"│ Error: Unsupported argument\n" +
+		"│ \n" +
+		"│   on variables.tf line 3, in variable \"region\":\n" +
+		"╵\n" +
+		"Plan policy \"no-public-buckets\" denied the change\n" +
+		"[ERROR] something went wrong\n" +
+		"Done.\n"
+
+	found, truncated := extractLogErrors(phaseLogs)
+	assert.False(t, truncated)
+	require.Len(t, found, 4)
+
+	assert.Equal(t, logError{
+		Kind:     "provider",
+		Line:     3,
+		EndLine:  7,
+		Summary:  "creating EC2 Instance: UnauthorizedOperation",
+		Resource: "aws_instance.web",
+		Source:   "main.tf:12",
+		Detail: []string{
+			"with aws_instance.web,",
+			`on main.tf line 12, in resource "aws_instance" "web":`,
+			`12: resource "aws_instance" "web" {`,
+		},
+	}, found[0])
+
+	assert.Equal(t, "terraform", found[1].Kind)
+	assert.Equal(t, 10, found[1].Line)
+	assert.Equal(t, "Unsupported argument", found[1].Summary)
+	assert.Equal(t, "variables.tf:3", found[1].Source)
+
+	assert.Equal(t, logError{Kind: "policy", Line: 14, EndLine: 14, Summary: `Plan policy "no-public-buckets" denied the change`}, found[2])
+	assert.Equal(t, logError{Kind: "error", Line: 15, EndLine: 15, Summary: "[ERROR] something went wrong"}, found[3])
+}
+
+func TestRunDiagnosisTail(t *testing.T) {
+	var diagnosis runDiagnosis
+	diagnosis.diagnose("one\ntwo\n\x1b[1mthree\x1b[0m\n")
+
+	assert.Equal(t, 3, diagnosis.LogLines)
+	assert.Empty(t, diagnosis.Errors)
+	assert.Equal(t, []string{"one", "two", "three"}, diagnosis.Tail)
+}
+
+func TestDiagnoseStackRunTool(t *testing.T) {
+	api, err := fakeapi.NewServer(fakeapi.DefaultFixtures())
+	require.NoError(t, err)
+	t.Cleanup(api.Close)
+
+	s := server.NewMCPServer("test", "1.0.0")
+	RegisterMCPTools(s, McpOptions{})
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "diagnose_stack_run"
+	request.Params.Arguments = map[string]any{"stack_id": "app-servers", "run_id": "01JXAPP0000000000000000001"}
+
+	ctx := authenticated.WithClient(t.Context(), api.Client())
+	result, err := s.GetTool("diagnose_stack_run").Handler(ctx, request)
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+
+	var diagnosis runDiagnosis
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &diagnosis))
+
+	assert.Equal(t, "FAILED", diagnosis.State)
+	assert.Equal(t, "Planning failed", *diagnosis.Note)
+	assert.Equal(t, "PLANNING", diagnosis.Phase)
+	require.Len(t, diagnosis.Errors, 1)
+	assert.Equal(t, "provider", diagnosis.Errors[0].Kind)
+	assert.Equal(t, "aws_instance.web", diagnosis.Errors[0].Resource)
+	assert.Equal(t, "main.tf:12", diagnosis.Errors[0].Source)
+	assert.Equal(t, 4, diagnosis.Errors[0].Line)
+}
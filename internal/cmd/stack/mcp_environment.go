@@ -0,0 +1,334 @@
+package stack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/pkg/errors"
+	"github.com/shurcooL/graphql"
+
+	"github.com/spacelift-io/spacectl/client/stacks"
+	"github.com/spacelift-io/spacectl/internal/cmd/authenticated"
+)
+
+type stackEnvironmentContext struct {
+	ContextID      string `json:"contextId"`
+	Name           string `json:"name"`
+	Priority       int    `json:"priority"`
+	IsAutoattached bool   `json:"isAutoattached"`
+}
+
+type stackEnvironment struct {
+	Environment      []listEnvElementOutput    `json:"environment"`
+	AttachedContexts []stackEnvironmentContext `json:"attachedContexts"`
+}
+
+// newStackEnvironment builds the effective environment of a stack. Values of
+// write-only elements are dropped even if the API returned them.
+func newStackEnvironment(query *listEnvQuery) (*stackEnvironment, error) {
+	elements, err := query.elements()
+	if err != nil {
+		return nil, err
+	}
+
+	env := &stackEnvironment{
+		Environment:      []listEnvElementOutput{},
+		AttachedContexts: []stackEnvironmentContext{},
+	}
+
+	for _, element := range elements {
+		if element.WriteOnly {
+			element.Value = nil
+		}
+		env.Environment = append(env.Environment, element)
+	}
+
+	for _, attached := range query.Stack.AttachedContexts {
+		env.AttachedContexts = append(env.AttachedContexts, stackEnvironmentContext{
+			ContextID:      attached.ContextID,
+			Name:           attached.Name,
+			Priority:       attached.Priority,
+			IsAutoattached: attached.IsAutoattached,
+		})
+	}
+
+	return env, nil
+}
+
+func registerGetStackEnvironmentTool(s *server.MCPServer) {
+	tool := mcp.NewTool("get_stack_environment",
+		mcp.WithDescription(`Retrieve the effective environment of a Spacelift stack: its own environment variables and mounted files, the ones coming from attached contexts, and the list of attached contexts. Values of write-only elements are never returned.`),
+		mcp.WithToolAnnotation(mcp.ToolAnnotation{
+			Title:        "Get Stack Environment",
+			ReadOnlyHint: new(true),
+		}),
+		mcp.WithString("stack_id", mcp.Description("The ID of the stack"), mcp.Required()),
+	)
+
+	s.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if _, err := authenticated.Ensure(ctx, nil); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		stackID, err := request.RequireString("stack_id")
+		if err != nil {
+			return nil, err
+		}
+
+		var query listEnvQuery
+		variables := map[string]any{
+			"stack": graphql.ID(stackID),
+		}
+
+		if err := authenticated.Client(ctx).Query(ctx, &query, variables); err != nil {
+			return nil, errors.Wrapf(err, "failed to query environment of stack %q", stackID)
+		}
+
+		env, err := newStackEnvironment(&query)
+		if err != nil {
+			return nil, err
+		}
+
+		envJSON, err := json.Marshal(env)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal environment to JSON")
+		}
+
+		return mcp.NewToolResultText(string(envJSON)), nil
+	})
+}
+
+func registerSetStackEnvironmentVariableTool(s *server.MCPServer) {
+	tool := mcp.NewTool("set_stack_environment_variable",
+		mcp.WithDescription(`Set an environment variable on a Spacelift stack, replacing its value if it already exists. Write-only variables can only be read by runs.`),
+		mcp.WithToolAnnotation(mcp.ToolAnnotation{
+			Title:           "Set Stack Environment Variable",
+			DestructiveHint: new(true),
+			IdempotentHint:  new(true),
+		}),
+		mcp.WithString("stack_id", mcp.Description("The ID of the stack"), mcp.Required()),
+		mcp.WithString("name", mcp.Description("The name of the environment variable"), mcp.Required()),
+		mcp.WithString("value", mcp.Description("The value of the environment variable"), mcp.Required()),
+		mcp.WithBoolean("write_only", mcp.Description("Whether the value can only be read by runs (default: true)")),
+	)
+
+	s.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if _, err := authenticated.Ensure(ctx, nil); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		stackID, err := request.RequireString("stack_id")
+		if err != nil {
+			return nil, err
+		}
+
+		name, err := request.RequireString("name")
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := request.RequireString("value")
+		if err != nil {
+			return nil, err
+		}
+
+		element, err := stacks.New(authenticated.Client(ctx)).AddConfig(ctx, stackID, ConfigInput{
+			ID:        graphql.ID(name),
+			Type:      envVarTypeConfig,
+			Value:     graphql.String(value),
+			WriteOnly: graphql.Boolean(request.GetBool("write_only", true)),
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to set environment variable")
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf(
+			"Environment variable %s has been set on stack %s (write only: %t)",
+			element.ID,
+			stackID,
+			element.WriteOnly,
+		)), nil
+	})
+}
+
+func registerAttachStackContextTool(s *server.MCPServer) {
+	tool := mcp.NewTool("attach_stack_context",
+		mcp.WithDescription(`Attach a Spacelift context to a stack, adding its environment variables, mounted files and hooks to the stack's runs. Contexts with a lower priority take precedence.`),
+		mcp.WithToolAnnotation(mcp.ToolAnnotation{
+			Title:           "Attach Stack Context",
+			DestructiveHint: new(false),
+		}),
+		mcp.WithString("stack_id", mcp.Description("The ID of the stack"), mcp.Required()),
+		mcp.WithString("context_id", mcp.Description("The ID of the context"), mcp.Required()),
+		mcp.WithNumber("priority", mcp.Description("The priority of the context (default: 0)")),
+	)
+
+	s.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if _, err := authenticated.Ensure(ctx, nil); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		stackID, err := request.RequireString("stack_id")
+		if err != nil {
+			return nil, err
+		}
+
+		contextID, err := request.RequireString("context_id")
+		if err != nil {
+			return nil, err
+		}
+
+		var mutation struct {
+			ContextAttach struct {
+				ID string `graphql:"id"`
+			} `graphql:"contextAttach(id: $context, stack: $stack, priority: $priority)"`
+		}
+
+		variables := map[string]any{
+			"context":  graphql.ID(contextID),
+			"stack":    graphql.ID(stackID),
+			"priority": graphql.Int(request.GetInt("priority", 0)), //nolint: gosec
+		}
+
+		if err := authenticated.Client(ctx).Mutate(ctx, &mutation, variables); err != nil {
+			return nil, errors.Wrap(err, "failed to attach context")
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("Context %s has been attached to stack %s", contextID, stackID)), nil
+	})
+}
+
+func registerDetachStackContextTool(s *server.MCPServer) {
+	tool := mcp.NewTool("detach_stack_context",
+		mcp.WithDescription(`Detach a Spacelift context from a stack. Contexts attached automatically through labels cannot be detached.`),
+		mcp.WithToolAnnotation(mcp.ToolAnnotation{
+			Title:           "Detach Stack Context",
+			DestructiveHint: new(true),
+		}),
+		mcp.WithString("stack_id", mcp.Description("The ID of the stack"), mcp.Required()),
+		mcp.WithString("context_id", mcp.Description("The ID of the context"), mcp.Required()),
+	)
+
+	s.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if _, err := authenticated.Ensure(ctx, nil); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		stackID, err := request.RequireString("stack_id")
+		if err != nil {
+			return nil, err
+		}
+
+		contextID, err := request.RequireString("context_id")
+		if err != nil {
+			return nil, err
+		}
+
+		var query struct {
+			Stack *struct {
+				AttachedContexts []struct {
+					ID             string `graphql:"id"`
+					ContextID      string `graphql:"contextId"`
+					IsAutoattached bool   `graphql:"isAutoattached"`
+				} `graphql:"attachedContexts"`
+			} `graphql:"stack(id: $stack)"`
+		}
+
+		if err := authenticated.Client(ctx).Query(ctx, &query, map[string]any{"stack": graphql.ID(stackID)}); err != nil {
+			return nil, errors.Wrapf(err, "failed to query contexts attached to stack %q", stackID)
+		}
+
+		if query.Stack == nil {
+			return mcp.NewToolResultError(fmt.Sprintf("stack %q not found", stackID)), nil
+		}
+
+		var attachmentID string
+		for _, attached := range query.Stack.AttachedContexts {
+			if attached.ContextID != contextID {
+				continue
+			}
+
+			if attached.IsAutoattached {
+				return mcp.NewToolResultError(fmt.Sprintf("context %q is attached automatically through labels and cannot be detached", contextID)), nil
+			}
+
+			attachmentID = attached.ID
+		}
+
+		if attachmentID == "" {
+			return mcp.NewToolResultError(fmt.Sprintf("context %q is not attached to stack %q", contextID, stackID)), nil
+		}
+
+		var mutation struct {
+			ContextDetach *struct {
+				ID string `graphql:"id"`
+			} `graphql:"contextDetach(id: $id)"`
+		}
+
+		if err := authenticated.Client(ctx).Mutate(ctx, &mutation, map[string]any{"id": graphql.ID(attachmentID)}); err != nil {
+			return nil, errors.Wrap(err, "failed to detach context")
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("Context %s has been detached from stack %s", contextID, stackID)), nil
+	})
+}
+
+func registerLockStackTool(s *server.MCPServer) {
+	tool := mcp.NewTool("lock_stack",
+		mcp.WithDescription(`Lock a Spacelift stack for exclusive use, preventing other users from triggering runs on it until it is unlocked.`),
+		mcp.WithToolAnnotation(mcp.ToolAnnotation{
+			Title:           "Lock Stack",
+			DestructiveHint: new(false),
+		}),
+		mcp.WithString("stack_id", mcp.Description("The ID of the stack"), mcp.Required()),
+		mcp.WithString("note", mcp.Description("Description of why the lock was acquired")),
+	)
+
+	s.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if _, err := authenticated.Ensure(ctx, nil); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		stackID, err := request.RequireString("stack_id")
+		if err != nil {
+			return nil, err
+		}
+
+		if err := stacks.New(authenticated.Client(ctx)).Lock(ctx, stackID, request.GetString("note", "")); err != nil {
+			return nil, errors.Wrap(err, "failed to lock stack")
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("Stack %s has been locked", stackID)), nil
+	})
+}
+
+func registerUnlockStackTool(s *server.MCPServer) {
+	tool := mcp.NewTool("unlock_stack",
+		mcp.WithDescription(`Release the lock on a Spacelift stack, allowing other users to trigger runs on it again.`),
+		mcp.WithToolAnnotation(mcp.ToolAnnotation{
+			Title:           "Unlock Stack",
+			DestructiveHint: new(true),
+		}),
+		mcp.WithString("stack_id", mcp.Description("The ID of the stack"), mcp.Required()),
+	)
+
+	s.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if _, err := authenticated.Ensure(ctx, nil); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		stackID, err := request.RequireString("stack_id")
+		if err != nil {
+			return nil, err
+		}
+
+		if err := stacks.New(authenticated.Client(ctx)).Unlock(ctx, stackID); err != nil {
+			return nil, errors.Wrap(err, "failed to unlock stack")
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("Stack %s has been unlocked", stackID)), nil
+	})
+}
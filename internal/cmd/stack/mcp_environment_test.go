@@ -0,0 +1,50 @@
+package stack
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewStackEnvironment(t *testing.T) {
+	var query listEnvQuery
+	require.NoError(t, json.Unmarshal([]byte(`{"stack": {
+		"runtimeConfig": [
+			{"element": {"id": "AWS_REGION", "type": "ENVIRONMENT_VARIABLE", "value": "eu-west-1"}},
+			{"element": {"id": "TF_VAR_password", "type": "ENVIRONMENT_VARIABLE", "value": "hunter2", "writeOnly": true}},
+			{"element": {"id": "TF_VAR_spacelift_stack_id", "type": "ENVIRONMENT_VARIABLE", "runtime": true}},
+			{"context": {"id": "shared", "contextName": "Shared"}, "element": {"id": "config.json", "type": "FILE_MOUNT", "value": "`+base64.StdEncoding.EncodeToString([]byte(`{}`))+`"}}
+		],
+		"attachedContexts": [
+			{"contextId": "shared", "name": "Shared", "priority": 1},
+			{"contextId": "labelled", "name": "Labelled", "isAutoattached": true, "config": [
+				{"id": "TOKEN", "type": "ENVIRONMENT_VARIABLE", "value": "secret", "writeOnly": true}
+			]}
+		]
+	}}`), &query))
+
+	env, err := newStackEnvironment(&query)
+	require.NoError(t, err)
+
+	require.Len(t, env.Environment, 5)
+	assert.Equal(t, "eu-west-1", *env.Environment[0].Value)
+	assert.Nil(t, env.Environment[1].Value, "write-only values are never returned")
+	assert.True(t, env.Environment[2].Runtime)
+	assert.Equal(t, "{}", *env.Environment[3].Value)
+	assert.Equal(t, "Shared", *env.Environment[3].Context)
+	assert.Equal(t, "TOKEN", env.Environment[4].Name)
+	assert.Nil(t, env.Environment[4].Value, "write-only values are never returned")
+
+	assert.Equal(t, []stackEnvironmentContext{
+		{ContextID: "shared", Name: "Shared", Priority: 1},
+		{ContextID: "labelled", Name: "Labelled", IsAutoattached: true},
+	}, env.AttachedContexts)
+
+	out, err := json.Marshal(env)
+	require.NoError(t, err)
+	assert.NotContains(t, string(out), "hunter2")
+	assert.NotContains(t, string(out), "secret")
+}
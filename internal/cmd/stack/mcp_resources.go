@@ -0,0 +1,538 @@
+package stack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/pkg/errors"
+	"github.com/shurcooL/graphql"
+
+	"github.com/spacelift-io/spacectl/internal/cmd/authenticated"
+)
+
+const (
+	runSubscriptionInterval       = 10 * time.Second
+	maxRunSubscriptionsPerSession = 50
+	promptLogLines                = 200
+)
+
+// runResourceURI matches the URIs of the run documents clients can subscribe to.
+var runResourceURI = regexp.MustCompile(`^spacelift://stack/([^/]+)/run/([^/]+)(/logs|/changes)?$`)
+
+// RegisterMCPResources registers the stack and run documents. Clients
+// subscribing to a run, its logs or changes are notified when its state
+// changes, which needs the hooks to be passed to the server. Subscriptions
+// don't go through the resource middlewares, so checkStack, if set, is called
+// instead to refuse those to the runs of a stack.
+func RegisterMCPResources(s *server.MCPServer, hooks *server.Hooks, checkStack func(ctx context.Context, stackID string) error) {
+	registerStackResource(s)
+	registerRunResource(s)
+	registerRunLogsResource(s)
+	registerRunChangesResource(s)
+
+	subscriptions := newRunSubscriptions(func(sessionID, uri string) {
+		_ = s.SendNotificationToSpecificClient(sessionID, mcp.MethodNotificationResourceUpdated, map[string]any{"uri": uri})
+	})
+	subscriptions.checkStack = checkStack
+
+	hooks.AddAfterSubscribe(func(ctx context.Context, _ any, message *mcp.SubscribeRequest, _ *mcp.EmptyResult) {
+		if session := server.ClientSessionFromContext(ctx); session != nil {
+			if err := subscriptions.subscribe(ctx, session.SessionID(), message.Params.URI); err != nil {
+				_ = s.SendLogMessageToClient(ctx, mcp.NewLoggingMessageNotification(mcp.LoggingLevelWarning, "spacectl", err.Error()))
+			}
+		}
+	})
+	hooks.AddAfterUnsubscribe(func(ctx context.Context, _ any, message *mcp.UnsubscribeRequest, _ *mcp.EmptyResult) {
+		if session := server.ClientSessionFromContext(ctx); session != nil {
+			subscriptions.unsubscribe(session.SessionID(), message.Params.URI)
+		}
+	})
+	hooks.AddOnUnregisterSession(func(_ context.Context, session server.ClientSession) {
+		subscriptions.unsubscribeAll(session.SessionID())
+	})
+}
+
+// RegisterMCPPrompts registers the prompts working on stack runs.
+func RegisterMCPPrompts(s *server.MCPServer) {
+	registerDiagnoseFailedRunPrompt(s)
+	registerReviewProposedChangesPrompt(s)
+}
+
+func registerStackResource(s *server.MCPServer) {
+	template := mcp.NewResourceTemplate("spacelift://stack/{stack_id}", "Stack",
+		mcp.WithTemplateDescription("The settings of a Spacelift stack: repository, branch, attached contexts and policies, hooks and state."),
+		mcp.WithTemplateMIMEType("application/json"),
+	)
+
+	s.AddResourceTemplate(template, func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		if _, err := authenticated.Ensure(ctx, nil); err != nil {
+			return nil, err
+		}
+		stackID := resourceArgument(request, "stack_id")
+
+		var query showStackQuery[vendorConfigBasic]
+		if err := authenticated.Client(ctx).Query(ctx, &query, map[string]any{"stackId": graphql.ID(stackID)}); err != nil {
+			return nil, errors.Wrapf(err, "failed to query for stack ID %q", stackID)
+		}
+
+		if query.Stack == nil {
+			return nil, fmt.Errorf("stack %q not found", stackID)
+		}
+
+		return jsonResourceContents(request.Params.URI, query.Stack)
+	})
+}
+
+func registerRunResource(s *server.MCPServer) {
+	template := mcp.NewResourceTemplate("spacelift://stack/{stack_id}/run/{run_id}", "Stack run",
+		mcp.WithTemplateDescription("A run of a Spacelift stack with its state, commit and resource delta. Subscribe to it to be notified when its state changes."),
+		mcp.WithTemplateMIMEType("application/json"),
+	)
+
+	s.AddResourceTemplate(template, func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		if _, err := authenticated.Ensure(ctx, nil); err != nil {
+			return nil, err
+		}
+
+		run, err := getMCPRun(ctx, resourceArgument(request, "stack_id"), resourceArgument(request, "run_id"))
+		if err != nil {
+			return nil, err
+		}
+
+		return jsonResourceContents(request.Params.URI, run)
+	})
+}
+
+func registerRunLogsResource(s *server.MCPServer) {
+	template := mcp.NewResourceTemplate("spacelift://stack/{stack_id}/run/{run_id}/logs", "Stack run logs",
+		mcp.WithTemplateDescription("The logs of all the phases of a stack run. Subscribe to it to be notified when the run state changes."),
+		mcp.WithTemplateMIMEType("text/plain"),
+	)
+
+	s.AddResourceTemplate(template, func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		if _, err := authenticated.Ensure(ctx, nil); err != nil {
+			return nil, err
+		}
+
+		output, err := collectRunLogs(ctx, resourceArgument(request, "stack_id"), resourceArgument(request, "run_id"))
+		if err != nil {
+			return nil, err
+		}
+
+		return []mcp.ResourceContents{mcp.TextResourceContents{URI: request.Params.URI, MIMEType: "text/plain", Text: output}}, nil
+	})
+}
+
+func registerRunChangesResource(s *server.MCPServer) {
+	template := mcp.NewResourceTemplate("spacelift://stack/{stack_id}/run/{run_id}/changes", "Stack run changes",
+		mcp.WithTemplateDescription("The resource changes planned or applied by a stack run."),
+		mcp.WithTemplateMIMEType("application/json"),
+	)
+
+	s.AddResourceTemplate(template, func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		if _, err := authenticated.Ensure(ctx, nil); err != nil {
+			return nil, err
+		}
+
+		changes, err := getRunChanges(ctx, resourceArgument(request, "stack_id"), resourceArgument(request, "run_id"))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get run changes")
+		}
+
+		return jsonResourceContents(request.Params.URI, changes)
+	})
+}
+
+func registerDiagnoseFailedRunPrompt(s *server.MCPServer) {
+	prompt := mcp.NewPrompt("diagnose_failed_run",
+		mcp.WithPromptDescription("Find out why a stack run failed and how to fix it."),
+		mcp.WithArgument("stack_id", mcp.ArgumentDescription("The ID of the stack"), mcp.RequiredArgument()),
+		mcp.WithArgument("run_id", mcp.ArgumentDescription("The ID of the failed run"), mcp.RequiredArgument()),
+	)
+
+	s.AddPrompt(prompt, func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		if _, err := authenticated.Ensure(ctx, nil); err != nil {
+			return nil, err
+		}
+		stackID, runID := request.Params.Arguments["stack_id"], request.Params.Arguments["run_id"]
+
+		run, err := getMCPRun(ctx, stackID, runID)
+		if err != nil {
+			return nil, err
+		}
+
+		runLogs, err := collectRunLogs(ctx, stackID, runID)
+		if err != nil {
+			return nil, err
+		}
+
+		skip := max(strings.Count(runLogs, "\n")+1-promptLogLines, 0)
+		runLogs = trimStringByLines(runLogs, &skip, nil)
+
+		runDocument, err := jsonResourceContents(runURI(stackID, runID), run)
+		if err != nil {
+			return nil, err
+		}
+
+		return mcp.NewGetPromptResult(fmt.Sprintf("Diagnose run %s of stack %s", runID, stackID), []mcp.PromptMessage{
+			mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(fmt.Sprintf(
+				"Run %s of stack %s ended in the %s state. Its details and the last %d lines of its logs are attached.\n\n"+
+					"1. Identify the phase that failed and quote the lines with the errors.\n"+
+					"2. Explain the most likely cause: code, provider credentials, policy denial, state lock or infrastructure.\n"+
+					"3. Suggest a fix, and whether the run can simply be retried.\n\n"+
+					"If the attached logs are not enough, read earlier lines with the get_stack_run_logs tool.",
+				runID, stackID, run.State, promptLogLines,
+			))),
+			mcp.NewPromptMessage(mcp.RoleUser, mcp.NewEmbeddedResource(runDocument[0])),
+			mcp.NewPromptMessage(mcp.RoleUser, mcp.NewEmbeddedResource(mcp.TextResourceContents{
+				URI:      runURI(stackID, runID) + "/logs",
+				MIMEType: "text/plain",
+				Text:     runLogs,
+			})),
+		}), nil
+	})
+}
+
+func registerReviewProposedChangesPrompt(s *server.MCPServer) {
+	prompt := mcp.NewPrompt("review_proposed_changes",
+		mcp.WithPromptDescription("Review the resource changes of a run before it is confirmed or a pull request is merged."),
+		mcp.WithArgument("stack_id", mcp.ArgumentDescription("The ID of the stack"), mcp.RequiredArgument()),
+		mcp.WithArgument("run_id", mcp.ArgumentDescription("The ID of the proposed or unconfirmed run"), mcp.RequiredArgument()),
+	)
+
+	s.AddPrompt(prompt, func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		if _, err := authenticated.Ensure(ctx, nil); err != nil {
+			return nil, err
+		}
+		stackID, runID := request.Params.Arguments["stack_id"], request.Params.Arguments["run_id"]
+
+		run, err := getMCPRun(ctx, stackID, runID)
+		if err != nil {
+			return nil, err
+		}
+
+		changes, err := getRunChanges(ctx, stackID, runID)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get run changes")
+		}
+
+		runDocument, err := jsonResourceContents(runURI(stackID, runID), run)
+		if err != nil {
+			return nil, err
+		}
+
+		changesDocument, err := jsonResourceContents(runURI(stackID, runID)+"/changes", changes)
+		if err != nil {
+			return nil, err
+		}
+
+		return mcp.NewGetPromptResult(fmt.Sprintf("Review the changes of run %s of stack %s", runID, stackID), []mcp.PromptMessage{
+			mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(fmt.Sprintf(
+				"Review the resource changes of run %s of stack %s, attached with the run details.\n\n"+
+					"1. Summarise what the run adds, changes, replaces and deletes.\n"+
+					"2. Call out every deletion and replacement, and whether it could lose data or cause downtime.\n"+
+					"3. Point out anything unexpected for the commit message, like changes to unrelated resources.\n"+
+					"4. Conclude whether it is safe to confirm, and what to check first if not.",
+				runID, stackID,
+			))),
+			mcp.NewPromptMessage(mcp.RoleUser, mcp.NewEmbeddedResource(runDocument[0])),
+			mcp.NewPromptMessage(mcp.RoleUser, mcp.NewEmbeddedResource(changesDocument[0])),
+		}), nil
+	})
+}
+
+func getMCPRun(ctx context.Context, stackID, runID string) (*runsJSONQuery, error) {
+	var query struct {
+		Stack *struct {
+			Run *runsJSONQuery `graphql:"run(id: $runId)"`
+		} `graphql:"stack(id: $stackId)"`
+	}
+
+	if err := authenticated.Client(ctx).Query(ctx, &query, map[string]any{"stackId": stackID, "runId": runID}); err != nil {
+		return nil, errors.Wrap(err, "failed to query run")
+	}
+
+	if query.Stack == nil {
+		return nil, fmt.Errorf("stack %q not found", stackID)
+	}
+
+	if query.Stack.Run == nil {
+		return nil, fmt.Errorf("run %q in stack %q not found", runID, stackID)
+	}
+
+	return query.Stack.Run, nil
+}
+
+func runURI(stackID, runID string) string {
+	return fmt.Sprintf("spacelift://stack/%s/run/%s", stackID, runID)
+}
+
+// resourceArgument returns a variable of the URI template that matched.
+func resourceArgument(request mcp.ReadResourceRequest, name string) string {
+	switch v := request.Params.Arguments[name].(type) {
+	case string:
+		return v
+	case []string:
+		if len(v) > 0 {
+			return v[0]
+		}
+	}
+
+	return ""
+}
+
+func jsonResourceContents(uri string, v any) ([]mcp.ResourceContents, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal resource to JSON")
+	}
+
+	return []mcp.ResourceContents{mcp.TextResourceContents{URI: uri, MIMEType: "application/json", Text: string(data)}}, nil
+}
+
+// runSubscriptions polls the runs clients subscribed to, and notifies them
+// every time the state changes until the run ends.
+type runSubscriptions struct {
+	notify        func(sessionID, uri string)
+	getRun        func(ctx context.Context, stackID, runID string) (waitedRun, error)
+	interval      time.Duration
+	maxPerSession int
+	// checkStack, if set, refuses subscriptions to the runs of a stack.
+	checkStack func(ctx context.Context, stackID string) error
+
+	mu sync.Mutex
+	// watchers poll every run once, for all the subscriptions to it made
+	// with the same credentials.
+	watchers map[runKey]*runWatcher
+}
+
+type runKey struct {
+	credentials string
+	stackID     string
+	runID       string
+}
+
+type runWatcher struct {
+	cancel        context.CancelFunc
+	subscriptions map[runSubscription]bool
+}
+
+type runSubscription struct {
+	sessionID string
+	uri       string
+}
+
+func newRunSubscriptions(notify func(sessionID, uri string)) *runSubscriptions {
+	return &runSubscriptions{
+		notify:        notify,
+		getRun:        getWaitedRun,
+		interval:      runSubscriptionInterval,
+		maxPerSession: maxRunSubscriptionsPerSession,
+		watchers:      map[runKey]*runWatcher{},
+	}
+}
+
+func (r *runSubscriptions) subscribe(ctx context.Context, sessionID, uri string) error {
+	match := runResourceURI.FindStringSubmatch(uri)
+	if match == nil {
+		return nil
+	}
+
+	key := runKey{credentials: authenticated.Credentials(ctx), stackID: match[1], runID: match[2]}
+	subscription := runSubscription{sessionID: sessionID, uri: uri}
+
+	r.mu.Lock()
+	subscribed, err := r.admit(key, subscription)
+	r.mu.Unlock()
+
+	if subscribed || err != nil {
+		return err
+	}
+
+	if r.checkStack != nil {
+		if err := r.checkStack(ctx, key.stackID); err != nil {
+			return fmt.Errorf("not watching %s: %w", uri, err)
+		}
+	}
+
+	// Reading the run with the client of the subscriber checks that they
+	// can access it.
+	run, err := r.getRun(ctx, key.stackID, key.runID)
+	if err != nil {
+		return fmt.Errorf("not watching %s: %w", uri, err)
+	}
+
+	// The subscriber would otherwise never hear about a run which ended
+	// already.
+	if done, _ := run.waitOver(nil); done {
+		r.notify(sessionID, uri)
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if subscribed, err := r.admit(key, subscription); subscribed || err != nil {
+		return err
+	}
+
+	watcher, ok := r.watchers[key]
+	if !ok {
+		// The watcher outlives the subscribe request, but keeps the client of
+		// its context, which has the credentials of the key.
+		watchCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		watcher = &runWatcher{cancel: cancel, subscriptions: map[runSubscription]bool{}}
+		r.watchers[key] = watcher
+
+		go r.watch(watchCtx, watcher, key, run.State)
+	}
+
+	watcher.subscriptions[subscription] = true
+
+	return nil
+}
+
+// admit tells whether the subscription exists already, or refuses it if the
+// session has too many. The caller must hold the lock.
+func (r *runSubscriptions) admit(key runKey, subscription runSubscription) (bool, error) {
+	if watcher, ok := r.watchers[key]; ok && watcher.subscriptions[subscription] {
+		return true, nil
+	}
+
+	if r.sessionSubscriptions(subscription.sessionID) >= r.maxPerSession {
+		return false, fmt.Errorf("not watching %s: a session can subscribe to at most %d run documents", subscription.uri, r.maxPerSession)
+	}
+
+	return false, nil
+}
+
+func (r *runSubscriptions) sessionSubscriptions(sessionID string) int {
+	count := 0
+	for _, watcher := range r.watchers {
+		for subscription := range watcher.subscriptions {
+			if subscription.sessionID == sessionID {
+				count++
+			}
+		}
+	}
+
+	return count
+}
+
+func (r *runSubscriptions) unsubscribe(sessionID, uri string) {
+	match := runResourceURI.FindStringSubmatch(uri)
+	if match == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key := range r.watchers {
+		if key.stackID == match[1] && key.runID == match[2] {
+			r.remove(key, func(subscription runSubscription) bool {
+				return subscription == runSubscription{sessionID: sessionID, uri: uri}
+			})
+		}
+	}
+}
+
+func (r *runSubscriptions) unsubscribeAll(sessionID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key := range r.watchers {
+		r.remove(key, func(subscription runSubscription) bool {
+			return subscription.sessionID == sessionID
+		})
+	}
+}
+
+// remove deletes the matching subscriptions to a run, and stops polling it
+// when none is left. The caller must hold the lock.
+func (r *runSubscriptions) remove(key runKey, matches func(runSubscription) bool) {
+	watcher, ok := r.watchers[key]
+	if !ok {
+		return
+	}
+
+	maps.DeleteFunc(watcher.subscriptions, func(subscription runSubscription, _ bool) bool {
+		return matches(subscription)
+	})
+
+	if len(watcher.subscriptions) == 0 {
+		watcher.cancel()
+		delete(r.watchers, key)
+	}
+}
+
+func (r *runSubscriptions) watch(ctx context.Context, watcher *runWatcher, key runKey, lastState string) {
+	defer r.done(watcher, key)
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		run, err := r.getRun(ctx, key.stackID, key.runID)
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err != nil {
+			if isTransientWaitError(err) {
+				continue
+			}
+
+			// The run can't be read anymore, for example because the
+			// credentials were revoked: reading it shows the error to the
+			// subscribers.
+			r.notifyAll(watcher)
+			return
+		}
+
+		if run.State != lastState {
+			lastState = run.State
+			r.notifyAll(watcher)
+		}
+
+		if done, _ := run.waitOver(nil); done {
+			return
+		}
+	}
+}
+
+func (r *runSubscriptions) notifyAll(watcher *runWatcher) {
+	r.mu.Lock()
+	subscriptions := slices.Collect(maps.Keys(watcher.subscriptions))
+	r.mu.Unlock()
+
+	for _, subscription := range subscriptions {
+		r.notify(subscription.sessionID, subscription.uri)
+	}
+}
+
+// done forgets the run once it ended, unless it is watched again already.
+func (r *runSubscriptions) done(watcher *runWatcher, key runKey) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	watcher.cancel()
+	if r.watchers[key] == watcher {
+		delete(r.watchers, key)
+	}
+}
@@ -0,0 +1,278 @@
+package stack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shurcooL/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacelift-io/spacectl/client/structs"
+	"github.com/spacelift-io/spacectl/internal/cmd/authenticated"
+)
+
+func TestRunSubscriptions(t *testing.T) {
+	states := []string{"QUEUED", "QUEUED", "PLANNING", "PLANNING", "UNCONFIRMED", "FINISHED"}
+
+	var mu sync.Mutex
+	var notified []string
+	polled := 0
+	finished := make(chan struct{})
+
+	subscriptions := newRunSubscriptions(func(sessionID, uri string) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		notified = append(notified, sessionID+" "+uri)
+	})
+	subscriptions.interval = time.Millisecond
+	subscriptions.getRun = func(_ context.Context, stackID, runID string) (waitedRun, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		assert.Equal(t, "my-stack", stackID)
+		assert.Equal(t, "run-1", runID)
+
+		state := states[min(polled, len(states)-1)]
+		polled++
+		if polled == len(states) {
+			close(finished)
+		}
+
+		return waitedRun{State: state, History: []structs.RunStateTransition{{State: structs.RunState(state), Terminal: state == "FINISHED"}}}, nil
+	}
+
+	require.NoError(t, subscriptions.subscribe(t.Context(), "session-1", "spacelift://stack/my-stack"))
+	require.NoError(t, subscriptions.subscribe(t.Context(), "session-1", "spacelift://stack/my-stack/run/run-1/logs"))
+	require.NoError(t, subscriptions.subscribe(t.Context(), "session-1", "spacelift://stack/my-stack/run/run-1/logs"))
+
+	select {
+	case <-finished:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the run was not polled until it finished")
+	}
+
+	require.Eventually(t, func() bool {
+		subscriptions.mu.Lock()
+		defer subscriptions.mu.Unlock()
+
+		return len(subscriptions.watchers) == 0
+	}, 5*time.Second, time.Millisecond, "the subscription ends with the run")
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	uri := "session-1 spacelift://stack/my-stack/run/run-1/logs"
+	assert.Equal(t, []string{uri, uri, uri}, notified, "one notification per state change")
+}
+
+func TestRunSubscriptionsSharePoller(t *testing.T) {
+	var mu sync.Mutex
+	notified := map[string]int{}
+	state := "QUEUED"
+	polled := map[string]int{}
+
+	subscriptions := newRunSubscriptions(func(sessionID, uri string) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		notified[sessionID+" "+uri]++
+	})
+	subscriptions.interval = time.Millisecond
+	subscriptions.getRun = func(ctx context.Context, _, _ string) (waitedRun, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if state == "FINISHED" {
+			polled[authenticated.Credentials(ctx)]++
+			return waitedRun{State: state, History: []structs.RunStateTransition{{State: "FINISHED", Terminal: true}}}, nil
+		}
+
+		return waitedRun{State: state}, nil
+	}
+
+	alice := authenticated.WithCredentials(t.Context(), "alice")
+	bob := authenticated.WithCredentials(t.Context(), "bob")
+
+	require.NoError(t, subscriptions.subscribe(alice, "session-1", "spacelift://stack/my-stack/run/run-1"))
+	require.NoError(t, subscriptions.subscribe(alice, "session-1", "spacelift://stack/my-stack/run/run-1/logs"))
+	require.NoError(t, subscriptions.subscribe(alice, "session-2", "spacelift://stack/my-stack/run/run-1/logs"))
+	require.NoError(t, subscriptions.subscribe(bob, "session-3", "spacelift://stack/my-stack/run/run-1"))
+
+	subscriptions.mu.Lock()
+	assert.Len(t, subscriptions.watchers, 2, "a single watcher per run and credentials")
+	subscriptions.mu.Unlock()
+
+	mu.Lock()
+	state = "FINISHED"
+	mu.Unlock()
+
+	require.Eventually(t, func() bool {
+		subscriptions.mu.Lock()
+		defer subscriptions.mu.Unlock()
+
+		return len(subscriptions.watchers) == 0
+	}, 5*time.Second, time.Millisecond, "the subscriptions end with the run")
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	assert.Equal(t, map[string]int{"alice": 1, "bob": 1}, polled, "the run is polled once per tick and credentials")
+	assert.Equal(t, map[string]int{
+		"session-1 spacelift://stack/my-stack/run/run-1":      1,
+		"session-1 spacelift://stack/my-stack/run/run-1/logs": 1,
+		"session-2 spacelift://stack/my-stack/run/run-1/logs": 1,
+		"session-3 spacelift://stack/my-stack/run/run-1":      1,
+	}, notified)
+}
+
+func TestRunSubscriptionsAccess(t *testing.T) {
+	subscriptions := newRunSubscriptions(func(string, string) {})
+	subscriptions.interval = time.Hour
+	subscriptions.checkStack = func(_ context.Context, stackID string) error {
+		if stackID == "other-space" {
+			return errors.New(`stack "other-space" is in space "dev", this server is restricted to the spaces prod`)
+		}
+		return nil
+	}
+	subscriptions.getRun = func(ctx context.Context, _, _ string) (waitedRun, error) {
+		if authenticated.Credentials(ctx) == "bob" {
+			return waitedRun{}, errors.New("unauthorized: You're logged in. Maybe you don't have access to the resource?")
+		}
+		return waitedRun{State: "PLANNING"}, nil
+	}
+
+	alice := authenticated.WithCredentials(t.Context(), "alice")
+	bob := authenticated.WithCredentials(t.Context(), "bob")
+
+	err := subscriptions.subscribe(alice, "session-1", "spacelift://stack/other-space/run/run-1")
+	require.ErrorContains(t, err, "this server is restricted to the spaces prod")
+
+	require.NoError(t, subscriptions.subscribe(alice, "session-1", "spacelift://stack/my-stack/run/run-1"))
+
+	err = subscriptions.subscribe(bob, "session-2", "spacelift://stack/my-stack/run/run-1")
+	require.ErrorContains(t, err, "unauthorized", "access is checked with the client of every subscriber")
+
+	subscriptions.mu.Lock()
+	defer subscriptions.mu.Unlock()
+
+	require.Len(t, subscriptions.watchers, 1)
+	assert.Equal(t, map[runSubscription]bool{
+		{sessionID: "session-1", uri: "spacelift://stack/my-stack/run/run-1"}: true,
+	}, subscriptions.watchers[runKey{credentials: "alice", stackID: "my-stack", runID: "run-1"}].subscriptions)
+
+	subscriptions.watchers[runKey{credentials: "alice", stackID: "my-stack", runID: "run-1"}].cancel()
+}
+
+func TestRunSubscriptionsPermanentError(t *testing.T) {
+	var polled atomic.Int32
+	notified := make(chan string, 1)
+
+	subscriptions := newRunSubscriptions(func(sessionID, uri string) {
+		notified <- sessionID + " " + uri
+	})
+	subscriptions.interval = time.Millisecond
+	subscriptions.getRun = func(context.Context, string, string) (waitedRun, error) {
+		if polled.Add(1) == 1 {
+			return waitedRun{State: "PLANNING"}, nil
+		}
+		return waitedRun{}, fmt.Errorf("failed to query run: %w", graphql.GraphQLErrors{{Message: "forbidden"}})
+	}
+
+	require.NoError(t, subscriptions.subscribe(t.Context(), "session-1", "spacelift://stack/my-stack/run/run-1"))
+
+	select {
+	case uri := <-notified:
+		assert.Equal(t, "session-1 spacelift://stack/my-stack/run/run-1", uri, "reading the run shows the error")
+	case <-time.After(5 * time.Second):
+		t.Fatal("the subscriber was not notified of the error")
+	}
+
+	require.Eventually(t, func() bool {
+		subscriptions.mu.Lock()
+		defer subscriptions.mu.Unlock()
+
+		return len(subscriptions.watchers) == 0
+	}, 5*time.Second, time.Millisecond, "polling stops on permanent errors")
+	assert.EqualValues(t, 2, polled.Load())
+}
+
+func TestRunSubscriptionsFinishedRun(t *testing.T) {
+	notified := make(chan string, 1)
+
+	subscriptions := newRunSubscriptions(func(sessionID, uri string) {
+		notified <- sessionID + " " + uri
+	})
+	subscriptions.interval = time.Hour
+	subscriptions.getRun = func(context.Context, string, string) (waitedRun, error) {
+		return waitedRun{State: "FAILED", History: []structs.RunStateTransition{{State: "FAILED", Terminal: true}}}, nil
+	}
+
+	require.NoError(t, subscriptions.subscribe(t.Context(), "session-1", "spacelift://stack/my-stack/run/run-1/logs"))
+
+	select {
+	case uri := <-notified:
+		assert.Equal(t, "session-1 spacelift://stack/my-stack/run/run-1/logs", uri)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the run which already ended was not notified")
+	}
+
+	subscriptions.mu.Lock()
+	defer subscriptions.mu.Unlock()
+
+	assert.Empty(t, subscriptions.watchers, "the run is not polled")
+}
+
+func TestRunSubscriptionsLimit(t *testing.T) {
+	subscriptions := newRunSubscriptions(func(string, string) {})
+	subscriptions.interval = time.Hour
+	subscriptions.maxPerSession = 2
+	subscriptions.getRun = func(context.Context, string, string) (waitedRun, error) {
+		return waitedRun{State: "PLANNING"}, nil
+	}
+
+	require.NoError(t, subscriptions.subscribe(t.Context(), "session-1", "spacelift://stack/my-stack/run/run-1"))
+	require.NoError(t, subscriptions.subscribe(t.Context(), "session-1", "spacelift://stack/my-stack/run/run-2"))
+	require.NoError(t, subscriptions.subscribe(t.Context(), "session-1", "spacelift://stack/my-stack/run/run-2"), "subscribing again is a no-op")
+
+	err := subscriptions.subscribe(t.Context(), "session-1", "spacelift://stack/my-stack/run/run-3")
+	require.ErrorContains(t, err, "at most 2 run documents")
+
+	require.NoError(t, subscriptions.subscribe(t.Context(), "session-2", "spacelift://stack/my-stack/run/run-3"), "the limit is per session")
+
+	subscriptions.unsubscribe("session-1", "spacelift://stack/my-stack/run/run-1")
+	require.NoError(t, subscriptions.subscribe(t.Context(), "session-1", "spacelift://stack/my-stack/run/run-3"))
+
+	subscriptions.unsubscribeAll("session-1")
+	subscriptions.unsubscribeAll("session-2")
+}
+
+func TestRunSubscriptionsUnsubscribe(t *testing.T) {
+	subscriptions := newRunSubscriptions(func(string, string) {})
+	subscriptions.interval = time.Hour
+	subscriptions.getRun = func(context.Context, string, string) (waitedRun, error) {
+		return waitedRun{State: "PLANNING"}, nil
+	}
+
+	require.NoError(t, subscriptions.subscribe(t.Context(), "session-1", "spacelift://stack/my-stack/run/run-1"))
+	require.NoError(t, subscriptions.subscribe(t.Context(), "session-1", "spacelift://stack/my-stack/run/run-2/changes"))
+	require.NoError(t, subscriptions.subscribe(t.Context(), "session-2", "spacelift://stack/my-stack/run/run-1"))
+
+	subscriptions.unsubscribe("session-1", "spacelift://stack/my-stack/run/run-1")
+	subscriptions.unsubscribeAll("session-2")
+
+	subscriptions.mu.Lock()
+	defer subscriptions.mu.Unlock()
+
+	require.Len(t, subscriptions.watchers, 1)
+	require.Contains(t, subscriptions.watchers, runKey{stackID: "my-stack", runID: "run-2"})
+	assert.Equal(t, map[runSubscription]bool{
+		{sessionID: "session-1", uri: "spacelift://stack/my-stack/run/run-2/changes"}: true,
+	}, subscriptions.watchers[runKey{stackID: "my-stack", runID: "run-2"}].subscriptions)
+}
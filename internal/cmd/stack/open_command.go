@@ -20,7 +20,7 @@ import (
 
 func openCommandInBrowser(ctx context.Context, cliCmd *cli.Command) error {
 	if stackID := cliCmd.String(flagStackID.Name); stackID != "" {
-		return browser.OpenURL(authenticated.Client().URL(
+		return browser.OpenURL(authenticated.Client(ctx).URL(
 			"/stack/%s",
 			stackID,
 		))
@@ -70,7 +70,7 @@ func findAndOpenStackInBrowser(ctx context.Context, p *stackSearchParams) error
 		return err
 	}
 
-	return browser.OpenURL(authenticated.Client().URL(
+	return browser.OpenURL(authenticated.Client(ctx).URL(
 		"/stack/%s",
 		got.ID,
 	))
@@ -181,7 +181,7 @@ func searchStacks[T hasIDAndName](ctx context.Context, input structs.SearchInput
 		} `graphql:"searchStacks(input: $input)"`
 	}
 
-	if err := authenticated.Client().Query(
+	if err := authenticated.Client(ctx).Query(
 		ctx,
 		&query,
 		map[string]any{"input": input},
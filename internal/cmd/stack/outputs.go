@@ -37,7 +37,7 @@ func (c *showOutputsStackCommand) showOutputs(ctx context.Context, cliCmd *cli.C
 	}
 	outputID := cliCmd.String(flagOutputID.Name)
 
-	outputFormat, err := cmd.GetOutputFormat(cliCmd)
+	outputFormat, formatter, err := cmd.GetOutputFormat(cliCmd)
 	if err != nil {
 		return err
 	}
@@ -47,7 +47,7 @@ func (c *showOutputsStackCommand) showOutputs(ctx context.Context, cliCmd *cli.C
 		"stackId": graphql.ID(stackID),
 	}
 
-	if err := authenticated.Client().Query(ctx, &query, variables); err != nil {
+	if err := authenticated.Client(ctx).Query(ctx, &query, variables); err != nil {
 		return errors.Wrapf(err, "failed to query for stack ID %q", stackID)
 	}
 
@@ -69,11 +69,9 @@ func (c *showOutputsStackCommand) showOutputs(ctx context.Context, cliCmd *cli.C
 	switch outputFormat {
 	case cmd.OutputFormatTable:
 		return c.showOutputsTable(outputs)
-	case cmd.OutputFormatJSON:
-		return cmd.OutputJSON(outputs)
+	default:
+		return cmd.Output(formatter, outputs)
 	}
-
-	return fmt.Errorf("unknown output format: %v", outputFormat)
 }
 
 func (c *showOutputsStackCommand) showOutputsTable(outputs []output) error {
@@ -2,6 +2,8 @@ package stack
 
 import (
 	"context"
+	"slices"
+	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/shurcooL/graphql"
@@ -12,41 +14,121 @@ import (
 )
 
 func resourcesList(ctx context.Context, cliCmd *cli.Command) error {
+	filter, err := resourceFilterFromFlags(cliCmd)
+	if err != nil {
+		return err
+	}
+
+	if strings.EqualFold(cliCmd.String(cmd.FlagOutputFormat.Name), resourcesFormatTree) {
+		stacks, err := getStacksWithResources(ctx, cliCmd)
+		if err != nil {
+			return err
+		}
+
+		return outputResourcesTree(stacks, filter)
+	}
+
+	outputFormat, formatter, err := cmd.GetOutputFormat(cliCmd)
+	if err != nil {
+		return err
+	}
+
+	// The command used to only print JSON, so it stays the default.
+	if !cliCmd.IsSet(cmd.FlagOutputFormat.Name) {
+		outputFormat = cmd.OutputFormatJSON
+	}
+
+	switch outputFormat {
+	case cmd.OutputFormatTable:
+		stacks, err := getStacksWithResources(ctx, cliCmd)
+		if err != nil {
+			return err
+		}
+
+		return outputResourcesTable(filter.apply(stacks))
+	default:
+		return resourcesListJSON(ctx, cliCmd, formatter, filter)
+	}
+}
+
+func resourcesListJSON(ctx context.Context, cliCmd *cli.Command, formatter cmd.OutputFormatter, filter resourceFilter) error {
 	stackID, err := getStackID(ctx, cliCmd)
 	if err != nil {
 		if !errors.Is(err, errNoStackFound) {
 			return err
 		}
 
-		return resourcesListAllStacks(ctx)
+		return resourcesListAllStacks(ctx, formatter, filter)
+	}
+
+	return resourcesListOneStack(ctx, formatter, stackID, filter)
+}
+
+func resourcesListOneStack(ctx context.Context, formatter cmd.OutputFormatter, id string, filter resourceFilter) error {
+	stack, err := queryStackResources(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if !filter.isEmpty() {
+		stack.Entities = slices.DeleteFunc(stack.Entities, func(e managedEntity) bool { return !filter.matches(e) })
+	}
+
+	return cmd.Output(formatter, stack)
+}
+
+func resourcesListAllStacks(ctx context.Context, formatter cmd.OutputFormatter, filter resourceFilter) error {
+	stacks, err := queryAllStacksResources(ctx)
+	if err != nil {
+		return err
+	}
+
+	return cmd.Output(formatter, filter.apply(stacks))
+}
+
+// getStacksWithResources returns the resources of the stack selected by the
+// flags or the current directory, or of all stacks if no stack is selected.
+func getStacksWithResources(ctx context.Context, cliCmd *cli.Command) ([]stackWithResources, error) {
+	stackID, err := getStackID(ctx, cliCmd)
+	if err != nil {
+		if !errors.Is(err, errNoStackFound) {
+			return nil, err
+		}
+
+		return queryAllStacksResources(ctx)
+	}
+
+	stack, err := queryStackResources(ctx, stackID)
+	if err != nil {
+		return nil, err
 	}
 
-	return resourcesListOneStack(ctx, stackID)
+	return []stackWithResources{stack}, nil
 }
 
-func resourcesListOneStack(ctx context.Context, id string) error {
+func queryStackResources(ctx context.Context, id string) (stackWithResources, error) {
 	var query struct {
 		Stack stackWithResources `graphql:"stack(id: $id)"`
 	}
 
 	variables := map[string]any{"id": graphql.ID(id)}
-	if err := authenticated.Client().Query(ctx, &query, variables); err != nil {
-		return errors.Wrap(err, "failed to query one stack")
+	if err := authenticated.Client(ctx).Query(ctx, &query, variables); err != nil {
+		return stackWithResources{}, errors.Wrap(err, "failed to query one stack")
 	}
 
-	return cmd.OutputJSON(query.Stack)
+	return query.Stack, nil
 }
 
-func resourcesListAllStacks(ctx context.Context) error {
+func queryAllStacksResources(ctx context.Context) ([]stackWithResources, error) {
 	var query struct {
 		Stacks []stackWithResources `graphql:"stacks" json:"stacks,omitempty"`
 	}
 
-	if err := authenticated.Client().Query(ctx, &query, map[string]any{}); err != nil {
-		return errors.Wrap(err, "failed to query list of stacks")
+	if err := authenticated.Client(ctx).Query(ctx, &query, map[string]any{}); err != nil {
+		return nil, errors.Wrap(err, "failed to query list of stacks")
 	}
 
-	return cmd.OutputJSON(query.Stacks)
+	return query.Stacks, nil
 }
 
 type stackWithResources struct {
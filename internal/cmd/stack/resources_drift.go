@@ -0,0 +1,298 @@
+package stack
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pterm/pterm"
+	"github.com/urfave/cli/v3"
+
+	"github.com/spacelift-io/spacectl/internal/cmd"
+	"github.com/spacelift-io/spacectl/internal/cmd/authenticated"
+)
+
+// driftReport lists the drifted resources together with their counts grouped
+// by stack, space and resource type.
+type driftReport struct {
+	GeneratedAt int64           `json:"generatedAt"`
+	Total       int             `json:"total"`
+	ByStack     []driftGroup    `json:"byStack"`
+	BySpace     []driftGroup    `json:"bySpace"`
+	ByType      []driftGroup    `json:"byType"`
+	Resources   []driftResource `json:"resources"`
+}
+
+type driftGroup struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+	// OldestDriftedAt is the Unix timestamp of the longest-standing drift.
+	OldestDriftedAt int64 `json:"oldestDriftedAt"`
+}
+
+type driftResource struct {
+	StackID    string `json:"stackId"`
+	StackName  string `json:"stackName"`
+	Space      string `json:"space"`
+	Type       string `json:"type"`
+	Address    string `json:"address"`
+	DriftedAt  int64  `json:"driftedAt"`
+	DriftedFor string `json:"driftedFor"`
+	LastRunID  string `json:"lastRunId,omitempty"`
+	LastRunURL string `json:"lastRunUrl,omitempty"`
+}
+
+func resourcesDrift(ctx context.Context, cliCmd *cli.Command) error {
+	outputFormat, formatter, err := cmd.GetOutputFormat(cliCmd)
+	if err != nil {
+		return err
+	}
+
+	stacks, err := getStacksWithResources(ctx, cliCmd)
+	if err != nil {
+		return err
+	}
+
+	report := buildDriftReport(stacks, time.Now())
+	for i, r := range report.Resources {
+		if r.LastRunID != "" {
+			report.Resources[i].LastRunURL = authenticated.Client(ctx).URL("/stack/%s/run/%s", r.StackID, r.LastRunID)
+		}
+	}
+
+	if outputFormat == cmd.OutputFormatTable {
+		err = writeDriftTable(report)
+	} else {
+		err = cmd.Output(formatter, report.data(outputFormat))
+	}
+	if err != nil {
+		return err
+	}
+
+	if cliCmd.IsSet(flagDriftThreshold.Name) {
+		if threshold := cliCmd.Uint(flagDriftThreshold.Name); uint(report.Total) > threshold {
+			return fmt.Errorf("%d drifted resources exceed the threshold of %d", report.Total, threshold)
+		}
+	}
+
+	return nil
+}
+
+func buildDriftReport(stacks []stackWithResources, now time.Time) driftReport {
+	report := driftReport{
+		GeneratedAt: now.Unix(),
+		Resources:   []driftResource{},
+	}
+
+	for _, s := range stacks {
+		for _, e := range s.Entities {
+			if e.Drifted == nil {
+				continue
+			}
+
+			driftedAt := time.Unix(*e.Drifted, 0)
+
+			resource := driftResource{
+				StackID:    s.ID,
+				StackName:  s.Name,
+				Space:      s.Space,
+				Type:       e.Type,
+				Address:    e.Address,
+				DriftedAt:  *e.Drifted,
+				DriftedFor: humanizeDriftDuration(now.Sub(driftedAt)),
+			}
+
+			if e.Updater != nil {
+				resource.LastRunID = e.Updater.ID
+			} else if e.Creator != nil {
+				resource.LastRunID = e.Creator.ID
+			}
+
+			report.Resources = append(report.Resources, resource)
+		}
+	}
+
+	slices.SortStableFunc(report.Resources, func(a, b driftResource) int {
+		return cmp.Or(
+			strings.Compare(a.StackName, b.StackName),
+			cmp.Compare(a.DriftedAt, b.DriftedAt),
+			strings.Compare(a.Address, b.Address),
+		)
+	})
+
+	report.Total = len(report.Resources)
+	report.ByStack = groupDrift(report.Resources, func(r driftResource) string { return r.StackName })
+	report.BySpace = groupDrift(report.Resources, func(r driftResource) string { return r.Space })
+	report.ByType = groupDrift(report.Resources, func(r driftResource) string { return r.Type })
+
+	return report
+}
+
+// groupDrift counts the drifted resources by key, largest groups first.
+func groupDrift(resources []driftResource, key func(driftResource) string) []driftGroup {
+	indexes := make(map[string]int)
+	groups := []driftGroup{}
+
+	for _, r := range resources {
+		name := key(r)
+
+		i, found := indexes[name]
+		if !found {
+			i = len(groups)
+			indexes[name] = i
+			groups = append(groups, driftGroup{Name: name, OldestDriftedAt: r.DriftedAt})
+		}
+
+		groups[i].Count++
+		groups[i].OldestDriftedAt = min(groups[i].OldestDriftedAt, r.DriftedAt)
+	}
+
+	slices.SortStableFunc(groups, func(a, b driftGroup) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), strings.Compare(a.Name, b.Name))
+	})
+
+	return groups
+}
+
+// humanizeDriftDuration formats a duration with at most two units, for example
+// "3d 4h" or "12m".
+func humanizeDriftDuration(d time.Duration) string {
+	d = max(d, 0)
+
+	days := int(d / (24 * time.Hour))
+	hours := int(d % (24 * time.Hour) / time.Hour)
+	minutes := int(d % time.Hour / time.Minute)
+
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	default:
+		return fmt.Sprintf("%dm", minutes)
+	}
+}
+
+// data returns what is written in a structured output format: row-based
+// formats get one row per drifted resource, the others the whole report.
+// Markdown gets the report, which has its own layout.
+func (r driftReport) data(format cmd.OutputFormat) any {
+	switch format {
+	case cmd.OutputFormatCSV, cmd.OutputFormatJSONL:
+		return r.Resources
+	default:
+		return r
+	}
+}
+
+var driftResourceColumns = []string{"Stack", "Space", "Type", "Address", "Drifted At", "Drifted For", "Last Run"}
+
+func (r driftResource) row() []string {
+	return []string{
+		r.StackName,
+		r.Space,
+		r.Type,
+		r.Address,
+		time.Unix(r.DriftedAt, 0).UTC().Format(time.RFC3339),
+		r.DriftedFor,
+		r.LastRunID,
+	}
+}
+
+func writeDriftTable(report driftReport) error {
+	if report.Total == 0 {
+		pterm.Success.Println("No drifted resources found")
+		return nil
+	}
+
+	tableData := [][]string{driftResourceColumns}
+	for _, r := range report.Resources {
+		tableData = append(tableData, r.row())
+	}
+
+	if err := cmd.OutputTable(tableData, true); err != nil {
+		return err
+	}
+
+	for _, section := range []struct {
+		title  string
+		groups []driftGroup
+	}{
+		{"By stack", report.ByStack},
+		{"By space", report.BySpace},
+		{"By type", report.ByType},
+	} {
+		pterm.DefaultSection.WithLevel(2).Println(section.title)
+
+		if err := cmd.OutputTable(driftGroupRows(section.groups, report.GeneratedAt), true); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func driftGroupRows(groups []driftGroup, now int64) [][]string {
+	rows := [][]string{{"Name", "Drifted", "Oldest Drift"}}
+	for _, g := range groups {
+		rows = append(rows, []string{
+			g.Name,
+			fmt.Sprint(g.Count),
+			humanizeDriftDuration(time.Duration(now-g.OldestDriftedAt) * time.Second),
+		})
+	}
+
+	return rows
+}
+
+// WriteMarkdown writes the report in a layout ready to be posted to a chat or
+// an issue, used by the markdown output format.
+func (r driftReport) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# Drift report (%s)\n\n", time.Unix(r.GeneratedAt, 0).UTC().Format(time.RFC3339))
+
+	if r.Total == 0 {
+		b.WriteString("No drifted resources found.\n")
+		_, err := io.WriteString(w, b.String())
+		return err
+	}
+
+	fmt.Fprintf(&b, "%d drifted resources in %d stacks.\n", r.Total, len(r.ByStack))
+
+	for _, section := range []struct {
+		title  string
+		groups []driftGroup
+	}{
+		{"By stack", r.ByStack},
+		{"By space", r.BySpace},
+		{"By type", r.ByType},
+	} {
+		fmt.Fprintf(&b, "\n## %s\n\n", section.title)
+		if err := cmd.WriteMarkdownTable(&b, driftGroupRows(section.groups, r.GeneratedAt)); err != nil {
+			return err
+		}
+	}
+
+	b.WriteString("\n## Resources\n\n")
+
+	rows := [][]string{driftResourceColumns}
+	for _, resource := range r.Resources {
+		row := resource.row()
+		if resource.LastRunURL != "" {
+			row[len(row)-1] = fmt.Sprintf("[%s](%s)", resource.LastRunID, resource.LastRunURL)
+		}
+		rows = append(rows, row)
+	}
+
+	if err := cmd.WriteMarkdownTable(&b, rows); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,72 @@
+package stack
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacelift-io/spacectl/internal/cmd"
+)
+
+func TestBuildDriftReport(t *testing.T) {
+	now := time.Date(2025, 6, 10, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *int64 { return new(now.Add(-d).Unix()) }
+
+	stacks := []stackWithResources{
+		{
+			ID: "network", Name: "Network", Space: "root",
+			Entities: []managedEntity{
+				{Address: "aws_vpc.main", Type: "aws_vpc", Drifted: at(50 * time.Hour), Updater: &run{ID: "run-2"}, Creator: &run{ID: "run-1"}},
+				{Address: "aws_subnet.a", Type: "aws_subnet"},
+			},
+		},
+		{
+			ID: "app", Name: "App", Space: "prod",
+			Entities: []managedEntity{
+				{Address: "aws_s3_bucket.logs", Type: "aws_s3_bucket", Drifted: at(90 * time.Minute), Creator: &run{ID: "run-3"}},
+				{Address: "aws_s3_bucket.data", Type: "aws_s3_bucket", Drifted: at(3 * time.Hour)},
+			},
+		},
+	}
+
+	report := buildDriftReport(stacks, now)
+
+	require.Equal(t, 3, report.Total)
+	assert.Equal(t, []string{"aws_s3_bucket.data", "aws_s3_bucket.logs", "aws_vpc.main"}, []string{
+		report.Resources[0].Address, report.Resources[1].Address, report.Resources[2].Address,
+	})
+	assert.Equal(t, "run-3", report.Resources[1].LastRunID)
+	assert.Equal(t, "run-2", report.Resources[2].LastRunID)
+	assert.Equal(t, "2d 2h", report.Resources[2].DriftedFor)
+	assert.Equal(t, "1h 30m", report.Resources[1].DriftedFor)
+
+	assert.Equal(t, []driftGroup{
+		{Name: "App", Count: 2, OldestDriftedAt: *at(3 * time.Hour)},
+		{Name: "Network", Count: 1, OldestDriftedAt: *at(50 * time.Hour)},
+	}, report.ByStack)
+	assert.Equal(t, "aws_s3_bucket", report.ByType[0].Name)
+
+	t.Run("structured output", func(t *testing.T) {
+		assert.Equal(t, report.Resources, report.data(cmd.OutputFormatCSV))
+		assert.Equal(t, report.Resources, report.data(cmd.OutputFormatJSONL))
+		assert.Equal(t, report, report.data(cmd.OutputFormatJSON))
+		assert.Equal(t, report, report.data(cmd.OutputFormatYAML))
+		assert.Equal(t, report, report.data(cmd.OutputFormatMarkdown))
+	})
+
+	t.Run("markdown", func(t *testing.T) {
+		report.Resources[2].LastRunURL = "https://example.app.spacelift.io/stack/network/run/run-2"
+
+		require.Implements(t, (*cmd.MarkdownWriter)(nil), report.data(cmd.OutputFormatMarkdown))
+
+		var b strings.Builder
+		require.NoError(t, report.WriteMarkdown(&b))
+
+		assert.Contains(t, b.String(), "3 drifted resources in 2 stacks.\n")
+		assert.Contains(t, b.String(), "| App | 2 | 3h 0m |\n")
+		assert.Contains(t, b.String(), "[run-2](https://example.app.spacelift.io/stack/network/run/run-2) |\n")
+	})
+}
@@ -0,0 +1,264 @@
+package stack
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/spacelift-io/spacectl/internal/cmd"
+)
+
+// attributeCondition matches a resource attribute parsed from its values. The
+// path is dot-separated, with list elements addressed by their index, for
+// example "tags.env" or "versioning.0.enabled".
+type attributeCondition struct {
+	path    string
+	value   string
+	negate  bool
+	missing bool
+}
+
+// parseAttributeCondition parses PATH=VALUE, PATH!=VALUE and !PATH, the last
+// one matching resources where the attribute is absent, null or empty.
+func parseAttributeCondition(raw string) (attributeCondition, error) {
+	if path, found := strings.CutPrefix(raw, "!"); found {
+		if path == "" {
+			return attributeCondition{}, fmt.Errorf("invalid attribute condition %q, expected PATH=VALUE, PATH!=VALUE or !PATH", raw)
+		}
+		return attributeCondition{path: path, missing: true}, nil
+	}
+
+	if path, value, found := strings.Cut(raw, "!="); found && path != "" {
+		return attributeCondition{path: path, value: value, negate: true}, nil
+	}
+
+	if path, value, found := strings.Cut(raw, "="); found && path != "" {
+		return attributeCondition{path: path, value: value}, nil
+	}
+
+	return attributeCondition{}, fmt.Errorf("invalid attribute condition %q, expected PATH=VALUE, PATH!=VALUE or !PATH", raw)
+}
+
+func (c attributeCondition) matches(values any) bool {
+	value, found := lookupAttribute(values, c.path)
+
+	if c.missing {
+		return !found || isEmptyAttribute(value)
+	}
+
+	equal := found && attributeString(value) == c.value
+	if c.negate {
+		return !equal
+	}
+
+	return equal
+}
+
+func lookupAttribute(values any, path string) (any, bool) {
+	current := values
+
+	for key := range strings.SplitSeq(path, ".") {
+		switch node := current.(type) {
+		case map[string]any:
+			next, found := node[key]
+			if !found {
+				return nil, false
+			}
+			current = next
+		case []any:
+			idx, err := strconv.Atoi(key)
+			if err != nil || idx < 0 || idx >= len(node) {
+				return nil, false
+			}
+			current = node[idx]
+		default:
+			return nil, false
+		}
+	}
+
+	return current, true
+}
+
+func isEmptyAttribute(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []any:
+		return len(v) == 0
+	case map[string]any:
+		return len(v) == 0
+	}
+
+	return false
+}
+
+// attributeString formats scalars the way they are written on the command
+// line, and everything else as compact JSON.
+func attributeString(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+
+	data, _ := json.Marshal(value)
+	return string(data)
+}
+
+// resourceSearchResult is a single resource in the exported inventory.
+type resourceSearchResult struct {
+	StackID   string `json:"stackId"`
+	StackName string `json:"stackName"`
+	Space     string `json:"space"`
+	Address   string `json:"address"`
+	Type      string `json:"type"`
+	Vendor    string `json:"vendor"`
+	Provider  string `json:"provider,omitempty"`
+	// Values are the parsed attributes of the resource.
+	Values any `json:"values,omitempty"`
+}
+
+type resourceSearch struct {
+	filter     resourceFilter
+	provider   string
+	conditions []attributeCondition
+}
+
+func resourcesSearch(ctx context.Context, cliCmd *cli.Command) error {
+	outputFormat, formatter, err := cmd.GetOutputFormat(cliCmd)
+	if err != nil {
+		return err
+	}
+
+	filter, err := resourceFilterFromFlags(cliCmd)
+	if err != nil {
+		return err
+	}
+
+	search := resourceSearch{filter: filter, provider: cliCmd.String(flagResourceProvider.Name)}
+	for _, raw := range cliCmd.StringSlice(flagResourceAttribute.Name) {
+		condition, err := parseAttributeCondition(raw)
+		if err != nil {
+			return err
+		}
+		search.conditions = append(search.conditions, condition)
+	}
+
+	stacks, err := queryAllStacksResources(ctx)
+	if err != nil {
+		return err
+	}
+
+	results := search.run(stacks)
+
+	switch outputFormat {
+	case cmd.OutputFormatTable:
+		tableData := [][]string{{"Stack", "Space", "Address", "Type", "Provider"}}
+		for _, r := range results {
+			tableData = append(tableData, []string{r.StackName, r.Space, r.Address, r.Type, r.Provider})
+		}
+
+		return cmd.OutputTable(tableData, true)
+	case cmd.OutputFormatCSV:
+		return writeSearchCSV(os.Stdout, results)
+	case cmd.OutputFormatMarkdown:
+		return cmd.WriteMarkdownTable(os.Stdout, searchResultRows(results))
+	default:
+		return cmd.Output(formatter, results)
+	}
+}
+
+// run returns the resources of all stacks matching the search. Only resources
+// are considered, not modules, outputs or other entities.
+func (s resourceSearch) run(stacks []stackWithResources) []resourceSearchResult {
+	results := []resourceSearchResult{}
+
+	for _, stack := range stacks {
+		for _, e := range stack.Entities {
+			details := e.vendorDetails()
+			if details.Kind != "resource" || !s.filter.matches(e) {
+				continue
+			}
+
+			if s.provider != "" && !strings.Contains(details.Provider, s.provider) {
+				continue
+			}
+
+			var values any
+			if details.Data != "" {
+				if err := json.Unmarshal([]byte(details.Data), &values); err != nil {
+					values = details.Data
+				}
+			}
+
+			if !s.matchesAttributes(values) {
+				continue
+			}
+
+			results = append(results, resourceSearchResult{
+				StackID:   stack.ID,
+				StackName: stack.Name,
+				Space:     stack.Space,
+				Address:   e.Address,
+				Type:      e.Type,
+				Vendor:    details.Vendor,
+				Provider:  details.Provider,
+				Values:    values,
+			})
+		}
+	}
+
+	return results
+}
+
+func (s resourceSearch) matchesAttributes(values any) bool {
+	for _, condition := range s.conditions {
+		if !condition.matches(values) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// searchResultRows returns the rows of the CSV and Markdown outputs. Values
+// are kept in a single column as compact JSON, since the attributes differ
+// from one resource type to another.
+func searchResultRows(results []resourceSearchResult) [][]string {
+	rows := [][]string{{"Stack ID", "Stack", "Space", "Address", "Type", "Vendor", "Provider", "Values"}}
+
+	for _, r := range results {
+		var values string
+		if r.Values != nil {
+			values = attributeString(r.Values)
+		}
+
+		rows = append(rows, []string{r.StackID, r.StackName, r.Space, r.Address, r.Type, r.Vendor, r.Provider, values})
+	}
+
+	return rows
+}
+
+func writeSearchCSV(w io.Writer, results []resourceSearchResult) error {
+	writer := csv.NewWriter(w)
+
+	if err := writer.WriteAll(searchResultRows(results)); err != nil {
+		return err
+	}
+
+	return writer.Error()
+}
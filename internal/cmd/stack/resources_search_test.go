@@ -0,0 +1,90 @@
+package stack
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAttributeCondition(t *testing.T) {
+	cases := map[string]attributeCondition{
+		"tags.env=prod":    {path: "tags.env", value: "prod"},
+		"tags.env!=prod":   {path: "tags.env", value: "prod", negate: true},
+		"!logging":         {path: "logging", missing: true},
+		"description=a=b":  {path: "description", value: "a=b"},
+		"acl=":             {path: "acl"},
+		"versioning.0.x=1": {path: "versioning.0.x", value: "1"},
+	}
+
+	for raw, expected := range cases {
+		condition, err := parseAttributeCondition(raw)
+		require.NoError(t, err, raw)
+		assert.Equal(t, expected, condition, raw)
+	}
+
+	for _, raw := range []string{"tags", "=prod", "!"} {
+		_, err := parseAttributeCondition(raw)
+		assert.Error(t, err, raw)
+	}
+}
+
+func TestResourceSearch(t *testing.T) {
+	encrypted := terraformResource("aws_s3_bucket.logs", "aws_s3_bucket", "", false)
+	encrypted.Vendor.EntityVendorTerraform.Terraform.TerraformResource.Values = `{"bucket":"logs","tags":{"env":"prod"},"server_side_encryption_configuration":[{"rule":{"sse_algorithm":"aws:kms"}}]}`
+
+	plain := terraformResource("aws_s3_bucket.data", "aws_s3_bucket", "", false)
+	plain.Vendor.EntityVendorTerraform.Terraform.TerraformResource.Values = `{"bucket":"data","tags":{"env":"dev"},"server_side_encryption_configuration":[],"size":1000000,"ratio":0.25}`
+
+	instance := terraformResource("aws_instance.web", "aws_instance", "", false)
+	instance.Vendor.EntityVendorTerraform.Terraform.TerraformResource.Provider = "registry.terraform.io/hashicorp/google"
+
+	var module managedEntity
+	module.Address = "module.vpc"
+	module.Vendor.EntityVendorTerraform.Terraform = &terraformEntity{}
+
+	stacks := []stackWithResources{
+		{ID: "storage", Name: "Storage", Space: "root", Entities: []managedEntity{encrypted, plain, module}},
+		{ID: "compute", Name: "Compute", Space: "root", Entities: []managedEntity{instance}},
+	}
+
+	addresses := func(search resourceSearch) []string {
+		var out []string
+		for _, r := range search.run(stacks) {
+			out = append(out, r.Address)
+		}
+		return out
+	}
+
+	assert.Equal(t, []string{"aws_s3_bucket.logs", "aws_s3_bucket.data", "aws_instance.web"}, addresses(resourceSearch{}))
+	assert.Equal(t, []string{"aws_instance.web"}, addresses(resourceSearch{provider: "hashicorp/google"}))
+	assert.Equal(t, []string{"aws_s3_bucket.data"}, addresses(resourceSearch{
+		filter:     resourceFilter{types: []string{"aws_s3_bucket"}},
+		conditions: []attributeCondition{{path: "server_side_encryption_configuration", missing: true}},
+	}))
+	assert.Equal(t, []string{"aws_s3_bucket.logs"}, addresses(resourceSearch{
+		conditions: []attributeCondition{{path: "server_side_encryption_configuration.0.rule.sse_algorithm", value: "aws:kms"}},
+	}))
+	assert.Equal(t, []string{"aws_s3_bucket.data", "aws_instance.web"}, addresses(resourceSearch{
+		conditions: []attributeCondition{{path: "tags.env", value: "prod", negate: true}},
+	}))
+
+	assert.Equal(t, []string{"aws_s3_bucket.data"}, addresses(resourceSearch{
+		conditions: []attributeCondition{{path: "size", value: "1000000"}, {path: "ratio", value: "0.25"}},
+	}))
+
+	t.Run("structured output", func(t *testing.T) {
+		data, err := json.Marshal(resourceSearch{provider: "hashicorp/google"}.run(stacks))
+		require.NoError(t, err)
+		assert.JSONEq(t, `[{"stackId":"compute","stackName":"Compute","space":"root","address":"aws_instance.web","type":"aws_instance","vendor":"Terraform","provider":"registry.terraform.io/hashicorp/google","values":{"id":"i-123"}}]`, string(data))
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		var b strings.Builder
+		require.NoError(t, writeSearchCSV(&b, resourceSearch{provider: "hashicorp/google"}.run(stacks)))
+		assert.Equal(t, "Stack ID,Stack,Space,Address,Type,Vendor,Provider,Values\n"+
+			`compute,Compute,root,aws_instance.web,aws_instance,Terraform,registry.terraform.io/hashicorp/google,"{""id"":""i-123""}"`+"\n", b.String())
+	})
+}
@@ -0,0 +1,393 @@
+package stack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/pterm/pterm"
+	"github.com/urfave/cli/v3"
+
+	"github.com/spacelift-io/spacectl/internal/cmd"
+)
+
+// resourcesFormatTree is an output format specific to the resources list
+// command, on top of the shared ones.
+const resourcesFormatTree = "tree"
+
+// resourceFilter selects the managed entities to display.
+type resourceFilter struct {
+	types       []string
+	addressGlob string
+	tainted     bool
+	drifted     bool
+}
+
+func resourceFilterFromFlags(cliCmd *cli.Command) (resourceFilter, error) {
+	filter := resourceFilter{
+		types:       cliCmd.StringSlice(flagResourceType.Name),
+		addressGlob: cliCmd.String(flagResourceAddressGlob.Name),
+		tainted:     cliCmd.Bool(flagResourceTainted.Name),
+		drifted:     cliCmd.Bool(flagResourceDrifted.Name),
+	}
+
+	if filter.addressGlob != "" {
+		if _, err := path.Match(filter.addressGlob, ""); err != nil {
+			return resourceFilter{}, fmt.Errorf("invalid address glob %q: %w", filter.addressGlob, err)
+		}
+	}
+
+	return filter, nil
+}
+
+func (f resourceFilter) isEmpty() bool {
+	return len(f.types) == 0 && f.addressGlob == "" && !f.tainted && !f.drifted
+}
+
+func (f resourceFilter) matches(e managedEntity) bool {
+	if len(f.types) > 0 && !slices.Contains(f.types, e.Type) {
+		return false
+	}
+
+	if f.addressGlob != "" {
+		if matched, _ := path.Match(f.addressGlob, e.Address); !matched {
+			return false
+		}
+	}
+
+	if f.tainted && !e.vendorDetails().Tainted {
+		return false
+	}
+
+	if f.drifted && e.Drifted == nil {
+		return false
+	}
+
+	return true
+}
+
+// apply returns the stacks with only the matching entities. Stacks left
+// without entities are dropped, unless the filter is empty.
+func (f resourceFilter) apply(stacks []stackWithResources) []stackWithResources {
+	if f.isEmpty() {
+		return stacks
+	}
+
+	out := make([]stackWithResources, 0, len(stacks))
+	for _, s := range stacks {
+		filtered := s
+		filtered.Entities = slices.DeleteFunc(slices.Clone(s.Entities), func(e managedEntity) bool { return !f.matches(e) })
+
+		if len(filtered.Entities) > 0 {
+			out = append(out, filtered)
+		}
+	}
+
+	return out
+}
+
+// entityDetails is the populated part of the entity vendor union.
+type entityDetails struct {
+	Vendor   string
+	Kind     string
+	Provider string
+	Tainted  bool
+	// Data is the raw attributes of the entity, usually a JSON document.
+	Data string
+}
+
+func (e managedEntity) vendorDetails() entityDetails {
+	v := e.Vendor
+
+	switch {
+	case v.EntityVendorTerraform.Terraform != nil:
+		tf := v.EntityVendorTerraform.Terraform
+		switch {
+		case tf.TerraformResource.Address != "":
+			return entityDetails{Vendor: "Terraform", Kind: "resource", Provider: tf.TerraformResource.Provider, Tainted: tf.TerraformResource.Tainted, Data: tf.TerraformResource.Values}
+		case tf.TerraformOutput.Hash != "":
+			return entityDetails{Vendor: "Terraform", Kind: "output", Data: outputData(tf.TerraformOutput.Sensitive, tf.TerraformOutput.Value)}
+		default:
+			return entityDetails{Vendor: "Terraform", Kind: "module"}
+		}
+	case v.EntityVendorPulumi.Pulumi != nil:
+		p := v.EntityVendorPulumi.Pulumi
+		switch {
+		case p.PulumiResource.Urn != "":
+			return entityDetails{Vendor: "Pulumi", Kind: "resource", Provider: p.PulumiResource.Provider, Data: p.PulumiResource.Outputs}
+		case p.PulumiOutput.Hash != "":
+			return entityDetails{Vendor: "Pulumi", Kind: "output", Data: outputData(p.PulumiOutput.Sensitive, p.PulumiOutput.Value)}
+		default:
+			return entityDetails{Vendor: "Pulumi", Kind: "stack"}
+		}
+	case v.EntityVendorCloudFormation.CloudFormation != nil:
+		cf := v.EntityVendorCloudFormation.CloudFormation
+		if cf.CloudFormationResource.LogicalResourceID != "" {
+			return entityDetails{Vendor: "CloudFormation", Kind: "resource", Data: cf.CloudFormationResource.Template}
+		}
+		return entityDetails{Vendor: "CloudFormation", Kind: "output", Data: cf.CloudFormationOutput.Value}
+	case v.EntityVendorKubernetes.Kubernetes != nil:
+		k := v.EntityVendorKubernetes.Kubernetes
+		if k.KubernetesResource.Data != "" {
+			return entityDetails{Vendor: "Kubernetes", Kind: "resource", Data: k.KubernetesResource.Data}
+		}
+		return entityDetails{Vendor: "Kubernetes", Kind: "root"}
+	case v.EntityVendorAnsible.Ansible != nil:
+		return entityDetails{Vendor: "Ansible", Kind: "resource", Data: v.EntityVendorAnsible.Ansible.AnsibleResource.Data}
+	}
+
+	return entityDetails{}
+}
+
+func outputData(sensitive bool, value *string) string {
+	if sensitive {
+		return `"<sensitive>"`
+	}
+
+	if value == nil {
+		return ""
+	}
+
+	return *value
+}
+
+func outputResourcesTable(stacks []stackWithResources) error {
+	columns := []string{"Address", "Type", "Vendor", "Kind", "Provider", "Tainted", "Drifted At"}
+	if len(stacks) > 1 {
+		columns = append([]string{"Stack"}, columns...)
+	}
+
+	tableData := [][]string{columns}
+	for _, s := range stacks {
+		for _, e := range s.Entities {
+			details := e.vendorDetails()
+
+			var driftedAt string
+			if e.Drifted != nil {
+				driftedAt = time.Unix(*e.Drifted, 0).UTC().Format(time.RFC3339)
+			}
+
+			row := []string{e.Address, e.Type, details.Vendor, details.Kind, details.Provider, fmt.Sprint(details.Tainted), driftedAt}
+			if len(stacks) > 1 {
+				row = append([]string{s.Name}, row...)
+			}
+
+			tableData = append(tableData, row)
+		}
+	}
+
+	return cmd.OutputTable(tableData, true)
+}
+
+func outputResourcesTree(stacks []stackWithResources, filter resourceFilter) error {
+	roots := make([]pterm.TreeNode, 0, len(stacks))
+	for _, s := range stacks {
+		children := buildResourceTree(s.Entities, filter)
+		if len(children) == 0 && !filter.isEmpty() {
+			continue
+		}
+
+		roots = append(roots, pterm.TreeNode{Text: s.Name, Children: children})
+	}
+
+	root := pterm.TreeNode{Children: roots}
+	if len(roots) == 1 {
+		root = roots[0]
+	}
+
+	return pterm.DefaultTree.WithRoot(root).Render()
+}
+
+// buildResourceTree builds the module hierarchy of the entities from their
+// parents. Entities not matching the filter are kept only when one of their
+// descendants matches it.
+func buildResourceTree(entities []managedEntity, filter resourceFilter) []pterm.TreeNode {
+	ids := make(map[string]bool, len(entities))
+	for _, e := range entities {
+		ids[e.ID] = true
+	}
+
+	children := make(map[string][]managedEntity)
+	for _, e := range entities {
+		parent := e.Parent
+		if !ids[parent] {
+			parent = ""
+		}
+		children[parent] = append(children[parent], e)
+	}
+
+	var build func(parent string) []pterm.TreeNode
+	build = func(parent string) []pterm.TreeNode {
+		var nodes []pterm.TreeNode
+		for _, e := range children[parent] {
+			node := pterm.TreeNode{Text: resourceTreeLabel(e), Children: build(e.ID)}
+			if len(node.Children) > 0 || filter.matches(e) {
+				nodes = append(nodes, node)
+			}
+		}
+		return nodes
+	}
+
+	return build("")
+}
+
+func resourceTreeLabel(e managedEntity) string {
+	label := e.Address
+	if label == "" {
+		label = e.Name
+	}
+
+	details := e.vendorDetails()
+	if details.Kind == "resource" && e.Type != "" {
+		label = fmt.Sprintf("%s (%s)", label, e.Type)
+	}
+
+	if details.Tainted {
+		label += " [tainted]"
+	}
+
+	if e.Drifted != nil {
+		label += " [drifted]"
+	}
+
+	return label
+}
+
+func resourcesShow(ctx context.Context, cliCmd *cli.Command) error {
+	if nArgs := cliCmd.NArg(); nArgs != 1 {
+		return fmt.Errorf("expecting the resource address as the only argument, got %d instead", nArgs)
+	}
+	address := cliCmd.Args().Get(0)
+
+	outputFormat, formatter, err := cmd.GetOutputFormat(cliCmd)
+	if err != nil {
+		return err
+	}
+
+	stackID, err := getStackID(ctx, cliCmd)
+	if err != nil {
+		return err
+	}
+
+	stack, err := queryStackResources(ctx, stackID)
+	if err != nil {
+		return err
+	}
+
+	idx := slices.IndexFunc(stack.Entities, func(e managedEntity) bool { return e.Address == address })
+	if idx < 0 {
+		return fmt.Errorf("resource %q not found in stack %q", address, stackID)
+	}
+
+	entity := stack.Entities[idx]
+	details := entity.vendorDetails()
+
+	switch outputFormat {
+	case cmd.OutputFormatTable:
+		return showResourceTable(entity, details)
+	default:
+		return showResourceJSON(formatter, entity, details)
+	}
+}
+
+func showResourceTable(e managedEntity, details entityDetails) error {
+	pterm.DefaultSection.WithLevel(1).Print(e.Address)
+
+	tableData := [][]string{
+		{"ID", e.ID},
+		{"Type", e.Type},
+		{"Vendor", details.Vendor},
+		{"Kind", details.Kind},
+		{"Parent", e.Parent},
+	}
+
+	if details.Provider != "" {
+		tableData = append(tableData, []string{"Provider", details.Provider})
+	}
+
+	if details.Tainted {
+		tableData = append(tableData, []string{"Tainted", "true"})
+	}
+
+	if e.Drifted != nil {
+		tableData = append(tableData, []string{"Drifted At", time.Unix(*e.Drifted, 0).UTC().Format(time.RFC3339)})
+	}
+
+	if e.Creator != nil {
+		tableData = append(tableData, []string{"Created By Run", e.Creator.ID})
+	}
+
+	if e.Updater != nil {
+		tableData = append(tableData, []string{"Updated By Run", e.Updater.ID})
+	}
+
+	if err := cmd.OutputTable(tableData, false); err != nil {
+		return err
+	}
+
+	if details.Data == "" {
+		return nil
+	}
+
+	pterm.DefaultSection.WithLevel(2).Println("Values")
+	fmt.Println(prettyJSON(details.Data))
+
+	return nil
+}
+
+func showResourceJSON(formatter cmd.OutputFormatter, e managedEntity, details entityDetails) error {
+	var values json.RawMessage
+	if json.Valid([]byte(details.Data)) {
+		values = json.RawMessage(details.Data)
+	} else if details.Data != "" {
+		raw, err := json.Marshal(details.Data)
+		if err != nil {
+			return errors.Wrap(err, "failed to encode resource values")
+		}
+		values = raw
+	}
+
+	return cmd.Output(formatter, struct {
+		ID       string          `json:"id"`
+		Address  string          `json:"address"`
+		Type     string          `json:"type"`
+		Vendor   string          `json:"vendor"`
+		Kind     string          `json:"kind"`
+		Parent   string          `json:"parent,omitempty"`
+		Provider string          `json:"provider,omitempty"`
+		Tainted  bool            `json:"tainted,omitempty"`
+		Drifted  *int64          `json:"drifted,omitempty"`
+		Creator  *run            `json:"creator,omitempty"`
+		Updater  *run            `json:"updater,omitempty"`
+		Values   json.RawMessage `json:"values,omitempty"`
+	}{
+		ID:       e.ID,
+		Address:  e.Address,
+		Type:     e.Type,
+		Vendor:   details.Vendor,
+		Kind:     details.Kind,
+		Parent:   e.Parent,
+		Provider: details.Provider,
+		Tainted:  details.Tainted,
+		Drifted:  e.Drifted,
+		Creator:  e.Creator,
+		Updater:  e.Updater,
+		Values:   values,
+	})
+}
+
+// prettyJSON indents the data if it is valid JSON, and returns it as-is otherwise.
+func prettyJSON(data string) string {
+	var out bytes.Buffer
+	if err := json.Indent(&out, []byte(data), "", "  "); err != nil {
+		return strings.TrimSpace(data)
+	}
+
+	return out.String()
+}
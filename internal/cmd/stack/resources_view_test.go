@@ -0,0 +1,90 @@
+package stack
+
+import (
+	"testing"
+
+	"github.com/pterm/pterm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResourceFilter(t *testing.T) {
+	bucket := terraformResource("module.logs.aws_s3_bucket.this", "aws_s3_bucket", "module.logs", false)
+	tainted := terraformResource("aws_instance.web", "aws_instance", "", true)
+	drifted := terraformResource("module.logs.aws_s3_bucket_policy.this", "aws_s3_bucket_policy", "module.logs", false)
+	drifted.Drifted = new(int64(1700000000))
+
+	entities := []managedEntity{bucket, tainted, drifted}
+
+	cases := []struct {
+		name     string
+		filter   resourceFilter
+		expected []string
+	}{
+		{"empty", resourceFilter{}, []string{bucket.Address, tainted.Address, drifted.Address}},
+		{"type", resourceFilter{types: []string{"aws_instance", "aws_s3_bucket"}}, []string{bucket.Address, tainted.Address}},
+		{"address glob", resourceFilter{addressGlob: "module.logs.*"}, []string{bucket.Address, drifted.Address}},
+		{"tainted", resourceFilter{tainted: true}, []string{tainted.Address}},
+		{"drifted", resourceFilter{drifted: true}, []string{drifted.Address}},
+		{"combined", resourceFilter{addressGlob: "module.logs.*", types: []string{"aws_s3_bucket"}}, []string{bucket.Address}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var matched []string
+			for _, e := range entities {
+				if c.filter.matches(e) {
+					matched = append(matched, e.Address)
+				}
+			}
+			assert.Equal(t, c.expected, matched)
+		})
+	}
+}
+
+func TestVendorDetails(t *testing.T) {
+	resource := terraformResource("aws_instance.web", "aws_instance", "", true)
+	assert.Equal(t, entityDetails{Vendor: "Terraform", Kind: "resource", Provider: "registry.terraform.io/hashicorp/aws", Tainted: true, Data: `{"id":"i-123"}`}, resource.vendorDetails())
+
+	var module managedEntity
+	module.Vendor.EntityVendorTerraform.Terraform = &terraformEntity{}
+	assert.Equal(t, entityDetails{Vendor: "Terraform", Kind: "module"}, module.vendorDetails())
+
+	var output managedEntity
+	output.Vendor.EntityVendorPulumi.Pulumi = &pulumiEntity{}
+	output.Vendor.EntityVendorPulumi.Pulumi.PulumiOutput.Hash = "abc"
+	output.Vendor.EntityVendorPulumi.Pulumi.PulumiOutput.Sensitive = true
+	assert.Equal(t, entityDetails{Vendor: "Pulumi", Kind: "output", Data: `"<sensitive>"`}, output.vendorDetails())
+
+	assert.Equal(t, entityDetails{}, managedEntity{}.vendorDetails())
+}
+
+func TestBuildResourceTree(t *testing.T) {
+	var module managedEntity
+	module.ID, module.Address, module.Parent = "module.logs", "module.logs", "root"
+	module.Vendor.EntityVendorTerraform.Terraform = &terraformEntity{}
+
+	entities := []managedEntity{
+		module,
+		terraformResource("module.logs.aws_s3_bucket.this", "aws_s3_bucket", "module.logs", false),
+		terraformResource("aws_instance.web", "aws_instance", "root", true),
+	}
+
+	assert.Equal(t, []pterm.TreeNode{
+		{Text: "module.logs", Children: []pterm.TreeNode{{Text: "module.logs.aws_s3_bucket.this (aws_s3_bucket)"}}},
+		{Text: "aws_instance.web (aws_instance) [tainted]"},
+	}, buildResourceTree(entities, resourceFilter{}))
+
+	assert.Equal(t, []pterm.TreeNode{
+		{Text: "module.logs", Children: []pterm.TreeNode{{Text: "module.logs.aws_s3_bucket.this (aws_s3_bucket)"}}},
+	}, buildResourceTree(entities, resourceFilter{types: []string{"aws_s3_bucket"}}))
+}
+
+func terraformResource(address, resourceType, parent string, tainted bool) managedEntity {
+	e := managedEntity{ID: address, Address: address, Type: resourceType, Parent: parent}
+	e.Vendor.EntityVendorTerraform.Terraform = &terraformEntity{}
+	e.Vendor.EntityVendorTerraform.Terraform.TerraformResource.Address = address
+	e.Vendor.EntityVendorTerraform.Terraform.TerraformResource.Provider = "registry.terraform.io/hashicorp/aws"
+	e.Vendor.EntityVendorTerraform.Terraform.TerraformResource.Tainted = tainted
+	e.Vendor.EntityVendorTerraform.Terraform.TerraformResource.Values = `{"id":"i-123"}`
+	return e
+}
@@ -0,0 +1,181 @@
+package stack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+	"github.com/pterm/pterm"
+	"github.com/shurcooL/graphql"
+	"github.com/urfave/cli/v3"
+
+	"github.com/spacelift-io/spacectl/internal/cmd"
+	"github.com/spacelift-io/spacectl/internal/cmd/authenticated"
+)
+
+type runComment struct {
+	Body      string `graphql:"body" json:"body"`
+	CreatedAt int    `graphql:"createdAt" json:"createdAt"`
+	Username  string `graphql:"username" json:"username"`
+}
+
+func runCommentAdd(ctx context.Context, cliCmd *cli.Command) error {
+	if nArgs := cliCmd.NArg(); nArgs != 0 {
+		return fmt.Errorf("expected zero arguments but got %d", nArgs)
+	}
+
+	stackID, err := getStackID(ctx, cliCmd)
+	if err != nil {
+		return err
+	}
+	runID := cliCmd.String(flagRequiredRun.Name)
+
+	body, err := readCommentBody(cliCmd, os.Stdin)
+	if err != nil {
+		return err
+	}
+
+	var mutation struct {
+		RunComment struct {
+			CreatedAt int `graphql:"createdAt"`
+		} `graphql:"runComment(stack: $stack, run: $run, body: $body)"`
+	}
+
+	variables := map[string]any{
+		"stack": graphql.ID(stackID),
+		"run":   graphql.ID(runID),
+		"body":  graphql.String(body),
+	}
+
+	if err := authenticated.Client(ctx).Mutate(ctx, &mutation, variables); err != nil {
+		return err
+	}
+
+	fmt.Printf("Comment added to run %s\n", runID)
+	fmt.Println("The run can be visited at", authenticated.Client(ctx).URL("/stack/%s/run/%s", stackID, runID))
+
+	return nil
+}
+
+// readCommentBody reads the markdown body of a comment from the --body flag,
+// the file given with --body-file ("-" meaning stdin), or stdin when it is piped.
+func readCommentBody(cliCmd *cli.Command, stdin *os.File) (string, error) {
+	bodySet := cliCmd.IsSet(flagCommentBody.Name)
+	fileSet := cliCmd.IsSet(flagCommentBodyFile.Name)
+
+	var body string
+	switch {
+	case bodySet && fileSet:
+		return "", fmt.Errorf("only one of --%s and --%s can be used", flagCommentBody.Name, flagCommentBodyFile.Name)
+	case bodySet:
+		body = cliCmd.String(flagCommentBody.Name)
+	case fileSet && cliCmd.String(flagCommentBodyFile.Name) == "-":
+		data, err := io.ReadAll(stdin)
+		if err != nil {
+			return "", fmt.Errorf("couldn't read from STDIN: %w", err)
+		}
+		body = string(data)
+	case fileSet:
+		path := cliCmd.String(flagCommentBodyFile.Name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("couldn't read file from %s: %w", path, err)
+		}
+		body = string(data)
+	case !isatty.IsTerminal(stdin.Fd()):
+		data, err := io.ReadAll(stdin)
+		if err != nil {
+			return "", fmt.Errorf("couldn't read from STDIN: %w", err)
+		}
+		body = string(data)
+	}
+
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return "", errors.New("comment body required: use --body, --body-file or pipe it via STDIN")
+	}
+
+	return body, nil
+}
+
+func runCommentList(ctx context.Context, cliCmd *cli.Command) error {
+	if nArgs := cliCmd.NArg(); nArgs != 0 {
+		return fmt.Errorf("expected zero arguments but got %d", nArgs)
+	}
+
+	outputFormat, formatter, err := cmd.GetOutputFormat(cliCmd)
+	if err != nil {
+		return err
+	}
+
+	stackID, err := getStackID(ctx, cliCmd)
+	if err != nil {
+		return err
+	}
+	runID := cliCmd.String(flagRequiredRun.Name)
+
+	comments, err := getRunComments(ctx, stackID, runID)
+	if err != nil {
+		return err
+	}
+
+	switch outputFormat {
+	case cmd.OutputFormatTable:
+		printCommentThread(comments)
+		return nil
+	default:
+		return cmd.Output(formatter, comments)
+	}
+}
+
+// getRunComments returns the comments of a run, oldest first.
+func getRunComments(ctx context.Context, stackID, runID string) ([]runComment, error) {
+	var query struct {
+		Stack *struct {
+			Run *struct {
+				Comments []runComment `graphql:"comments"`
+			} `graphql:"run(id: $run)"`
+		} `graphql:"stack(id: $stack)"`
+	}
+
+	variables := map[string]any{
+		"stack": graphql.ID(stackID),
+		"run":   graphql.ID(runID),
+	}
+
+	if err := authenticated.Client(ctx).Query(ctx, &query, variables); err != nil {
+		return nil, fmt.Errorf("failed to query run comments: %w", err)
+	}
+
+	if query.Stack == nil {
+		return nil, fmt.Errorf("stack %q not found", stackID)
+	}
+
+	if query.Stack.Run == nil {
+		return nil, fmt.Errorf("run %q not found", runID)
+	}
+
+	comments := slices.Clone(query.Stack.Run.Comments)
+	slices.SortStableFunc(comments, func(a, b runComment) int {
+		return a.CreatedAt - b.CreatedAt
+	})
+
+	return comments, nil
+}
+
+func printCommentThread(comments []runComment) {
+	if len(comments) == 0 {
+		fmt.Println("The run has no comments")
+		return
+	}
+
+	for _, comment := range comments {
+		pterm.DefaultSection.WithLevel(2).Printfln("%s commented on %s", comment.Username, cmd.HumanizeUnixSeconds(comment.CreatedAt))
+		fmt.Println(comment.Body)
+	}
+}
@@ -0,0 +1,71 @@
+package stack
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+func TestReadCommentBody(t *testing.T) {
+	dir := t.TempDir()
+
+	bodyFile := filepath.Join(dir, "comment.md")
+	require.NoError(t, os.WriteFile(bodyFile, []byte("## From file\n\nSee JIRA-123\n"), 0o600))
+
+	stdinFile := filepath.Join(dir, "stdin")
+	require.NoError(t, os.WriteFile(stdinFile, []byte("  piped **body**\n"), 0o600))
+
+	emptyFile := filepath.Join(dir, "empty")
+	require.NoError(t, os.WriteFile(emptyFile, nil, 0o600))
+
+	cases := []struct {
+		name     string
+		args     []string
+		stdin    string
+		expected string
+		err      string
+	}{
+		{name: "flag", args: []string{"--body", "LGTM"}, stdin: emptyFile, expected: "LGTM"},
+		{name: "file", args: []string{"--body-file", bodyFile}, stdin: emptyFile, expected: "## From file\n\nSee JIRA-123"},
+		{name: "explicit stdin", args: []string{"--body-file", "-"}, stdin: stdinFile, expected: "piped **body**"},
+		{name: "piped stdin", stdin: stdinFile, expected: "piped **body**"},
+		{name: "both flags", args: []string{"--body", "x", "--body-file", bodyFile}, stdin: emptyFile, err: "only one of --body and --body-file can be used"},
+		{name: "empty", stdin: emptyFile, err: "comment body required: use --body, --body-file or pipe it via STDIN"},
+		{name: "missing file", args: []string{"--body-file", filepath.Join(dir, "missing.md")}, stdin: emptyFile, err: "couldn't read file from"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			stdin, err := os.Open(c.stdin)
+			require.NoError(t, err)
+			defer stdin.Close()
+
+			// Copy the flags, as they keep their state between runs.
+			bodyFlag, bodyFileFlag := *flagCommentBody, *flagCommentBodyFile
+
+			var body string
+			command := &cli.Command{
+				Flags: []cli.Flag{&bodyFlag, &bodyFileFlag},
+				Action: func(_ context.Context, cliCmd *cli.Command) error {
+					body, err = readCommentBody(cliCmd, stdin)
+					return nil
+				},
+			}
+			require.NoError(t, command.Run(t.Context(), append([]string{"add"}, c.args...)))
+
+			if c.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), c.err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, body)
+		})
+	}
+}
@@ -19,6 +19,16 @@ func runConfirm() cli.ActionFunc {
 			return err
 		}
 
+		guardrails, err := confirmGuardrailsFromFlags(cliCmd)
+		if err != nil {
+			return err
+		}
+
+		runID := cliCmd.String(flagRequiredRun.Name)
+		if err := enforceConfirmGuardrails(ctx, guardrails, stackID, runID); err != nil {
+			return err
+		}
+
 		var mutation struct {
 			RunConfirm struct {
 				ID string `graphql:"id"`
@@ -27,7 +37,7 @@ func runConfirm() cli.ActionFunc {
 
 		variables := map[string]any{
 			"stack": graphql.ID(stackID),
-			"run":   graphql.ID(cliCmd.String(flagRequiredRun.Name)),
+			"run":   graphql.ID(runID),
 		}
 
 		var requestOpts []graphql.RequestOption
@@ -35,13 +45,13 @@ func runConfirm() cli.ActionFunc {
 			requestOpts = append(requestOpts, graphql.WithHeader(internal.UserProvidedRunMetadataHeader, cliCmd.String(flagRunMetadata.Name)))
 		}
 
-		if err := authenticated.Client().Mutate(ctx, &mutation, variables, requestOpts...); err != nil {
+		if err := authenticated.Client(ctx).Mutate(ctx, &mutation, variables, requestOpts...); err != nil {
 			return err
 		}
 
 		fmt.Println("You have successfully confirmed a deployment")
 
-		fmt.Println("The live run can be visited at", authenticated.Client().URL(
+		fmt.Println("The live run can be visited at", authenticated.Client(ctx).URL(
 			"/stack/%s/run/%s",
 			stackID,
 			mutation.RunConfirm.ID,
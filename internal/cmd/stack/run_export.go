@@ -0,0 +1,279 @@
+package stack
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/shurcooL/graphql"
+	"github.com/urfave/cli/v3"
+
+	"github.com/spacelift-io/spacectl/client/structs"
+	"github.com/spacelift-io/spacectl/internal/cmd/authenticated"
+	"github.com/spacelift-io/spacectl/internal/logs"
+)
+
+const runExportIndexFile = "index.json"
+
+// runExportIndex describes the content of an exported run bundle.
+type runExportIndex struct {
+	StackID    string            `json:"stackId"`
+	RunID      string            `json:"runId"`
+	URL        string            `json:"url"`
+	ExportedAt string            `json:"exportedAt"`
+	Files      []runExportedFile `json:"files"`
+}
+
+type runExportedFile struct {
+	Path        string `json:"path"`
+	Description string `json:"description"`
+	Size        int    `json:"size"`
+	SHA256      string `json:"sha256"`
+}
+
+// runExportFile is a file of the bundle, before it is written.
+type runExportFile struct {
+	path        string
+	description string
+	data        []byte
+}
+
+type exportedRun struct {
+	ID     string `graphql:"id" json:"id"`
+	Branch string `graphql:"branch" json:"branch"`
+	Commit struct {
+		AuthorLogin string `graphql:"authorLogin" json:"authorLogin"`
+		AuthorName  string `graphql:"authorName" json:"authorName"`
+		Hash        string `graphql:"hash" json:"hash"`
+		Message     string `graphql:"message" json:"message"`
+		URL         string `graphql:"url" json:"url"`
+	} `graphql:"commit" json:"commit"`
+	CreatedAt      int                          `graphql:"createdAt" json:"createdAt"`
+	Delta          *runDelta                    `graphql:"delta" json:"delta"`
+	DriftDetection bool                         `graphql:"driftDetection" json:"driftDetection"`
+	NeedsApproval  bool                         `graphql:"needsApproval" json:"needsApproval"`
+	State          string                       `graphql:"state" json:"state"`
+	Title          string                       `graphql:"title" json:"title"`
+	TriggeredBy    string                       `graphql:"triggeredBy" json:"triggeredBy"`
+	Type           string                       `graphql:"type" json:"type"`
+	History        []structs.RunStateTransition `graphql:"history" json:"-"`
+}
+
+// runHistoryEntry is a state transition, as written to the bundle.
+type runHistoryEntry struct {
+	State        string  `json:"state"`
+	StateVersion int     `json:"stateVersion"`
+	Timestamp    int     `json:"timestamp"`
+	Terminal     bool    `json:"terminal"`
+	HasLogs      bool    `json:"hasLogs"`
+	Username     *string `json:"username"`
+	Note         *string `json:"note"`
+}
+
+func runExport(ctx context.Context, cliCmd *cli.Command) error {
+	if nArgs := cliCmd.NArg(); nArgs != 0 {
+		return fmt.Errorf("expected zero arguments but got %d", nArgs)
+	}
+
+	stackID, err := getStackID(ctx, cliCmd)
+	if err != nil {
+		return err
+	}
+	runID := cliCmd.String(flagRequiredRun.Name)
+
+	path := cliCmd.String(flagRunExportOutput.Name)
+	if path == "" {
+		path = runID + ".tar.gz"
+	}
+
+	files, err := collectRunExport(ctx, stackID, runID)
+	if err != nil {
+		return err
+	}
+
+	index := runExportIndex{
+		StackID:    stackID,
+		RunID:      runID,
+		URL:        authenticated.Client(ctx).URL("/stack/%s/run/%s", stackID, runID),
+		ExportedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+
+	out, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("couldn't create %s: %w", path, err)
+	}
+	defer out.Close()
+
+	if err := writeRunBundle(out, index, files, time.Now()); err != nil {
+		return fmt.Errorf("couldn't write %s: %w", path, err)
+	}
+
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("couldn't write %s: %w", path, err)
+	}
+
+	fmt.Printf("Run %s exported to %s (%d files)\n", runID, path, len(files)+1)
+
+	return nil
+}
+
+// collectRunExport gathers the metadata, history, logs of every phase, changes,
+// comments and policy receipts of a run.
+func collectRunExport(ctx context.Context, stackID, runID string) ([]runExportFile, error) {
+	var query struct {
+		Stack *struct {
+			Run *exportedRun `graphql:"run(id: $run)"`
+		} `graphql:"stack(id: $stack)"`
+	}
+
+	variables := map[string]any{
+		"stack": graphql.ID(stackID),
+		"run":   graphql.ID(runID),
+	}
+
+	if err := authenticated.Client(ctx).Query(ctx, &query, variables); err != nil {
+		return nil, fmt.Errorf("failed to query run: %w", err)
+	}
+
+	if query.Stack == nil {
+		return nil, fmt.Errorf("stack %q not found", stackID)
+	}
+
+	if query.Stack.Run == nil {
+		return nil, fmt.Errorf("run %q in stack %q not found", runID, stackID)
+	}
+
+	run := query.Stack.Run
+
+	changes, err := getRunChanges(ctx, stackID, runID)
+	if err != nil {
+		return nil, err
+	}
+
+	comments, err := getRunComments(ctx, stackID, runID)
+	if err != nil {
+		return nil, err
+	}
+
+	receipts, err := getPolicyReceipts(ctx, stackID, runID)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []runExportFile
+	addJSON := func(path, description string, v any) error {
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return fmt.Errorf("couldn't encode %s: %w", path, err)
+		}
+
+		files = append(files, runExportFile{path: path, description: description, data: append(data, '\n')})
+		return nil
+	}
+
+	// History is ordered newest first, the bundle lists it chronologically.
+	history := slices.Clone(run.History)
+	slices.Reverse(history)
+
+	entries := make([]runHistoryEntry, 0, len(history))
+	for _, transition := range history {
+		entries = append(entries, runHistoryEntry{
+			State:        string(transition.State),
+			StateVersion: transition.StateVersion,
+			Timestamp:    transition.Timestamp,
+			Terminal:     transition.Terminal,
+			HasLogs:      transition.HasLogs,
+			Username:     transition.Username,
+			Note:         transition.Note,
+		})
+	}
+
+	for _, f := range []struct {
+		path, description string
+		v                 any
+	}{
+		{"run.json", "Run metadata", run},
+		{"history.json", "State transitions, oldest first", entries},
+		{"changes.json", "Resource changes (changesV3)", changes},
+		{"comments.json", "Comments, oldest first", comments},
+		{"policy-receipts.json", "Policy evaluations", receipts},
+	} {
+		if err := addJSON(f.path, f.description, f.v); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, transition := range history {
+		if !transition.HasLogs {
+			continue
+		}
+
+		phaseLogs, err := logs.StateLogs(ctx, stackID, runID, transition.State, transition.StateVersion)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the logs of the %s phase: %w", transition.State, err)
+		}
+
+		files = append(files, runExportFile{
+			path:        fmt.Sprintf("logs/%02d-%s.log", i+1, transition.State),
+			description: fmt.Sprintf("Logs of the %s phase (version %d)", transition.State, transition.StateVersion),
+			data:        []byte(phaseLogs),
+		})
+	}
+
+	return files, nil
+}
+
+// writeRunBundle writes the files as a gzipped tarball, preceded by an index
+// listing them with their checksums.
+func writeRunBundle(w io.Writer, index runExportIndex, files []runExportFile, modTime time.Time) error {
+	index.Files = make([]runExportedFile, 0, len(files))
+	for _, f := range files {
+		sum := sha256.Sum256(f.data)
+		index.Files = append(index.Files, runExportedFile{
+			Path:        f.path,
+			Description: f.description,
+			Size:        len(f.data),
+			SHA256:      hex.EncodeToString(sum[:]),
+		})
+	}
+
+	indexData, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	all := append([]runExportFile{{path: runExportIndexFile, data: append(indexData, '\n')}}, files...)
+	for _, f := range all {
+		header := &tar.Header{
+			Name:    f.path,
+			Mode:    0o644,
+			Size:    int64(len(f.data)),
+			ModTime: modTime,
+		}
+
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+
+		if _, err := tw.Write(f.data); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+
+	return gz.Close()
+}
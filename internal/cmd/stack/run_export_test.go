@@ -0,0 +1,59 @@
+package stack
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteRunBundle(t *testing.T) {
+	files := []runExportFile{
+		{path: "run.json", description: "Run metadata", data: []byte(`{"id":"run-1"}` + "\n")},
+		{path: "logs/02-PLANNING.log", description: "Logs of the PLANNING phase (version 0)", data: []byte("Plan: 1 to add\n")},
+	}
+
+	var buf bytes.Buffer
+	modTime := time.Date(2025, 6, 10, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, writeRunBundle(&buf, runExportIndex{StackID: "stack-1", RunID: "run-1"}, files, modTime))
+
+	gz, err := gzip.NewReader(&buf)
+	require.NoError(t, err)
+
+	contents := map[string]string{}
+	var order []string
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+
+		data, err := io.ReadAll(tr)
+		require.NoError(t, err)
+
+		assert.Equal(t, modTime, header.ModTime.UTC())
+		contents[header.Name] = string(data)
+		order = append(order, header.Name)
+	}
+
+	assert.Equal(t, []string{"index.json", "run.json", "logs/02-PLANNING.log"}, order)
+	assert.Equal(t, "Plan: 1 to add\n", contents["logs/02-PLANNING.log"])
+
+	var index runExportIndex
+	require.NoError(t, json.Unmarshal([]byte(contents["index.json"]), &index))
+
+	assert.Equal(t, "stack-1", index.StackID)
+	assert.Equal(t, []runExportedFile{
+		{Path: "run.json", Description: "Run metadata", Size: 15, SHA256: "a2be853b74997fd9e9a08e5638f843b0f761baae7c42e4cbdd3addb4ecb44420"},
+		{Path: "logs/02-PLANNING.log", Description: "Logs of the PLANNING phase (version 0)", Size: 15, SHA256: "b941fab623e67418ee77efcd8a88af1161ebbd38d7fe4b78e80e4eddf062f28f"},
+	}, index.Files)
+}
@@ -0,0 +1,178 @@
+package stack
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/shurcooL/graphql"
+	"github.com/urfave/cli/v3"
+
+	"github.com/spacelift-io/spacectl/client/structs"
+)
+
+// runFields are the properties of a run that can be filtered on.
+type runFields struct {
+	State       string
+	Type        string
+	Branch      string
+	AuthorLogin string
+	AuthorName  string
+	TriggeredBy string
+	CreatedAt   int
+}
+
+// runFilter selects runs by their properties. Empty fields match any run.
+type runFilter struct {
+	states      []string
+	types       []string
+	branch      string
+	author      string
+	triggeredBy string
+	since       *time.Time
+	until       *time.Time
+}
+
+func runFilterFromFlags(cliCmd *cli.Command, now time.Time) (runFilter, error) {
+	filter := runFilter{
+		branch:      cliCmd.String(flagRunBranch.Name),
+		author:      cliCmd.String(flagRunAuthor.Name),
+		triggeredBy: cliCmd.String(flagRunTriggeredBy.Name),
+	}
+
+	for _, state := range cliCmd.StringSlice(flagRunState.Name) {
+		filter.states = append(filter.states, strings.ToUpper(state))
+	}
+
+	for _, runType := range cliCmd.StringSlice(flagRunType.Name) {
+		filter.types = append(filter.types, strings.ToUpper(runType))
+	}
+
+	var err error
+	if filter.since, err = parseTimeBound(cliCmd.String(flagRunSince.Name), now); err != nil {
+		return runFilter{}, fmt.Errorf("invalid --%s value: %w", flagRunSince.Name, err)
+	}
+
+	if filter.until, err = parseTimeBound(cliCmd.String(flagRunUntil.Name), now); err != nil {
+		return runFilter{}, fmt.Errorf("invalid --%s value: %w", flagRunUntil.Name, err)
+	}
+
+	if filter.since != nil && filter.until != nil && filter.until.Before(*filter.since) {
+		return runFilter{}, fmt.Errorf("--%s must not be before --%s", flagRunUntil.Name, flagRunSince.Name)
+	}
+
+	return filter, nil
+}
+
+// parseTimeBound parses either a duration relative to now, for example "24h",
+// or an RFC3339 timestamp. An empty value means there is no bound.
+func parseTimeBound(value string, now time.Time) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	if d, err := time.ParseDuration(value); err == nil {
+		return new(now.Add(-d)), nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("expected a duration like 24h or an RFC3339 timestamp, got %q", value)
+	}
+
+	return &t, nil
+}
+
+func (f runFilter) isEmpty() bool {
+	return len(f.states) == 0 && len(f.types) == 0 && f.branch == "" && f.author == "" && f.triggeredBy == "" && f.since == nil && f.until == nil
+}
+
+func (f runFilter) matches(run runFields) bool {
+	if len(f.states) > 0 && !slices.Contains(f.states, run.State) {
+		return false
+	}
+
+	if len(f.types) > 0 && !slices.Contains(f.types, run.Type) {
+		return false
+	}
+
+	if f.branch != "" && f.branch != run.Branch {
+		return false
+	}
+
+	if f.author != "" && !strings.EqualFold(f.author, run.AuthorLogin) && !strings.EqualFold(f.author, run.AuthorName) {
+		return false
+	}
+
+	if f.triggeredBy != "" && !strings.EqualFold(f.triggeredBy, run.TriggeredBy) {
+		return false
+	}
+
+	if f.until != nil && int64(run.CreatedAt) > f.until.Unix() {
+		return false
+	}
+
+	return !f.isBeforeWindow(run)
+}
+
+// isBeforeWindow reports whether the run was created before the time window.
+// Runs are listed newest first, so no later run can match either.
+func (f runFilter) isBeforeWindow(run runFields) bool {
+	return f.since != nil && int64(run.CreatedAt) < f.since.Unix()
+}
+
+// predicates converts the filter to search predicates.
+func (f runFilter) predicates() []structs.QueryPredicate {
+	var predicates []structs.QueryPredicate
+
+	enumEquals := func(field string, values []string) {
+		if len(values) == 0 {
+			return
+		}
+
+		constraint := make([]graphql.String, len(values))
+		for i, value := range values {
+			constraint[i] = graphql.String(value)
+		}
+
+		predicates = append(predicates, structs.QueryPredicate{
+			Field:      graphql.String(field),
+			Constraint: structs.QueryFieldConstraint{EnumEquals: &constraint},
+		})
+	}
+
+	stringMatches := func(field, value string) {
+		if value == "" {
+			return
+		}
+
+		predicates = append(predicates, structs.QueryPredicate{
+			Field:      graphql.String(field),
+			Constraint: structs.QueryFieldConstraint{StringMatches: &[]graphql.String{graphql.String(value)}},
+		})
+	}
+
+	enumEquals("state", f.states)
+	enumEquals("type", f.types)
+	stringMatches("branch", f.branch)
+	stringMatches("commitAuthor", f.author)
+	stringMatches("triggeredBy", f.triggeredBy)
+
+	if f.since != nil || f.until != nil {
+		var timeRange structs.TimeRange
+		if f.since != nil {
+			timeRange.Start = new(graphql.Int(f.since.Unix())) //nolint: gosec
+		}
+		if f.until != nil {
+			timeRange.End = new(graphql.Int(f.until.Unix())) //nolint: gosec
+		}
+
+		predicates = append(predicates, structs.QueryPredicate{
+			Field:      "createdAt",
+			Constraint: structs.QueryFieldConstraint{TimeInRange: &timeRange},
+		})
+	}
+
+	return predicates
+}
@@ -0,0 +1,155 @@
+package stack
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTimeBound(t *testing.T) {
+	now := time.Date(2025, 6, 10, 12, 0, 0, 0, time.UTC)
+
+	bound, err := parseTimeBound("", now)
+	require.NoError(t, err)
+	assert.Nil(t, bound)
+
+	bound, err = parseTimeBound("24h", now)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(-24*time.Hour), *bound)
+
+	bound, err = parseTimeBound("2025-06-01T08:00:00Z", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 6, 1, 8, 0, 0, 0, time.UTC), *bound)
+
+	_, err = parseTimeBound("yesterday", now)
+	assert.EqualError(t, err, `expected a duration like 24h or an RFC3339 timestamp, got "yesterday"`)
+}
+
+func TestRunFilter(t *testing.T) {
+	since := time.Unix(1000, 0)
+	until := time.Unix(2000, 0)
+
+	run := runFields{
+		State:       "FAILED",
+		Type:        "TRACKED",
+		Branch:      "main",
+		AuthorLogin: "jdoe",
+		AuthorName:  "Jane Doe",
+		TriggeredBy: "api::01ABC",
+		CreatedAt:   1500,
+	}
+
+	cases := map[string]struct {
+		filter   runFilter
+		expected bool
+	}{
+		"no filter":          {filter: runFilter{}, expected: true},
+		"state":              {filter: runFilter{states: []string{"FINISHED", "FAILED"}}, expected: true},
+		"other state":        {filter: runFilter{states: []string{"FINISHED"}}, expected: false},
+		"type":               {filter: runFilter{types: []string{"TRACKED"}}, expected: true},
+		"other type":         {filter: runFilter{types: []string{"PROPOSED"}}, expected: false},
+		"branch":             {filter: runFilter{branch: "main"}, expected: true},
+		"other branch":       {filter: runFilter{branch: "develop"}, expected: false},
+		"author login":       {filter: runFilter{author: "JDOE"}, expected: true},
+		"author name":        {filter: runFilter{author: "jane doe"}, expected: true},
+		"other author":       {filter: runFilter{author: "someone"}, expected: false},
+		"triggered by":       {filter: runFilter{triggeredBy: "api::01abc"}, expected: true},
+		"in time window":     {filter: runFilter{since: &since, until: &until}, expected: true},
+		"before time window": {filter: runFilter{since: new(time.Unix(1600, 0))}, expected: false},
+		"after time window":  {filter: runFilter{until: new(time.Unix(1400, 0))}, expected: false},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, c.expected, c.filter.matches(run))
+		})
+	}
+}
+
+func TestRunFilterPredicates(t *testing.T) {
+	filter := runFilter{
+		states: []string{"FAILED"},
+		branch: "main",
+		since:  new(time.Unix(1000, 0)),
+	}
+
+	data, err := json.Marshal(filter.predicates())
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `[
+		{"field": "state", "constraint": {"booleanEquals": null, "enumEquals": ["FAILED"], "stringMatches": null}, "exclude": false},
+		{"field": "branch", "constraint": {"booleanEquals": null, "enumEquals": null, "stringMatches": ["main"]}, "exclude": false},
+		{"field": "createdAt", "constraint": {"booleanEquals": null, "enumEquals": null, "stringMatches": null, "timeInRange": {"start": 1000, "end": null}}, "exclude": false}
+	]`, string(data))
+}
+
+func TestFetchFilteredRuns(t *testing.T) {
+	// Ten failed and finished runs, newest first, in pages of four.
+	var all []runsJSONQuery
+	for i := 10; i > 0; i-- {
+		state := "FINISHED"
+		if i%2 == 0 {
+			state = "FAILED"
+		}
+		all = append(all, runsJSONQuery{ID: string(rune('a' + i)), State: state, CreatedAt: i * 100})
+	}
+
+	fetcher := func(_ context.Context, before *string) ([]runsJSONQuery, error) {
+		start := 0
+		if before != nil {
+			start = slices.IndexFunc(all, func(r runsJSONQuery) bool { return r.ID == *before }) + 1
+		}
+		return all[start:min(start+4, len(all))], nil
+	}
+
+	ids := func(runs []runsJSONQuery) (out []string) {
+		for _, r := range runs {
+			out = append(out, r.ID)
+		}
+		return out
+	}
+
+	t.Run("filters across pages", func(t *testing.T) {
+		runs, next, err := fetchFilteredRuns(t.Context(), runPage{maxResults: 3, filter: runFilter{states: []string{"FAILED"}}}, fetcher)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"k", "i", "g"}, ids(runs))
+		require.NotNil(t, next)
+		assert.Equal(t, "g", *next)
+
+		runs, next, err = fetchFilteredRuns(t.Context(), runPage{maxResults: 3, filter: runFilter{states: []string{"FAILED"}}, cursor: next}, fetcher)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"e", "c"}, ids(runs))
+		assert.Nil(t, next)
+	})
+
+	t.Run("stops after the page limit", func(t *testing.T) {
+		page := runPage{maxResults: 10, maxPages: 2, filter: runFilter{states: []string{"QUEUED"}}}
+
+		runs, next, err := fetchFilteredRuns(t.Context(), page, fetcher)
+		require.NoError(t, err)
+
+		assert.Empty(t, runs)
+		require.NotNil(t, next)
+		assert.Equal(t, "d", *next)
+
+		runs, _, err = fetchFilteredRuns(t.Context(), runPage{maxResults: 6, maxPages: 1}, fetcher)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"k", "j", "i", "h", "g", "f"}, ids(runs), "the limit only applies to filtered lists")
+	})
+
+	t.Run("stops at the start of the time window", func(t *testing.T) {
+		runs, next, err := fetchFilteredRuns(t.Context(), runPage{maxResults: 10, filter: runFilter{since: new(time.Unix(650, 0))}}, fetcher)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"k", "j", "i", "h"}, ids(runs))
+		assert.Nil(t, next)
+	})
+}
@@ -0,0 +1,222 @@
+package stack
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/shurcooL/graphql"
+	"github.com/urfave/cli/v3"
+	"gopkg.in/yaml.v3"
+
+	"github.com/spacelift-io/spacectl/internal/cmd/authenticated"
+)
+
+// confirmGuardrails are checked client-side before confirming a run. They are
+// opt-in: zero values disable the corresponding check.
+type confirmGuardrails struct {
+	// MaxDeletes is the maximum number of resources deleted by the run.
+	MaxDeletes *int `yaml:"max_deletes"`
+	// MaxReplaces is the maximum number of resources replaced by the run.
+	MaxReplaces *int `yaml:"max_replaces"`
+	// ForbiddenTypes are globs of resource types that must not be deleted
+	// or replaced, for example "aws_db_*".
+	ForbiddenTypes []string `yaml:"forbidden_types"`
+	// ForbiddenAddresses are globs of resource addresses that must not be
+	// deleted or replaced, for example "module.prod.*".
+	ForbiddenAddresses []string `yaml:"forbidden_addresses"`
+	// RequiredState is the state the run must be in, for example UNCONFIRMED.
+	RequiredState string `yaml:"required_state"`
+}
+
+// guardedRun is what the guardrails are checked against.
+type guardedRun struct {
+	State   string
+	Delta   *runDelta
+	Changes []runChangesData
+}
+
+// confirmGuardrailsFromFlags reads the rules file, if any, and applies the
+// flags on top of it: limits and the required state set by flags replace the
+// ones from the file, forbidden patterns are added to them.
+func confirmGuardrailsFromFlags(cliCmd *cli.Command) (confirmGuardrails, error) {
+	var guardrails confirmGuardrails
+
+	if cliCmd.IsSet(flagGuardrailsFile.Name) {
+		filePath := cliCmd.String(flagGuardrailsFile.Name)
+
+		data, err := os.ReadFile(filePath)
+		if err != nil {
+			return confirmGuardrails{}, fmt.Errorf("couldn't read guardrails file from %s: %w", filePath, err)
+		}
+
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&guardrails); err != nil && !errors.Is(err, io.EOF) {
+			return confirmGuardrails{}, fmt.Errorf("invalid guardrails file %s: %w", filePath, err)
+		}
+	}
+
+	if cliCmd.IsSet(flagGuardrailMaxDeletes.Name) {
+		guardrails.MaxDeletes = new(int(cliCmd.Int(flagGuardrailMaxDeletes.Name)))
+	}
+
+	if cliCmd.IsSet(flagGuardrailMaxReplaces.Name) {
+		guardrails.MaxReplaces = new(int(cliCmd.Int(flagGuardrailMaxReplaces.Name)))
+	}
+
+	guardrails.ForbiddenTypes = append(guardrails.ForbiddenTypes, cliCmd.StringSlice(flagGuardrailForbidType.Name)...)
+	guardrails.ForbiddenAddresses = append(guardrails.ForbiddenAddresses, cliCmd.StringSlice(flagGuardrailForbidAddress.Name)...)
+
+	if cliCmd.IsSet(flagGuardrailRequireState.Name) {
+		guardrails.RequiredState = cliCmd.String(flagGuardrailRequireState.Name)
+	}
+	guardrails.RequiredState = strings.ToUpper(guardrails.RequiredState)
+
+	for _, pattern := range append(slices.Clone(guardrails.ForbiddenTypes), guardrails.ForbiddenAddresses...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return confirmGuardrails{}, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+	}
+
+	return guardrails, nil
+}
+
+func (g confirmGuardrails) isEmpty() bool {
+	return g.MaxDeletes == nil && g.MaxReplaces == nil && len(g.ForbiddenTypes) == 0 &&
+		len(g.ForbiddenAddresses) == 0 && g.RequiredState == ""
+}
+
+// check returns the guardrails violated by the run.
+func (g confirmGuardrails) check(run guardedRun) []string {
+	var violations []string
+
+	if g.RequiredState != "" && run.State != g.RequiredState {
+		violations = append(violations, fmt.Sprintf("the run is in %s state, %s is required", run.State, g.RequiredState))
+	}
+
+	var deletes, replaces int
+	for _, changes := range run.Changes {
+		for _, resource := range changes.Resources {
+			action := strings.ToUpper(resource.Metadata.Type)
+
+			replace := strings.HasPrefix(action, "REPLACE")
+			switch {
+			case replace:
+				replaces++
+			case action == "DELETE":
+				deletes++
+			default:
+				continue
+			}
+
+			verb := "deletes"
+			if replace {
+				verb = "replaces"
+			}
+
+			resourceType := resourceTypeFromAddress(resource.Address)
+			if pattern, found := matchingPattern(g.ForbiddenTypes, resourceType); found {
+				violations = append(violations, fmt.Sprintf("the run %s %s, resources of type %s are forbidden by %q", verb, resource.Address, resourceType, pattern))
+			} else if pattern, found := matchingPattern(g.ForbiddenAddresses, resource.Address); found {
+				violations = append(violations, fmt.Sprintf("the run %s %s, forbidden by %q", verb, resource.Address, pattern))
+			}
+		}
+	}
+
+	// The delta also counts the deletions of replaced resources, use the
+	// highest count so that the check fails closed.
+	if run.Delta != nil {
+		deletes = max(deletes, run.Delta.DeleteCount)
+	}
+
+	if g.MaxDeletes != nil && deletes > *g.MaxDeletes {
+		violations = append(violations, fmt.Sprintf("the run deletes %d resources, the maximum is %d", deletes, *g.MaxDeletes))
+	}
+
+	if g.MaxReplaces != nil && replaces > *g.MaxReplaces {
+		violations = append(violations, fmt.Sprintf("the run replaces %d resources, the maximum is %d", replaces, *g.MaxReplaces))
+	}
+
+	return violations
+}
+
+func matchingPattern(patterns []string, value string) (string, bool) {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, value); matched {
+			return pattern, true
+		}
+	}
+
+	return "", false
+}
+
+// resourceTypeFromAddress extracts the resource type from a Terraform resource
+// address, for example aws_instance from module.app.aws_instance.web[0].
+func resourceTypeFromAddress(address string) string {
+	parts := strings.Split(address, ".")
+
+	for len(parts) >= 2 && parts[0] == "module" {
+		parts = parts[2:]
+	}
+
+	if len(parts) >= 2 && parts[0] == "data" {
+		parts = parts[1:]
+	}
+
+	if len(parts) == 0 {
+		return ""
+	}
+
+	return parts[0]
+}
+
+// enforceConfirmGuardrails fails with an explanation when the run violates
+// any of the guardrails.
+func enforceConfirmGuardrails(ctx context.Context, guardrails confirmGuardrails, stackID, runID string) error {
+	if guardrails.isEmpty() {
+		return nil
+	}
+
+	var query struct {
+		Stack *struct {
+			Run *struct {
+				State string    `graphql:"state"`
+				Delta *runDelta `graphql:"delta"`
+			} `graphql:"run(id: $run)"`
+		} `graphql:"stack(id: $stack)"`
+	}
+
+	variables := map[string]any{
+		"stack": graphql.ID(stackID),
+		"run":   graphql.ID(runID),
+	}
+
+	if err := authenticated.Client(ctx).Query(ctx, &query, variables); err != nil {
+		return fmt.Errorf("failed to query run for guardrails: %w", err)
+	}
+
+	if query.Stack == nil || query.Stack.Run == nil {
+		return fmt.Errorf("run %q in stack %q not found", runID, stackID)
+	}
+
+	changes, err := getRunChanges(ctx, stackID, runID)
+	if err != nil {
+		return err
+	}
+
+	run := guardedRun{State: query.Stack.Run.State, Delta: query.Stack.Run.Delta, Changes: changes}
+
+	violations := guardrails.check(run)
+	if len(violations) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("refusing to confirm run %s:\n  - %s", runID, strings.Join(violations, "\n  - "))
+}
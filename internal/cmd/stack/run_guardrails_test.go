@@ -0,0 +1,119 @@
+package stack
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+func TestResourceTypeFromAddress(t *testing.T) {
+	for address, expected := range map[string]string{
+		"aws_instance.web":                          "aws_instance",
+		"aws_instance.web[0]":                       "aws_instance",
+		`module.app.aws_s3_bucket.logs["eu"]`:       "aws_s3_bucket",
+		"module.app.module.db.aws_db_instance.main": "aws_db_instance",
+		"module.app.data.aws_ami.ubuntu":            "aws_ami",
+	} {
+		assert.Equal(t, expected, resourceTypeFromAddress(address), address)
+	}
+}
+
+func TestConfirmGuardrailsCheck(t *testing.T) {
+	change := func(address, action string) runChangesResource {
+		return runChangesResource{Address: address, Metadata: runChangesMetadata{Type: action}}
+	}
+
+	run := guardedRun{
+		State: "UNCONFIRMED",
+		Delta: &runDelta{AddCount: 1, DeleteCount: 2},
+		Changes: []runChangesData{{Resources: []runChangesResource{
+			change("aws_instance.web", "ADD"),
+			change("module.prod.aws_db_instance.main", "REPLACE_CREATE_BEFORE_DESTROY"),
+			change("aws_s3_bucket.logs", "DELETE"),
+			change("aws_iam_role.app", "CHANGE"),
+		}}},
+	}
+
+	assert.Empty(t, confirmGuardrails{
+		MaxDeletes:     new(2),
+		MaxReplaces:    new(1),
+		ForbiddenTypes: []string{"aws_iam_*", "aws_instance"},
+		RequiredState:  "UNCONFIRMED",
+	}.check(run), "changes and additions are not destructive")
+
+	assert.Equal(t, []string{
+		"the run is in UNCONFIRMED state, FINISHED is required",
+		`the run replaces module.prod.aws_db_instance.main, resources of type aws_db_instance are forbidden by "aws_db_*"`,
+		`the run deletes aws_s3_bucket.logs, forbidden by "aws_s3_bucket.*"`,
+		"the run deletes 2 resources, the maximum is 0",
+		"the run replaces 1 resources, the maximum is 0",
+	}, confirmGuardrails{
+		MaxDeletes:         new(0),
+		MaxReplaces:        new(0),
+		ForbiddenTypes:     []string{"aws_db_*"},
+		ForbiddenAddresses: []string{"aws_s3_bucket.*"},
+		RequiredState:      "FINISHED",
+	}.check(run))
+
+	run.Delta = nil
+	assert.Equal(t, []string{"the run deletes 1 resources, the maximum is 0"}, confirmGuardrails{MaxDeletes: new(0)}.check(run),
+		"deletions are counted from the changes without a delta")
+}
+
+func TestConfirmGuardrailsFromFlags(t *testing.T) {
+	rulesFile := filepath.Join(t.TempDir(), "guardrails.yaml")
+	require.NoError(t, os.WriteFile(rulesFile, []byte(`
+max_deletes: 0
+max_replaces: 3
+forbidden_types: ["aws_db_*"]
+required_state: unconfirmed
+`), 0o600))
+
+	parse := func(args ...string) (confirmGuardrails, error) {
+		// Copy the flags, as they keep their state between runs.
+		file, maxDeletes, maxReplaces := *flagGuardrailsFile, *flagGuardrailMaxDeletes, *flagGuardrailMaxReplaces
+		forbidType, forbidAddress, requireState := *flagGuardrailForbidType, *flagGuardrailForbidAddress, *flagGuardrailRequireState
+
+		var guardrails confirmGuardrails
+		var err error
+
+		command := &cli.Command{
+			Flags: []cli.Flag{&file, &maxDeletes, &maxReplaces, &forbidType, &forbidAddress, &requireState},
+			Action: func(_ context.Context, cliCmd *cli.Command) error {
+				guardrails, err = confirmGuardrailsFromFlags(cliCmd)
+				return nil
+			},
+		}
+		require.NoError(t, command.Run(t.Context(), append([]string{"confirm"}, args...)))
+
+		return guardrails, err
+	}
+
+	guardrails, err := parse()
+	require.NoError(t, err)
+	assert.True(t, guardrails.isEmpty())
+
+	guardrails, err = parse("--guardrails", rulesFile, "--max-replaces", "1", "--forbid-type", "aws_kms_key", "--forbid-address", "module.prod.*")
+	require.NoError(t, err)
+	assert.Equal(t, confirmGuardrails{
+		MaxDeletes:         new(0),
+		MaxReplaces:        new(1),
+		ForbiddenTypes:     []string{"aws_db_*", "aws_kms_key"},
+		ForbiddenAddresses: []string{"module.prod.*"},
+		RequiredState:      "UNCONFIRMED",
+	}, guardrails)
+
+	_, err = parse("--forbid-address", "module.[")
+	assert.ErrorContains(t, err, `invalid pattern "module.["`)
+
+	unknownKey := filepath.Join(t.TempDir(), "unknown.yaml")
+	require.NoError(t, os.WriteFile(unknownKey, []byte("max_delete: 1\n"), 0o600))
+
+	_, err = parse("--guardrails", unknownKey)
+	assert.ErrorContains(t, err, "field max_delete not found")
+}
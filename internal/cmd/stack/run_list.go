@@ -3,6 +3,7 @@ package stack
 import (
 	"context"
 	"fmt"
+	"os"
 	"strings"
 	"time"
 
@@ -14,7 +15,7 @@ import (
 )
 
 func runList(ctx context.Context, cliCmd *cli.Command) error {
-	outputFormat, err := cmd.GetOutputFormat(cliCmd)
+	outputFormat, formatter, err := cmd.GetOutputFormat(cliCmd)
 	if err != nil {
 		return err
 	}
@@ -23,27 +24,38 @@ func runList(ctx context.Context, cliCmd *cli.Command) error {
 	if err != nil {
 		return err
 	}
-	maxResults := cliCmd.Int(flagMaxResults.Name)
+
+	filter, err := runFilterFromFlags(cliCmd, time.Now())
+	if err != nil {
+		return err
+	}
+
+	page := runPage{
+		maxResults: cliCmd.Int(flagMaxResults.Name),
+		maxPages:   maxScannedRunPages,
+		filter:     filter,
+	}
+	if cliCmd.IsSet(flagRunCursor.Name) {
+		page.cursor = new(cliCmd.String(flagRunCursor.Name))
+	}
 	showPreview := cliCmd.Bool(flagPreviewRuns.Name)
 
 	switch outputFormat {
 	case cmd.OutputFormatTable:
-		return listRunsTable(ctx, maxResults, func(ctx context.Context, before *string) ([]runsTableQuery, error) {
+		return listRunsTable(ctx, page, func(ctx context.Context, before *string) ([]runsTableQuery, error) {
 			if showPreview {
 				return queryPreviewRuns[runsTableQuery](ctx, stackID, before)
 			}
 			return queryTrackedRuns[runsTableQuery](ctx, stackID, before)
 		})
-	case cmd.OutputFormatJSON:
-		return listRunsJSON(ctx, maxResults, func(ctx context.Context, before *string) ([]runsJSONQuery, error) {
+	default:
+		return listRunsJSON(ctx, formatter, page, func(ctx context.Context, before *string) ([]runsJSONQuery, error) {
 			if showPreview {
 				return queryPreviewRuns[runsJSONQuery](ctx, stackID, before)
 			}
 			return queryTrackedRuns[runsJSONQuery](ctx, stackID, before)
 		})
 	}
-
-	return fmt.Errorf("unknown output format: %v", outputFormat)
 }
 
 type runsJSONQuery struct {
@@ -74,16 +86,49 @@ type runsJSONQuery struct {
 	State          string `graphql:"state" json:"state"`
 	Title          string `graphql:"title" json:"title"`
 	TriggeredBy    string `graphql:"triggeredBy" json:"triggeredBy"`
+	Type           string `graphql:"type" json:"type"`
 }
 
 func (r runsJSONQuery) Cursor() string {
 	return r.ID
 }
 
+func (r runsJSONQuery) filterFields() runFields {
+	return runFields{
+		State:       r.State,
+		Type:        r.Type,
+		Branch:      r.Branch,
+		AuthorLogin: r.Commit.AuthorLogin,
+		AuthorName:  r.Commit.AuthorName,
+		TriggeredBy: r.TriggeredBy,
+		CreatedAt:   r.CreatedAt,
+	}
+}
+
 type withCursor interface {
 	Cursor() string
 }
 
+type filterableRun interface {
+	withCursor
+	filterFields() runFields
+}
+
+// maxScannedRunPages is the number of pages fetched looking for runs matching
+// a filter. Stack runs are filtered on the client, so a selective filter could
+// otherwise page through the whole history of the stack.
+const maxScannedRunPages = 10
+
+// runPage selects the runs to list: up to maxResults runs matching the filter,
+// starting after the cursor if set. With a filter, no more than maxPages pages
+// are fetched, 0 for no limit.
+type runPage struct {
+	maxResults int
+	maxPages   int
+	filter     runFilter
+	cursor     *string
+}
+
 func queryTrackedRuns[T any](ctx context.Context, stackID string, before *string) ([]T, error) {
 	var query struct {
 		Stack *struct {
@@ -91,7 +136,7 @@ func queryTrackedRuns[T any](ctx context.Context, stackID string, before *string
 		} `graphql:"stack(id: $stackId)"`
 	}
 
-	if err := authenticated.Client().Query(ctx, &query, map[string]any{"stackId": stackID, "before": before}); err != nil {
+	if err := authenticated.Client(ctx).Query(ctx, &query, map[string]any{"stackId": stackID, "before": before}); err != nil {
 		return nil, errors.Wrap(err, "failed to query run list")
 	}
 
@@ -109,7 +154,7 @@ func queryPreviewRuns[T any](ctx context.Context, stackID string, before *string
 		} `graphql:"stack(id: $stackId)"`
 	}
 
-	if err := authenticated.Client().Query(ctx, &query, map[string]any{"stackId": stackID, "before": before}); err != nil {
+	if err := authenticated.Client(ctx).Query(ctx, &query, map[string]any{"stackId": stackID, "before": before}); err != nil {
 		return nil, errors.Wrap(err, "failed to query run list")
 	}
 
@@ -146,58 +191,135 @@ func fetchRuns[T withCursor](ctx context.Context, maxResults int, fetcher func(c
 	return results, nil
 }
 
-func listRunsJSON(ctx context.Context, maxResults int, fetcher func(context.Context, *string) ([]runsJSONQuery, error)) error {
-	results, err := fetchRuns(ctx, maxResults, fetcher)
+// fetchFilteredRuns pages through the runs, newest first, and returns the ones
+// selected by the page. The returned cursor is set when more matching runs may
+// follow, including when the page limit is reached.
+func fetchFilteredRuns[T filterableRun](ctx context.Context, page runPage, fetcher func(context.Context, *string) ([]T, error)) ([]T, *string, error) {
+	var results []T
+	before := page.cursor
+
+	for pages := 0; len(results) < page.maxResults; pages++ {
+		if page.maxPages > 0 && pages == page.maxPages && !page.filter.isEmpty() {
+			return results, before, nil
+		}
+
+		runs, err := fetcher(ctx, before)
+
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "failed to query run list")
+		}
+
+		if len(runs) == 0 {
+			return results, nil, nil
+		}
+
+		for _, run := range runs {
+			fields := run.filterFields()
+			if page.filter.isBeforeWindow(fields) {
+				return results, nil, nil
+			}
+
+			before = new(run.Cursor())
+
+			if page.filter.matches(fields) {
+				results = append(results, run)
+			}
+
+			if len(results) == page.maxResults {
+				break
+			}
+		}
+	}
+
+	return results, before, nil
+}
+
+// printNextCursor tells the user how to get the next page. It is written to
+// stderr so that it does not break structured output.
+func printNextCursor(cursor *string) {
+	if cursor != nil {
+		fmt.Fprintf(os.Stderr, "More runs may be available, use --%s %s to get the next page\n", flagRunCursor.Name, *cursor)
+	}
+}
+
+func listRunsJSON(ctx context.Context, formatter cmd.OutputFormatter, page runPage, fetcher func(context.Context, *string) ([]runsJSONQuery, error)) error {
+	results, next, err := fetchFilteredRuns(ctx, page, fetcher)
 	if err != nil {
 		return err
 	}
 
-	return cmd.OutputJSON(results)
+	if err := cmd.Output(formatter, results); err != nil {
+		return err
+	}
+
+	printNextCursor(next)
+	return nil
 }
 
 type runsTableQuery struct {
 	ID     string `graphql:"id"`
 	State  string `graphql:"state"`
+	Type   string `graphql:"type"`
 	Title  string `graphql:"title"`
+	Branch string `graphql:"branch"`
 	Commit struct {
-		AuthorName string `graphql:"authorName"`
-		Hash       string `graphql:"hash"`
+		AuthorLogin string `graphql:"authorLogin"`
+		AuthorName  string `graphql:"authorName"`
+		Hash        string `graphql:"hash"`
 	} `graphql:"commit"`
-	CreatedAt   int    `graphql:"createdAt"`
-	TriggeredBy string `graphql:"triggeredBy"`
-	Delta       struct {
-		AddCount    int `graphql:"addCount"`
-		ChangeCount int `graphql:"changeCount"`
-		DeleteCount int `graphql:"deleteCount"`
-	} `graphql:"delta"`
+	CreatedAt   int      `graphql:"createdAt"`
+	TriggeredBy string   `graphql:"triggeredBy"`
+	Delta       runDelta `graphql:"delta"`
+}
+
+// runDelta is the number of resources changed by a run.
+type runDelta struct {
+	AddCount    int `graphql:"addCount" json:"addCount"`
+	ChangeCount int `graphql:"changeCount" json:"changeCount"`
+	DeleteCount int `graphql:"deleteCount" json:"deleteCount"`
+}
+
+// String formats the delta the way it is shown in the UI, for example "+1 ~2 -3".
+func (d runDelta) String() string {
+	var components []string
+
+	if d.AddCount > 0 {
+		components = append(components, fmt.Sprintf("+%d", d.AddCount))
+	}
+	if d.ChangeCount > 0 {
+		components = append(components, fmt.Sprintf("~%d", d.ChangeCount))
+	}
+	if d.DeleteCount > 0 {
+		components = append(components, fmt.Sprintf("-%d", d.DeleteCount))
+	}
+
+	return strings.Join(components, " ")
 }
 
 func (r runsTableQuery) Cursor() string {
 	return r.ID
 }
 
-func listRunsTable(ctx context.Context, maxResults int, fetcher func(context.Context, *string) ([]runsTableQuery, error)) error {
-	results, err := fetchRuns(ctx, maxResults, fetcher)
+func (r runsTableQuery) filterFields() runFields {
+	return runFields{
+		State:       r.State,
+		Type:        r.Type,
+		Branch:      r.Branch,
+		AuthorLogin: r.Commit.AuthorLogin,
+		AuthorName:  r.Commit.AuthorName,
+		TriggeredBy: r.TriggeredBy,
+		CreatedAt:   r.CreatedAt,
+	}
+}
+
+func listRunsTable(ctx context.Context, page runPage, fetcher func(context.Context, *string) ([]runsTableQuery, error)) error {
+	results, next, err := fetchFilteredRuns(ctx, page, fetcher)
 	if err != nil {
 		return err
 	}
 
 	tableData := [][]string{{"ID", "Status", "Message", "Commit", "Triggered At", "Triggered By", "Changes"}}
 	for _, run := range results {
-		var deltaComponents []string
-
-		if run.Delta.AddCount > 0 {
-			deltaComponents = append(deltaComponents, fmt.Sprintf("+%d", run.Delta.AddCount))
-		}
-		if run.Delta.ChangeCount > 0 {
-			deltaComponents = append(deltaComponents, fmt.Sprintf("~%d", run.Delta.ChangeCount))
-		}
-		if run.Delta.DeleteCount > 0 {
-			deltaComponents = append(deltaComponents, fmt.Sprintf("-%d", run.Delta.DeleteCount))
-		}
-
-		delta := strings.Join(deltaComponents, " ")
-
 		triggeredBy := run.TriggeredBy
 		if triggeredBy == "" {
 			triggeredBy = "Git commit"
@@ -212,9 +334,14 @@ func listRunsTable(ctx context.Context, maxResults int, fetcher func(context.Con
 			cmd.HumanizeGitHash(run.Commit.Hash),
 			createdAt.Format(time.RFC3339),
 			triggeredBy,
-			delta,
+			run.Delta.String(),
 		})
 	}
 
-	return cmd.OutputTable(tableData, true)
+	if err := cmd.OutputTable(tableData, true); err != nil {
+		return err
+	}
+
+	printNextCursor(next)
+	return nil
 }
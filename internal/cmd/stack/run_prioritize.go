@@ -24,7 +24,7 @@ func runPrioritize(ctx context.Context, cliCmd *cli.Command) error {
 	}
 
 	fmt.Printf("Run ID %q has been successfully prioritized\n", runID)
-	fmt.Println("The live run can be visited at", authenticated.Client().URL(
+	fmt.Println("The live run can be visited at", authenticated.Client(ctx).URL(
 		"/stack/%s/run/%s",
 		stackID,
 		mutation.SetRunPriority.ID,
@@ -57,7 +57,7 @@ func setRunPriority(ctx context.Context, stackID, runID string, prioritize bool)
 		"prioritize": graphql.Boolean(prioritize),
 	}
 
-	if err := authenticated.Client().Mutate(ctx, &mutation, variables); err != nil {
+	if err := authenticated.Client(ctx).Mutate(ctx, &mutation, variables); err != nil {
 		return setRunPriorityMutation{}, err
 	}
 
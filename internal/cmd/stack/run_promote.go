@@ -29,13 +29,13 @@ func runPromote() cli.ActionFunc {
 			"run":   graphql.ID(cliCmd.String(flagRequiredRun.Name)),
 		}
 
-		if err := authenticated.Client().Mutate(ctx, &mutation, variables); err != nil {
+		if err := authenticated.Client(ctx).Mutate(ctx, &mutation, variables); err != nil {
 			return err
 		}
 
 		fmt.Println("You have successfully promoted the run to a tracked run")
 
-		fmt.Println("The live run can be visited at", authenticated.Client().URL(
+		fmt.Println("The live run can be visited at", authenticated.Client(ctx).URL(
 			"/stack/%s/run/%s",
 			stackID,
 			mutation.RunPromote.ID,
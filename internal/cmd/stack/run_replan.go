@@ -55,12 +55,12 @@ func runReplan(ctx context.Context, cliCmd *cli.Command) error {
 		"targets": targets,
 	}
 
-	if err := authenticated.Client().Mutate(ctx, &mutation, variables); err != nil {
+	if err := authenticated.Client(ctx).Mutate(ctx, &mutation, variables); err != nil {
 		return err
 	}
 
 	fmt.Printf("Run ID %q is being replanned\n", runID)
-	fmt.Println("The live run can be visited at", authenticated.Client().URL(
+	fmt.Println("The live run can be visited at", authenticated.Client(ctx).URL(
 		"/stack/%s/run/%s",
 		stackID,
 		mutation.RunTargetedReplan.ID,
@@ -0,0 +1,477 @@
+package stack
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/manifoldco/promptui"
+	"github.com/pkg/errors"
+	"github.com/shurcooL/graphql"
+	"github.com/urfave/cli/v3"
+
+	"github.com/spacelift-io/spacectl/client/enums"
+	"github.com/spacelift-io/spacectl/internal/cmd"
+	"github.com/spacelift-io/spacectl/internal/cmd/authenticated"
+)
+
+const approvalPolicyType = "APPROVAL"
+
+const (
+	reviewActionChanges = "View changes"
+	reviewActionApprove = "Approve"
+	reviewActionReject  = "Reject"
+	reviewActionSkip    = "Skip"
+	reviewActionQuit    = "Quit"
+)
+
+// reviewQueueItem is a run waiting for a review, together with its stack and
+// the outcomes of the approval policies evaluated so far.
+type reviewQueueItem struct {
+	StackID          string          `json:"stackId"`
+	StackName        string          `json:"stackName"`
+	Space            string          `json:"space"`
+	Labels           []string        `json:"labels"`
+	Run              reviewQueueRun  `json:"run"`
+	ApprovalPolicies []policyReceipt `json:"approvalPolicies"`
+}
+
+type reviewQueueStack struct {
+	ID     string           `graphql:"id"`
+	Name   string           `graphql:"name"`
+	Space  string           `graphql:"space"`
+	Labels []string         `graphql:"labels"`
+	Runs   []reviewQueueRun `graphql:"runs"`
+}
+
+type reviewQueueRun struct {
+	ID     string `graphql:"id" json:"id"`
+	Branch string `graphql:"branch" json:"branch"`
+	Commit struct {
+		AuthorName string `graphql:"authorName" json:"authorName"`
+		Hash       string `graphql:"hash" json:"hash"`
+	} `graphql:"commit" json:"commit"`
+	CreatedAt     int       `graphql:"createdAt" json:"createdAt"`
+	Delta         *runDelta `graphql:"delta" json:"delta"`
+	NeedsApproval bool      `graphql:"needsApproval" json:"needsApproval"`
+	State         string    `graphql:"state" json:"state"`
+	Title         string    `graphql:"title" json:"title"`
+	TriggeredBy   string    `graphql:"triggeredBy" json:"triggeredBy"`
+}
+
+type policyReceipt struct {
+	Name      string   `graphql:"name" json:"name"`
+	Type      string   `graphql:"type" json:"type"`
+	Outcome   string   `graphql:"outcome" json:"outcome"`
+	Flags     []string `graphql:"flags" json:"flags"`
+	CreatedAt int      `graphql:"createdAt" json:"createdAt"`
+}
+
+type reviewQueueFilter struct {
+	spaces          []string
+	labels          []string
+	stackGlob       string
+	maxDeletes      *int
+	approvalOutcome string
+}
+
+func reviewQueueFilterFromFlags(cliCmd *cli.Command) (reviewQueueFilter, error) {
+	filter := reviewQueueFilter{
+		spaces:          cliCmd.StringSlice(flagReviewQueueSpace.Name),
+		labels:          cliCmd.StringSlice(flagReviewQueueLabel.Name),
+		stackGlob:       cliCmd.String(flagReviewQueueStackGlob.Name),
+		approvalOutcome: cliCmd.String(flagReviewQueueApprovalOutcome.Name),
+	}
+
+	if filter.stackGlob != "" {
+		if _, err := path.Match(filter.stackGlob, ""); err != nil {
+			return reviewQueueFilter{}, fmt.Errorf("invalid stack glob %q: %w", filter.stackGlob, err)
+		}
+	}
+
+	if cliCmd.IsSet(flagReviewQueueMaxDeletes.Name) {
+		filter.maxDeletes = new(int(cliCmd.Int(flagReviewQueueMaxDeletes.Name)))
+	}
+
+	return filter, nil
+}
+
+func (f reviewQueueFilter) isEmpty() bool {
+	return len(f.spaces) == 0 && len(f.labels) == 0 && f.stackGlob == "" && f.maxDeletes == nil && f.approvalOutcome == ""
+}
+
+func (f reviewQueueFilter) matches(item reviewQueueItem) bool {
+	if len(f.spaces) > 0 && !slices.Contains(f.spaces, item.Space) {
+		return false
+	}
+
+	for _, label := range f.labels {
+		if !slices.Contains(item.Labels, label) {
+			return false
+		}
+	}
+
+	if f.stackGlob != "" {
+		if matched, _ := path.Match(f.stackGlob, item.StackID); !matched {
+			return false
+		}
+	}
+
+	// Without a delta the number of deletes is unknown, so the run could
+	// delete anything.
+	if f.maxDeletes != nil && (item.Run.Delta == nil || item.Run.Delta.DeleteCount > *f.maxDeletes) {
+		return false
+	}
+
+	if f.approvalOutcome != "" {
+		return slices.ContainsFunc(item.ApprovalPolicies, func(receipt policyReceipt) bool {
+			return strings.EqualFold(receipt.Outcome, f.approvalOutcome)
+		})
+	}
+
+	return true
+}
+
+func runReviewQueue(ctx context.Context, cliCmd *cli.Command) error {
+	if nArgs := cliCmd.NArg(); nArgs != 0 {
+		return fmt.Errorf("expected zero arguments but got %d", nArgs)
+	}
+
+	outputFormat, formatter, err := cmd.GetOutputFormat(cliCmd)
+	if err != nil {
+		return err
+	}
+
+	interactive := cliCmd.Bool(flagInteractive.Name)
+	approve := cliCmd.Bool(flagReviewQueueApprove.Name)
+	if interactive && approve {
+		return fmt.Errorf("--%s and --%s cannot be used together", flagInteractive.Name, flagReviewQueueApprove.Name)
+	}
+
+	filter, err := reviewQueueFilterFromFlags(cliCmd)
+	if err != nil {
+		return err
+	}
+
+	yes := cliCmd.Bool(flagReviewQueueYes.Name)
+	if approve && filter.isEmpty() && !yes {
+		return fmt.Errorf("--%s without any filter approves every run waiting for approval, narrow it down with filters or pass --%s", flagReviewQueueApprove.Name, flagReviewQueueYes.Name)
+	}
+
+	queue, err := getReviewQueue(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	note := cliCmd.String(flagRunReviewNote.Name)
+
+	switch {
+	case interactive:
+		return reviewInteractively(ctx, queue, note)
+	case approve:
+		return approveReviewQueue(ctx, queue, note, yes)
+	}
+
+	switch outputFormat {
+	case cmd.OutputFormatTable:
+		return outputReviewQueueTable(queue)
+	default:
+		return cmd.Output(formatter, queue)
+	}
+}
+
+// getReviewQueue returns the runs needing approval across all the stacks the
+// user has access to, oldest first.
+func getReviewQueue(ctx context.Context, filter reviewQueueFilter) ([]reviewQueueItem, error) {
+	var query struct {
+		Stacks []reviewQueueStack `graphql:"stacks"`
+	}
+
+	if err := authenticated.Client(ctx).Query(ctx, &query, map[string]any{}); err != nil {
+		return nil, errors.Wrap(err, "failed to query list of stacks")
+	}
+
+	queue := []reviewQueueItem{}
+	for _, stack := range query.Stacks {
+		for _, run := range stack.Runs {
+			if !run.NeedsApproval {
+				continue
+			}
+
+			receipts, err := getApprovalPolicyReceipts(ctx, stack.ID, run.ID)
+			if err != nil {
+				return nil, err
+			}
+
+			item := reviewQueueItem{
+				StackID:          stack.ID,
+				StackName:        stack.Name,
+				Space:            stack.Space,
+				Labels:           stack.Labels,
+				Run:              run,
+				ApprovalPolicies: receipts,
+			}
+
+			if filter.matches(item) {
+				queue = append(queue, item)
+			}
+		}
+	}
+
+	slices.SortStableFunc(queue, func(a, b reviewQueueItem) int {
+		return a.Run.CreatedAt - b.Run.CreatedAt
+	})
+
+	return queue, nil
+}
+
+func getApprovalPolicyReceipts(ctx context.Context, stackID, runID string) ([]policyReceipt, error) {
+	all, err := getPolicyReceipts(ctx, stackID, runID)
+	if err != nil {
+		return nil, err
+	}
+
+	receipts := []policyReceipt{}
+	for _, receipt := range all {
+		if receipt.Type == approvalPolicyType {
+			receipts = append(receipts, receipt)
+		}
+	}
+
+	return receipts, nil
+}
+
+func getPolicyReceipts(ctx context.Context, stackID, runID string) ([]policyReceipt, error) {
+	var query struct {
+		Stack *struct {
+			Run *struct {
+				PolicyReceipts []policyReceipt `graphql:"policyReceipts"`
+			} `graphql:"run(id: $run)"`
+		} `graphql:"stack(id: $stack)"`
+	}
+
+	variables := map[string]any{
+		"stack": graphql.ID(stackID),
+		"run":   graphql.ID(runID),
+	}
+
+	if err := authenticated.Client(ctx).Query(ctx, &query, variables); err != nil {
+		return nil, errors.Wrapf(err, "failed to query policy receipts of run %s", runID)
+	}
+
+	if query.Stack == nil || query.Stack.Run == nil {
+		return nil, fmt.Errorf("run %q not found on stack %q", runID, stackID)
+	}
+
+	return query.Stack.Run.PolicyReceipts, nil
+}
+
+func (i reviewQueueItem) delta() string {
+	if i.Run.Delta == nil {
+		return ""
+	}
+
+	return i.Run.Delta.String()
+}
+
+// approvalOutcomes summarises the approval policy evaluations, for example
+// "team-leads: undecided, security: approve".
+func (i reviewQueueItem) approvalOutcomes() string {
+	outcomes := make([]string, 0, len(i.ApprovalPolicies))
+	for _, receipt := range i.ApprovalPolicies {
+		outcomes = append(outcomes, fmt.Sprintf("%s: %s", receipt.Name, receipt.Outcome))
+	}
+
+	return strings.Join(outcomes, ", ")
+}
+
+func (i reviewQueueItem) triggeredBy() string {
+	if i.Run.TriggeredBy == "" {
+		return "Git commit"
+	}
+
+	return i.Run.TriggeredBy
+}
+
+func outputReviewQueueTable(queue []reviewQueueItem) error {
+	tableData := [][]string{{"Stack", "Run", "Title", "State", "Changes", "Approval Policies", "Triggered By", "Triggered At"}}
+	for _, item := range queue {
+		tableData = append(tableData, []string{
+			item.StackID,
+			item.Run.ID,
+			item.Run.Title,
+			item.Run.State,
+			item.delta(),
+			item.approvalOutcomes(),
+			item.triggeredBy(),
+			cmd.HumanizeUnixSeconds(item.Run.CreatedAt),
+		})
+	}
+
+	return cmd.OutputTable(tableData, true)
+}
+
+func approveReviewQueue(ctx context.Context, queue []reviewQueueItem, note string, yes bool) error {
+	if len(queue) == 0 {
+		fmt.Println("No runs are waiting for approval")
+		return nil
+	}
+
+	if !yes {
+		confirmed, err := confirmReviewQueueApproval(queue)
+		if err != nil {
+			return err
+		}
+
+		if !confirmed {
+			fmt.Println("No runs were approved")
+			return nil
+		}
+	}
+
+	for _, item := range queue {
+		if err := addRunReview(ctx, item.StackID, item.Run.ID, note, enums.RunReviewDecisionApprove); err != nil {
+			return fmt.Errorf("failed to approve run %s on stack %s: %w", item.Run.ID, item.StackID, err)
+		}
+
+		fmt.Printf("Approved run %s on stack %s\n", item.Run.ID, item.StackID)
+	}
+
+	return nil
+}
+
+// confirmReviewQueueApproval shows the runs about to be approved and asks the
+// user to confirm.
+func confirmReviewQueueApproval(queue []reviewQueueItem) (bool, error) {
+	if err := outputReviewQueueTable(queue); err != nil {
+		return false, err
+	}
+
+	prompt := promptui.Prompt{
+		Label:     fmt.Sprintf("Approve these %d runs", len(queue)),
+		IsConfirm: true,
+	}
+
+	if _, err := prompt.Run(); err != nil {
+		if errors.Is(err, promptui.ErrAbort) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+// reviewInteractively steps through the queue, letting the reviewer look at
+// the changes of every run before approving, rejecting or skipping it.
+func reviewInteractively(ctx context.Context, queue []reviewQueueItem, note string) error {
+	if len(queue) == 0 {
+		fmt.Println("No runs are waiting for approval")
+		return nil
+	}
+
+	var approved, rejected, skipped int
+	defer func() {
+		fmt.Printf("\nApproved: %d, rejected: %d, skipped: %d\n", approved, rejected, skipped)
+	}()
+
+	for i, item := range queue {
+		fmt.Printf("\n[%d/%d] ", i+1, len(queue))
+		printReviewQueueItem(ctx, item)
+
+	prompt:
+		for {
+			prompt := promptui.Select{
+				Label: "What do you want to do",
+				Items: []string{reviewActionChanges, reviewActionApprove, reviewActionReject, reviewActionSkip, reviewActionQuit},
+			}
+
+			_, action, err := prompt.Run()
+			if err != nil {
+				return err
+			}
+
+			switch action {
+			case reviewActionChanges:
+				if err := printReviewRunChanges(ctx, item); err != nil {
+					return err
+				}
+			case reviewActionApprove, reviewActionReject:
+				var decision enums.RunReviewDecision = enums.RunReviewDecisionApprove
+				if action == reviewActionReject {
+					decision = enums.RunReviewDecisionReject
+				}
+
+				if err := reviewWithNote(ctx, item, note, decision); err != nil {
+					return err
+				}
+
+				if decision == enums.RunReviewDecisionApprove {
+					approved++
+				} else {
+					rejected++
+				}
+				break prompt
+			case reviewActionSkip:
+				skipped++
+				break prompt
+			case reviewActionQuit:
+				skipped += len(queue) - i
+				return nil
+			}
+		}
+	}
+
+	return nil
+}
+
+func printReviewQueueItem(ctx context.Context, item reviewQueueItem) {
+	fmt.Printf("%s (%s)\n", item.Run.Title, item.Run.ID)
+	fmt.Printf("  Stack:             %s (%s)\n", item.StackName, item.StackID)
+	fmt.Printf("  State:             %s\n", item.Run.State)
+	fmt.Printf("  Commit:            %s by %s on %s\n", cmd.HumanizeGitHash(item.Run.Commit.Hash), item.Run.Commit.AuthorName, item.Run.Branch)
+	fmt.Printf("  Triggered:         %s by %s\n", time.Unix(int64(item.Run.CreatedAt), 0).Format(time.RFC3339), item.triggeredBy())
+	fmt.Printf("  Changes:           %s\n", item.delta())
+	fmt.Printf("  Approval policies: %s\n", item.approvalOutcomes())
+	fmt.Printf("  URL:               %s\n", authenticated.Client(ctx).URL("/stack/%s/run/%s", item.StackID, item.Run.ID))
+}
+
+func printReviewRunChanges(ctx context.Context, item reviewQueueItem) error {
+	changes, err := getRunChanges(ctx, item.StackID, item.Run.ID)
+	if err != nil {
+		return err
+	}
+
+	tableData := [][]string{{"Address", "Change"}}
+	for _, change := range changes {
+		for _, resource := range change.Resources {
+			tableData = append(tableData, []string{resource.Address, resource.Metadata.Type})
+		}
+	}
+
+	if len(tableData) == 1 {
+		fmt.Println("The run has no resource changes")
+		return nil
+	}
+
+	return cmd.OutputTable(tableData, true)
+}
+
+func reviewWithNote(ctx context.Context, item reviewQueueItem, note string, decision enums.RunReviewDecision) error {
+	prompt := promptui.Prompt{
+		Label:     "Note (optional)",
+		Default:   note,
+		AllowEdit: true,
+	}
+
+	note, err := prompt.Run()
+	if err != nil {
+		return err
+	}
+
+	return addRunReview(ctx, item.StackID, item.Run.ID, note, decision)
+}
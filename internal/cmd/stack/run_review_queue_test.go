@@ -0,0 +1,60 @@
+package stack
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReviewQueueFilter(t *testing.T) {
+	item := reviewQueueItem{
+		StackID: "prod-network",
+		Space:   "prod",
+		Labels:  []string{"network", "team:infra"},
+		Run: reviewQueueRun{
+			ID:    "run-1",
+			Delta: &runDelta{AddCount: 1, DeleteCount: 2},
+		},
+		ApprovalPolicies: []policyReceipt{
+			{Name: "team-leads", Type: approvalPolicyType, Outcome: "undecided"},
+			{Name: "security", Type: approvalPolicyType, Outcome: "approve"},
+		},
+	}
+
+	cases := map[string]struct {
+		filter   reviewQueueFilter
+		expected bool
+	}{
+		"no filter":              {filter: reviewQueueFilter{}, expected: true},
+		"space":                  {filter: reviewQueueFilter{spaces: []string{"dev", "prod"}}, expected: true},
+		"other space":            {filter: reviewQueueFilter{spaces: []string{"dev"}}, expected: false},
+		"all labels":             {filter: reviewQueueFilter{labels: []string{"network", "team:infra"}}, expected: true},
+		"missing label":          {filter: reviewQueueFilter{labels: []string{"network", "team:apps"}}, expected: false},
+		"stack glob":             {filter: reviewQueueFilter{stackGlob: "prod-*"}, expected: true},
+		"other stack glob":       {filter: reviewQueueFilter{stackGlob: "dev-*"}, expected: false},
+		"deletes within limit":   {filter: reviewQueueFilter{maxDeletes: new(2)}, expected: true},
+		"too many deletes":       {filter: reviewQueueFilter{maxDeletes: new(0)}, expected: false},
+		"approval outcome":       {filter: reviewQueueFilter{approvalOutcome: "UNDECIDED"}, expected: true},
+		"other approval outcome": {filter: reviewQueueFilter{approvalOutcome: "reject"}, expected: false},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, c.expected, c.filter.matches(item))
+		})
+	}
+
+	assert.Equal(t, "team-leads: undecided, security: approve", item.approvalOutcomes())
+	assert.Equal(t, "+1 -2", item.delta())
+	assert.Equal(t, "Git commit", item.triggeredBy())
+}
+
+func TestReviewQueueFilterWithoutDelta(t *testing.T) {
+	item := reviewQueueItem{StackID: "prod-network", Run: reviewQueueRun{ID: "run-1"}}
+
+	assert.True(t, reviewQueueFilter{}.matches(item))
+	assert.False(t, reviewQueueFilter{maxDeletes: new(2)}.matches(item), "runs with unknown deletes must not pass --max-deletes")
+
+	assert.True(t, reviewQueueFilter{}.isEmpty())
+	assert.False(t, reviewQueueFilter{maxDeletes: new(0)}.isEmpty())
+}
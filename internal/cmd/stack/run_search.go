@@ -0,0 +1,147 @@
+package stack
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/shurcooL/graphql"
+	"github.com/urfave/cli/v3"
+
+	"github.com/spacelift-io/spacectl/client/structs"
+	"github.com/spacelift-io/spacectl/internal/cmd"
+	"github.com/spacelift-io/spacectl/internal/cmd/authenticated"
+)
+
+// searchedRun is a run found by a search across all stacks.
+type searchedRun struct {
+	ID    string `graphql:"id" json:"id"`
+	Stack struct {
+		ID   string `graphql:"id" json:"id"`
+		Name string `graphql:"name" json:"name"`
+	} `graphql:"stack" json:"stack"`
+	Branch string `graphql:"branch" json:"branch"`
+	Commit struct {
+		AuthorLogin string `graphql:"authorLogin" json:"authorLogin"`
+		AuthorName  string `graphql:"authorName" json:"authorName"`
+		Hash        string `graphql:"hash" json:"hash"`
+	} `graphql:"commit" json:"commit"`
+	CreatedAt   int       `graphql:"createdAt" json:"createdAt"`
+	Delta       *runDelta `graphql:"delta" json:"delta"`
+	State       string    `graphql:"state" json:"state"`
+	Title       string    `graphql:"title" json:"title"`
+	TriggeredBy string    `graphql:"triggeredBy" json:"triggeredBy"`
+	Type        string    `graphql:"type" json:"type"`
+}
+
+func runSearch(ctx context.Context, cliCmd *cli.Command) error {
+	outputFormat, formatter, err := cmd.GetOutputFormat(cliCmd)
+	if err != nil {
+		return err
+	}
+
+	filter, err := runFilterFromFlags(cliCmd, time.Now())
+	if err != nil {
+		return err
+	}
+
+	predicates := filter.predicates()
+	input := structs.SearchInput{
+		Predicates: &predicates,
+		OrderBy: &structs.QueryOrder{
+			Field:     "createdAt",
+			Direction: "DESC",
+		},
+	}
+	if cliCmd.IsSet(flagRunCursor.Name) {
+		input.After = graphql.NewString(graphql.String(cliCmd.String(flagRunCursor.Name)))
+	}
+
+	runs, next, err := searchAllRuns(ctx, input, cliCmd.Int(flagMaxResults.Name))
+	if err != nil {
+		return err
+	}
+
+	switch outputFormat {
+	case cmd.OutputFormatTable:
+		tableData := [][]string{{"Stack", "ID", "Type", "Status", "Message", "Commit", "Author", "Triggered At", "Triggered By", "Changes"}}
+		for _, run := range runs {
+			triggeredBy := run.TriggeredBy
+			if triggeredBy == "" {
+				triggeredBy = "Git commit"
+			}
+
+			var delta string
+			if run.Delta != nil {
+				delta = run.Delta.String()
+			}
+
+			tableData = append(tableData, []string{
+				run.Stack.ID,
+				run.ID,
+				run.Type,
+				run.State,
+				run.Title,
+				cmd.HumanizeGitHash(run.Commit.Hash),
+				run.Commit.AuthorName,
+				cmd.HumanizeUnixSeconds(run.CreatedAt),
+				triggeredBy,
+				delta,
+			})
+		}
+
+		if err := cmd.OutputTable(tableData, true); err != nil {
+			return err
+		}
+	default:
+		if err := cmd.Output(formatter, runs); err != nil {
+			return err
+		}
+	}
+
+	printNextCursor(next)
+	return nil
+}
+
+// searchAllRuns returns up to maxResults runs matching the search input. The
+// returned cursor is set when there are more results.
+func searchAllRuns(ctx context.Context, input structs.SearchInput, maxResults int) ([]searchedRun, *string, error) {
+	const maxPageSize = 50
+
+	out := []searchedRun{}
+	if maxResults <= 0 {
+		return out, nil, nil
+	}
+
+	for len(out) < maxResults {
+		//nolint: gosec
+		input.First = graphql.NewInt(graphql.Int(min(maxPageSize, maxResults-len(out))))
+
+		var query struct {
+			SearchRunsOutput struct {
+				Edges []struct {
+					Node searchedRun `graphql:"node"`
+				} `graphql:"edges"`
+				PageInfo structs.PageInfo `graphql:"pageInfo"`
+			} `graphql:"searchRuns(input: $input)"`
+		}
+
+		if err := authenticated.Client(ctx).Query(ctx, &query, map[string]any{"input": input}); err != nil {
+			return nil, nil, errors.Wrap(err, "failed to search for runs")
+		}
+
+		for _, edge := range query.SearchRunsOutput.Edges {
+			out = append(out, edge.Node)
+		}
+
+		// An empty page would keep requesting the same cursor forever.
+		pageInfo := query.SearchRunsOutput.PageInfo
+		if !pageInfo.HasNextPage || len(query.SearchRunsOutput.Edges) == 0 {
+			return out, nil, nil
+		}
+
+		input.After = graphql.NewString(graphql.String(pageInfo.EndCursor))
+	}
+
+	return out, new(string(*input.After)), nil
+}
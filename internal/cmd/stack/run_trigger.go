@@ -9,6 +9,7 @@ import (
 	"github.com/shurcooL/graphql"
 	"github.com/urfave/cli/v3"
 
+	"github.com/spacelift-io/spacectl/client/runs"
 	"github.com/spacelift-io/spacectl/client/structs"
 	"github.com/spacelift-io/spacectl/internal"
 	"github.com/spacelift-io/spacectl/internal/cmd/authenticated"
@@ -22,24 +23,16 @@ func runTrigger(spaceliftType, humanType string) cli.ActionFunc {
 			return err
 		}
 
-		var mutation struct {
-			RunTrigger struct {
-				ID string `graphql:"id"`
-			} `graphql:"runTrigger(stack: $stack, commitSha: $sha, runType: $type, runtimeConfig: $runtimeConfig)"`
-		}
-
-		variables := map[string]any{
-			"stack":         graphql.ID(stackID),
-			"sha":           sha,
-			"type":          structs.NewRunType(spaceliftType),
-			"runtimeConfig": runtimeConfigInput,
-		}
-
-		if err := authenticated.Client().Mutate(ctx, &mutation, variables, requestOpts...); err != nil {
+		run, err := runs.New(authenticated.Client(ctx)).Trigger(ctx, stackID, runs.TriggerInput{
+			Type:          structs.RunType(spaceliftType),
+			CommitSHA:     sha,
+			RuntimeConfig: runtimeConfigInput,
+		}, requestOpts...)
+		if err != nil {
 			return err
 		}
 
-		return finalizeRunTrigger(ctx, cliCmd, stackID, mutation.RunTrigger.ID, humanType, requestOpts)
+		return finalizeRunTrigger(ctx, cliCmd, stackID, run.ID, humanType, requestOpts)
 	}
 }
 
@@ -59,35 +52,27 @@ func runTrigger510(spaceliftType, humanType string) cli.ActionFunc {
 			}
 		}
 
-		var mutation struct {
-			RunTrigger struct {
-				ID string `graphql:"id"`
-			} `graphql:"runTrigger(stack: $stack, commitSha: $sha, runType: $type, runtimeConfig: $runtimeConfig, forceApply: $forceApply)"`
-		}
-
-		variables := map[string]any{
-			"stack":         graphql.ID(stackID),
-			"sha":           sha,
-			"type":          structs.NewRunType(spaceliftType),
-			"runtimeConfig": runtimeConfigInput,
-			"forceApply":    forceApply,
-		}
-
-		if err := authenticated.Client().Mutate(ctx, &mutation, variables, requestOpts...); err != nil {
+		run, err := runs.New(authenticated.Client(ctx)).Trigger(ctx, stackID, runs.TriggerInput{
+			Type:          structs.RunType(spaceliftType),
+			CommitSHA:     sha,
+			RuntimeConfig: runtimeConfigInput,
+			ForceApply:    forceApply,
+		}, requestOpts...)
+		if err != nil {
 			return err
 		}
 
-		return finalizeRunTrigger(ctx, cliCmd, stackID, mutation.RunTrigger.ID, humanType, requestOpts)
+		return finalizeRunTrigger(ctx, cliCmd, stackID, run.ID, humanType, requestOpts)
 	}
 }
 
-func prepareRunTrigger(ctx context.Context, cliCmd *cli.Command) (string, *graphql.String, *RuntimeConfigInput, []graphql.RequestOption, error) {
+func prepareRunTrigger(ctx context.Context, cliCmd *cli.Command) (string, *string, *structs.RuntimeConfigInput, []graphql.RequestOption, error) {
 	stackID, err := getStackID(ctx, cliCmd)
 	if err != nil {
 		return "", nil, nil, nil, err
 	}
 
-	var runtimeConfigInput *RuntimeConfigInput
+	var runtimeConfigInput *structs.RuntimeConfigInput
 	if cliCmd.IsSet(flagRuntimeConfig.Name) {
 		runtimeConfigFilePath := cliCmd.String(flagRuntimeConfig.Name)
 
@@ -102,14 +87,14 @@ func prepareRunTrigger(ctx context.Context, cliCmd *cli.Command) (string, *graph
 
 		yaml := string(data)
 
-		runtimeConfigInput = &RuntimeConfigInput{
+		runtimeConfigInput = &structs.RuntimeConfigInput{
 			Yaml: &yaml,
 		}
 	}
 
-	var sha *graphql.String
+	var sha *string
 	if cliCmd.IsSet(flagCommitSHA.Name) {
-		sha = new(graphql.String(cliCmd.String(flagCommitSHA.Name)))
+		sha = new(cliCmd.String(flagCommitSHA.Name))
 	}
 
 	var requestOpts []graphql.RequestOption
@@ -123,7 +108,7 @@ func prepareRunTrigger(ctx context.Context, cliCmd *cli.Command) (string, *graph
 func finalizeRunTrigger(ctx context.Context, cliCmd *cli.Command, stackID, runID, humanType string, requestOpts []graphql.RequestOption) error {
 	fmt.Println("You have successfully created a", humanType)
 
-	fmt.Println("The live run can be visited at", authenticated.Client().URL(
+	fmt.Println("The live run can be visited at", authenticated.Client(ctx).URL(
 		"/stack/%s/run/%s",
 		stackID,
 		runID,
@@ -142,18 +127,7 @@ func finalizeRunTrigger(ctx context.Context, cliCmd *cli.Command, stackID, runID
 			return nil
 		}
 
-		var mutation struct {
-			RunConfirm struct {
-				ID string `graphql:"id"`
-			} `graphql:"runConfirm(stack: $stack, run: $run)"`
-		}
-
-		variables := map[string]any{
-			"stack": graphql.ID(stackID),
-			"run":   graphql.ID(runID),
-		}
-
-		if err := authenticated.Client().Mutate(ctx, &mutation, variables, requestOpts...); err != nil {
+		if err := runs.New(authenticated.Client(ctx)).Confirm(ctx, stackID, runID, requestOpts...); err != nil {
 			return err
 		}
 
@@ -0,0 +1,251 @@
+package stack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/shurcooL/graphql"
+	"github.com/urfave/cli/v3"
+
+	"github.com/spacelift-io/spacectl/client/structs"
+	"github.com/spacelift-io/spacectl/internal/cmd"
+	"github.com/spacelift-io/spacectl/internal/cmd/authenticated"
+)
+
+// Exit codes of stack run wait.
+const (
+	waitExitReached  = 0
+	waitExitFailed   = 2
+	waitExitEnded    = 3
+	waitExitTimedOut = 4
+)
+
+// runWaitSummary is the JSON summary printed once waiting is over.
+type runWaitSummary struct {
+	StackID         string    `json:"stackId"`
+	RunID           string    `json:"runId"`
+	State           string    `json:"state"`
+	Until           []string  `json:"until"`
+	Reached         bool      `json:"reached"`
+	TimedOut        bool      `json:"timedOut"`
+	Delta           *runDelta `json:"delta"`
+	DurationSeconds int       `json:"durationSeconds"`
+	ExitCode        int       `json:"exitCode"`
+	URL             string    `json:"url"`
+}
+
+type waitedRun struct {
+	State     string                       `graphql:"state"`
+	CreatedAt int                          `graphql:"createdAt"`
+	Delta     *runDelta                    `graphql:"delta"`
+	History   []structs.RunStateTransition `graphql:"history"`
+}
+
+func runWait(ctx context.Context, cliCmd *cli.Command) error {
+	if nArgs := cliCmd.NArg(); nArgs != 0 {
+		return fmt.Errorf("expected zero arguments but got %d", nArgs)
+	}
+
+	stackID, err := getStackID(ctx, cliCmd)
+	if err != nil {
+		return err
+	}
+	runID := cliCmd.String(flagRequiredRun.Name)
+
+	var until []string
+	for _, state := range cliCmd.StringSlice(flagWaitUntil.Name) {
+		until = append(until, strings.ToUpper(state))
+	}
+
+	interval := cliCmd.Duration(flagWaitInterval.Name)
+	if interval <= 0 {
+		return fmt.Errorf("--%s must be positive", flagWaitInterval.Name)
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, cliCmd.Duration(flagWaitTimeout.Name))
+	defer cancel()
+
+	run, timedOut, err := waitForRun(waitCtx, until, interval, func(ctx context.Context) (waitedRun, error) {
+		return getWaitedRun(ctx, stackID, runID)
+	})
+	if err != nil {
+		return err
+	}
+
+	summary := run.summary(until, timedOut, time.Now())
+	summary.StackID = stackID
+	summary.RunID = runID
+	summary.URL = authenticated.Client(ctx).URL("/stack/%s/run/%s", stackID, runID)
+
+	if err := cmd.OutputJSON(summary); err != nil {
+		return err
+	}
+
+	switch summary.ExitCode {
+	case waitExitReached:
+		return nil
+	case waitExitTimedOut:
+		return cli.Exit(fmt.Sprintf("timed out waiting for run %s, last state: %s", runID, summary.State), summary.ExitCode)
+	default:
+		return cli.Exit(fmt.Sprintf("run %s ended in %s state", runID, summary.State), summary.ExitCode)
+	}
+}
+
+// maxWaitRetryBackoff caps the delay between two attempts to query the run
+// after transient errors.
+const maxWaitRetryBackoff = time.Minute
+
+// errWaitedRunNotFound is returned when the stack or the run does not exist.
+var errWaitedRunNotFound = errors.New("not found")
+
+// waitForRun polls the run every interval until waiting is over or the context
+// is done, in which case the last known state of the run is returned as timed
+// out. Query errors are retried with an exponential backoff, unless retrying
+// cannot help.
+func waitForRun(ctx context.Context, until []string, interval time.Duration, fetch func(context.Context) (waitedRun, error)) (waitedRun, bool, error) {
+	var run waitedRun
+	backoff := interval
+
+	for {
+		current, err := fetch(ctx)
+		if err == nil {
+			run = current
+			backoff = interval
+
+			if done, _ := run.waitOver(until); done {
+				return run, false, nil
+			}
+		} else if ctx.Err() == nil && !isTransientWaitError(err) {
+			return run, false, err
+		}
+
+		if ctx.Err() != nil {
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return run, true, nil
+			}
+
+			return run, false, ctx.Err()
+		}
+
+		delay := interval
+		if err != nil {
+			delay = backoff
+			backoff = min(backoff*2, maxWaitRetryBackoff)
+
+			fmt.Fprintf(os.Stderr, "Failed to check the run, retrying in %s: %v\n", delay, err)
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(delay):
+		}
+	}
+}
+
+// transientStatus matches the error of the GraphQL client for the HTTP
+// statuses worth retrying: too many requests and server errors.
+var transientStatus = regexp.MustCompile(`non-200 OK status code: (429|5\d\d) `)
+
+// isTransientWaitError reports whether querying the run again may succeed.
+// Only network errors and the statuses asking to come back later are retried,
+// GraphQL errors and anything else fail right away.
+func isTransientWaitError(err error) bool {
+	var graphQLErrors graphql.GraphQLErrors
+	if errors.As(err, &graphQLErrors) || errors.Is(err, errWaitedRunNotFound) {
+		return false
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	return transientStatus.MatchString(err.Error())
+}
+
+func getWaitedRun(ctx context.Context, stackID, runID string) (waitedRun, error) {
+	var query struct {
+		Stack *struct {
+			Run *waitedRun `graphql:"run(id: $run)"`
+		} `graphql:"stack(id: $stack)"`
+	}
+
+	variables := map[string]any{
+		"stack": graphql.ID(stackID),
+		"run":   graphql.ID(runID),
+	}
+
+	if err := authenticated.Client(ctx).Query(ctx, &query, variables); err != nil {
+		return waitedRun{}, fmt.Errorf("failed to query run: %w", err)
+	}
+
+	if query.Stack == nil {
+		return waitedRun{}, fmt.Errorf("stack %q %w", stackID, errWaitedRunNotFound)
+	}
+
+	if query.Stack.Run == nil {
+		return waitedRun{}, fmt.Errorf("run %q in stack %q %w", runID, stackID, errWaitedRunNotFound)
+	}
+
+	return *query.Stack.Run, nil
+}
+
+// waitOver reports whether there is no need to wait any longer, either because
+// the run is in one of the awaited states or because it ended. Without awaited
+// states, waiting is over when the run ends.
+func (r waitedRun) waitOver(until []string) (done bool, reached bool) {
+	if slices.Contains(until, r.State) {
+		return true, true
+	}
+
+	// History is ordered newest first.
+	if len(r.History) == 0 || !r.History[0].Terminal {
+		return false, false
+	}
+
+	return true, len(until) == 0 && r.State == "FINISHED"
+}
+
+func (r waitedRun) summary(until []string, timedOut bool, now time.Time) runWaitSummary {
+	summary := runWaitSummary{
+		State:    r.State,
+		Until:    until,
+		TimedOut: timedOut,
+		Delta:    r.Delta,
+	}
+
+	if summary.Until == nil {
+		summary.Until = []string{}
+	}
+
+	_, summary.Reached = r.waitOver(until)
+
+	end := int(now.Unix())
+	if len(r.History) > 0 && (summary.Reached || r.History[0].Terminal) {
+		end = r.History[0].Timestamp
+	}
+	if r.CreatedAt > 0 {
+		summary.DurationSeconds = max(end-r.CreatedAt, 0)
+	}
+
+	switch {
+	case summary.Reached:
+		summary.ExitCode = waitExitReached
+	case timedOut:
+		summary.ExitCode = waitExitTimedOut
+	case r.State == "FAILED":
+		summary.ExitCode = waitExitFailed
+	default:
+		summary.ExitCode = waitExitEnded
+	}
+
+	return summary
+}
@@ -0,0 +1,145 @@
+package stack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/shurcooL/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/spacelift-io/spacectl/client/structs"
+)
+
+func TestRunWaitSummary(t *testing.T) {
+	now := time.Unix(2000, 0)
+
+	waitedRunIn := func(state string, terminal bool) waitedRun {
+		return waitedRun{
+			State:     state,
+			CreatedAt: 1000,
+			Delta:     &runDelta{AddCount: 1},
+			History: []structs.RunStateTransition{
+				{State: structs.RunState(state), Terminal: terminal, Timestamp: 1600},
+				{State: "QUEUED", Timestamp: 1000},
+			},
+		}
+	}
+
+	cases := map[string]struct {
+		run      waitedRun
+		until    []string
+		timedOut bool
+		done     bool
+		reached  bool
+		exitCode int
+		duration int
+	}{
+		"finished by default":    {run: waitedRunIn("FINISHED", true), done: true, reached: true, exitCode: waitExitReached, duration: 600},
+		"failed by default":      {run: waitedRunIn("FAILED", true), done: true, exitCode: waitExitFailed, duration: 600},
+		"discarded by default":   {run: waitedRunIn("DISCARDED", true), done: true, exitCode: waitExitEnded, duration: 600},
+		"awaited state":          {run: waitedRunIn("UNCONFIRMED", false), until: []string{"UNCONFIRMED"}, done: true, reached: true, exitCode: waitExitReached, duration: 600},
+		"ended without reaching": {run: waitedRunIn("FINISHED", true), until: []string{"UNCONFIRMED"}, done: true, exitCode: waitExitEnded, duration: 600},
+		"still running":          {run: waitedRunIn("PLANNING", false), until: []string{"FINISHED"}},
+		"timed out":              {run: waitedRunIn("PLANNING", false), timedOut: true, exitCode: waitExitTimedOut, duration: 1000},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			done, reached := c.run.waitOver(c.until)
+			assert.Equal(t, c.done, done, "done")
+			assert.Equal(t, c.reached, reached, "reached")
+
+			if !done && !c.timedOut {
+				return
+			}
+
+			summary := c.run.summary(c.until, c.timedOut, now)
+			assert.Equal(t, c.run.State, summary.State)
+			assert.Equal(t, c.reached, summary.Reached)
+			assert.Equal(t, c.timedOut, summary.TimedOut)
+			assert.Equal(t, c.exitCode, summary.ExitCode)
+			assert.Equal(t, c.duration, summary.DurationSeconds)
+			assert.Equal(t, 1, summary.Delta.AddCount)
+		})
+	}
+}
+
+func TestWaitForRun(t *testing.T) {
+	finished := waitedRun{State: "FINISHED", History: []structs.RunStateTransition{{State: "FINISHED", Terminal: true}}}
+	planning := waitedRun{State: "PLANNING"}
+	connectionReset := fmt.Errorf("failed to query run: %w", &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET})
+
+	// responses returns a fetch function replying with the given results in
+	// order, repeating the last one.
+	responses := func(results ...any) (func(context.Context) (waitedRun, error), *int) {
+		calls := new(int)
+		return func(context.Context) (waitedRun, error) {
+			result := results[min(*calls, len(results)-1)]
+			*calls++
+
+			if err, ok := result.(error); ok {
+				return waitedRun{}, err
+			}
+			return result.(waitedRun), nil
+		}, calls
+	}
+
+	t.Run("retries transient errors", func(t *testing.T) {
+		fetch, calls := responses(planning, connectionReset, errors.New(`non-200 OK status code: 502 Bad Gateway body: ""`), errors.New(`non-200 OK status code: 429 Too Many Requests body: ""`), finished)
+
+		run, timedOut, err := waitForRun(t.Context(), nil, time.Millisecond, fetch)
+		require.NoError(t, err)
+
+		assert.False(t, timedOut)
+		assert.Equal(t, "FINISHED", run.State)
+		assert.Equal(t, 5, *calls)
+	})
+
+	t.Run("fails on persistent errors", func(t *testing.T) {
+		for _, persistent := range []error{
+			fmt.Errorf("run %q in stack %q %w", "run", "stack", errWaitedRunNotFound),
+			errors.New("unauthorized: You can re-login using `spacectl profile login`"),
+			fmt.Errorf("failed to query run: %w", graphql.GraphQLErrors{{Message: "forbidden"}}),
+			errors.New(`non-200 OK status code: 400 Bad Request body: ""`),
+		} {
+			fetch, calls := responses(planning, persistent)
+
+			_, _, err := waitForRun(t.Context(), nil, time.Millisecond, fetch)
+			assert.Equal(t, persistent, err)
+			assert.Equal(t, 2, *calls)
+		}
+	})
+
+	t.Run("times out with the last known state", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(t.Context(), 20*time.Millisecond)
+		defer cancel()
+
+		fetch, _ := responses(planning, connectionReset)
+
+		run, timedOut, err := waitForRun(ctx, nil, time.Millisecond, fetch)
+		require.NoError(t, err)
+
+		assert.True(t, timedOut)
+		assert.Equal(t, "PLANNING", run.State)
+	})
+
+	t.Run("uses a result received after the timeout", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+		defer cancel()
+
+		run, timedOut, err := waitForRun(ctx, nil, time.Millisecond, func(ctx context.Context) (waitedRun, error) {
+			<-ctx.Done()
+			return finished, nil
+		})
+		require.NoError(t, err)
+
+		assert.False(t, timedOut)
+		assert.Equal(t, "FINISHED", run.State)
+	})
+}
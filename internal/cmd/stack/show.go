@@ -256,7 +256,7 @@ func (c *showStackCommand[VC]) showStack(ctx context.Context, cliCmd *cli.Comman
 		return err
 	}
 
-	outputFormat, err := cmd.GetOutputFormat(cliCmd)
+	outputFormat, formatter, err := cmd.GetOutputFormat(cliCmd)
 	if err != nil {
 		return err
 	}
@@ -266,7 +266,7 @@ func (c *showStackCommand[VC]) showStack(ctx context.Context, cliCmd *cli.Comman
 		"stackId": graphql.ID(stackID),
 	}
 
-	if err := authenticated.Client().Query(ctx, &query, variables); err != nil {
+	if err := authenticated.Client(ctx).Query(ctx, &query, variables); err != nil {
 		return errors.Wrapf(err, "failed to query for stack ID %q", stackID)
 	}
 
@@ -277,11 +277,9 @@ func (c *showStackCommand[VC]) showStack(ctx context.Context, cliCmd *cli.Comman
 	switch outputFormat {
 	case cmd.OutputFormatTable:
 		return c.showStackTable(query)
-	case cmd.OutputFormatJSON:
-		return cmd.OutputJSON(query.Stack)
+	default:
+		return cmd.Output(formatter, query.Stack)
 	}
-
-	return fmt.Errorf("unknown output format: %v", outputFormat)
 }
 
 func (c *showStackCommand[VC]) showStackTable(query showStackQuery[VC]) error {
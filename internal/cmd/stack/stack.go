@@ -32,6 +32,12 @@ func Command() cmd.Command {
 								flagRequiredRun,
 								flagRunMetadata,
 								flagTail,
+								flagGuardrailsFile,
+								flagGuardrailMaxDeletes,
+								flagGuardrailMaxReplaces,
+								flagGuardrailForbidType,
+								flagGuardrailForbidAddress,
+								flagGuardrailRequireState,
 							},
 							Action:    runConfirm(),
 							Before:    authenticated.Ensure,
@@ -253,6 +259,7 @@ func Command() cmd.Command {
 								cmd.FlagOutputFormat,
 								cmd.FlagNoColor,
 								cmd.FlagLimit,
+								cmd.FlagPageSize,
 								cmd.FlagSearch,
 							},
 							Action:    listStacks(),
@@ -439,6 +446,14 @@ func Command() cmd.Command {
 									Flags: []cli.Flag{
 										flagStackID,
 										flagMaxResults,
+										flagRunState,
+										flagRunType,
+										flagRunBranch,
+										flagRunAuthor,
+										flagRunTriggeredBy,
+										flagRunSince,
+										flagRunUntil,
+										flagRunCursor,
 										cmd.FlagOutputFormat,
 									},
 									Action:    runList,
@@ -453,6 +468,14 @@ func Command() cmd.Command {
 										flagStackID,
 										flagMaxResults,
 										flagPreviewRuns,
+										flagRunState,
+										flagRunType,
+										flagRunBranch,
+										flagRunAuthor,
+										flagRunTriggeredBy,
+										flagRunSince,
+										flagRunUntil,
+										flagRunCursor,
 										cmd.FlagOutputFormat,
 									},
 									Action:    runList,
@@ -462,6 +485,150 @@ func Command() cmd.Command {
 							},
 						},
 					},
+					{
+						Name:  "search",
+						Usage: "Searches for runs across all stacks",
+						Versions: []cmd.VersionedCommand{
+							{
+								EarliestVersion: cmd.SupportedVersionLatest,
+								Command: &cli.Command{
+									Flags: []cli.Flag{
+										flagMaxResults,
+										flagRunState,
+										flagRunType,
+										flagRunBranch,
+										flagRunAuthor,
+										flagRunTriggeredBy,
+										flagRunSince,
+										flagRunUntil,
+										flagRunCursor,
+										cmd.FlagOutputFormat,
+									},
+									Action:    runSearch,
+									Before:    authenticated.Ensure,
+									ArgsUsage: cmd.EmptyArgsUsage,
+								},
+							},
+						},
+					},
+					{
+						Name:  "comment",
+						Usage: "Manage the comments of a run",
+						Versions: []cmd.VersionedCommand{
+							{
+								EarliestVersion: cmd.SupportedVersionAll,
+								Command:         &cli.Command{},
+							},
+						},
+						Subcommands: []cmd.Command{
+							{
+								Name:  "add",
+								Usage: "Adds a comment to a run, reading the markdown body from a flag, a file or STDIN",
+								Versions: []cmd.VersionedCommand{
+									{
+										EarliestVersion: cmd.SupportedVersionAll,
+										Command: &cli.Command{
+											Flags: []cli.Flag{
+												flagStackID,
+												flagRequiredRun,
+												flagCommentBody,
+												flagCommentBodyFile,
+											},
+											Action:    runCommentAdd,
+											Before:    authenticated.Ensure,
+											ArgsUsage: cmd.EmptyArgsUsage,
+										},
+									},
+								},
+							},
+							{
+								Name:  "list",
+								Usage: "Lists the comments of a run, oldest first",
+								Versions: []cmd.VersionedCommand{
+									{
+										EarliestVersion: cmd.SupportedVersionAll,
+										Command: &cli.Command{
+											Flags: []cli.Flag{
+												flagStackID,
+												flagRequiredRun,
+												cmd.FlagOutputFormat,
+											},
+											Action:    runCommentList,
+											Before:    cmd.PerformAllBefore(cmd.HandleNoColor, authenticated.Ensure),
+											ArgsUsage: cmd.EmptyArgsUsage,
+										},
+									},
+								},
+							},
+						},
+					},
+					{
+						Name: "wait",
+						Usage: "Waits until a run reaches one of the given states or ends, then prints a JSON summary. " +
+							"Exits with 0 when the state is reached, 2 when the run failed, 3 when it ended in another state and 4 on timeout",
+						Versions: []cmd.VersionedCommand{
+							{
+								EarliestVersion: cmd.SupportedVersionAll,
+								Command: &cli.Command{
+									Flags: []cli.Flag{
+										flagStackID,
+										flagRequiredRun,
+										flagWaitUntil,
+										flagWaitTimeout,
+										flagWaitInterval,
+									},
+									Action:    runWait,
+									Before:    authenticated.Ensure,
+									ArgsUsage: cmd.EmptyArgsUsage,
+								},
+							},
+						},
+					},
+					{
+						Name:  "export",
+						Usage: "Exports the metadata, history, logs, changes, comments and policy receipts of a run to a tar.gz archive",
+						Versions: []cmd.VersionedCommand{
+							{
+								EarliestVersion: cmd.SupportedVersionAll,
+								Command: &cli.Command{
+									Flags: []cli.Flag{
+										flagStackID,
+										flagRequiredRun,
+										flagRunExportOutput,
+									},
+									Action:    runExport,
+									Before:    authenticated.Ensure,
+									ArgsUsage: cmd.EmptyArgsUsage,
+								},
+							},
+						},
+					},
+					{
+						Name:  "review-queue",
+						Usage: "Lists the runs waiting for approval across all stacks, and reviews them interactively or in bulk",
+						Versions: []cmd.VersionedCommand{
+							{
+								EarliestVersion: cmd.SupportedVersionLatest,
+								Command: &cli.Command{
+									Flags: []cli.Flag{
+										flagReviewQueueSpace,
+										flagReviewQueueLabel,
+										flagReviewQueueStackGlob,
+										flagReviewQueueMaxDeletes,
+										flagReviewQueueApprovalOutcome,
+										flagInteractive,
+										flagReviewQueueApprove,
+										flagReviewQueueYes,
+										flagRunReviewNote,
+										cmd.FlagOutputFormat,
+									},
+									Action:    runReviewQueue,
+									Before:    authenticated.Ensure,
+									ArgsUsage: cmd.EmptyArgsUsage,
+								},
+							},
+						},
+					},
 				},
 			},
 			{
@@ -842,7 +1009,7 @@ func Command() cmd.Command {
 				Subcommands: []cmd.Command{
 					{
 						Name:  "list",
-						Usage: "Sets an environment variable.",
+						Usage: "Lists the resources of a stack, or of all stacks. On top of the usual output formats, --output tree shows them as a tree",
 						Versions: []cmd.VersionedCommand{
 							{
 								EarliestVersion: cmd.SupportedVersionAll,
@@ -850,9 +1017,80 @@ func Command() cmd.Command {
 									Flags: []cli.Flag{
 										flagStackID,
 										flagRun,
+										cmd.FlagOutputFormat,
+										flagResourceType,
+										flagResourceAddressGlob,
+										flagResourceTainted,
+										flagResourceDrifted,
+										cmd.FlagNoColor,
 									},
 									Action:    resourcesList,
-									Before:    authenticated.Ensure,
+									Before:    cmd.PerformAllBefore(cmd.HandleNoColor, authenticated.Ensure),
+									ArgsUsage: cmd.EmptyArgsUsage,
+								},
+							},
+						},
+					},
+					{
+						Name:  "show",
+						Usage: "Shows the details and the attribute values of a single resource",
+						Versions: []cmd.VersionedCommand{
+							{
+								EarliestVersion: cmd.SupportedVersionAll,
+								Command: &cli.Command{
+									Flags: []cli.Flag{
+										flagStackID,
+										flagRun,
+										cmd.FlagOutputFormat,
+										cmd.FlagNoColor,
+									},
+									Action:    resourcesShow,
+									Before:    cmd.PerformAllBefore(cmd.HandleNoColor, authenticated.Ensure),
+									ArgsUsage: "ADDRESS",
+								},
+							},
+						},
+					},
+					{
+						Name:  "search",
+						Usage: "Searches resources across all stacks you have access to",
+						Versions: []cmd.VersionedCommand{
+							{
+								EarliestVersion: cmd.SupportedVersionAll,
+								Command: &cli.Command{
+									Flags: []cli.Flag{
+										cmd.FlagOutputFormat,
+										flagResourceType,
+										flagResourceAddressGlob,
+										flagResourceProvider,
+										flagResourceAttribute,
+										flagResourceTainted,
+										flagResourceDrifted,
+										cmd.FlagNoColor,
+									},
+									Action:    resourcesSearch,
+									Before:    cmd.PerformAllBefore(cmd.HandleNoColor, authenticated.Ensure),
+									ArgsUsage: cmd.EmptyArgsUsage,
+								},
+							},
+						},
+					},
+					{
+						Name:  "drift",
+						Usage: "Report drifted resources grouped by stack, space and resource type",
+						Versions: []cmd.VersionedCommand{
+							{
+								EarliestVersion: cmd.SupportedVersionAll,
+								Command: &cli.Command{
+									Flags: []cli.Flag{
+										flagStackID,
+										flagRun,
+										cmd.FlagOutputFormat,
+										flagDriftThreshold,
+										cmd.FlagNoColor,
+									},
+									Action:    resourcesDrift,
+									Before:    cmd.PerformAllBefore(cmd.HandleNoColor, authenticated.Ensure),
 									ArgsUsage: cmd.EmptyArgsUsage,
 								},
 							},
@@ -12,6 +12,7 @@ import (
 	"github.com/shurcooL/graphql"
 	"github.com/urfave/cli/v3"
 
+	"github.com/spacelift-io/spacectl/client"
 	"github.com/spacelift-io/spacectl/client/structs"
 	"github.com/spacelift-io/spacectl/internal/cmd/authenticated"
 )
@@ -131,7 +132,7 @@ func stackGetByID[T hasIDAndName](ctx context.Context, stackID string) (*T, erro
 		"id": graphql.ID(stackID),
 	}
 
-	err := authenticated.Client().Query(ctx, &query, variables)
+	err := authenticated.Client(ctx).Query(ctx, &query, variables)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query GraphQL API when checking if a stack exists: %w", err)
 	}
@@ -152,7 +153,7 @@ func stackGetByRunID[T hasIDAndName](ctx context.Context, runID string) (*T, err
 		"runId": graphql.ID(runID),
 	}
 
-	err := authenticated.Client().Query(ctx, &query, variables)
+	err := authenticated.Client(ctx).Query(ctx, &query, variables)
 	if err != nil {
 		if err.Error() == "not found" {
 			return nil, errNoStackFound
@@ -245,22 +246,19 @@ func fetchStacks[T hasIDAndName](
 	filter StackFilter[T],
 ) ([]T, error) {
 	conditions := buildStackSearchPredicates(p)
+	input := structs.SearchInput{Predicates: &conditions}
 
-	input := structs.SearchInput{
-		First:      new(graphql.Int(p.count)), //nolint: gosec
-		Predicates: &conditions,
-	}
-
-	stacks := []T{}
-
+	opts := client.PaginateOptions{PageSize: p.count}
 	if paginateAll {
 		var cancel context.CancelFunc
 		ctx, cancel = context.WithTimeout(ctx, time.Second*15)
 		defer cancel()
+	} else {
+		opts.Limit = p.count
 	}
 
-	for {
-		result, err := searchStacks[T](ctx, input)
+	stacks := []T{}
+	for s, err := range paginateStacks[T](ctx, input, opts) {
 		if err != nil {
 			if paginateAll && errors.Is(err, context.DeadlineExceeded) {
 				return nil, fmt.Errorf("searching stacks took too long - try using --id to specify a stack directly")
@@ -268,23 +266,9 @@ func fetchStacks[T hasIDAndName](
 			return nil, err
 		}
 
-		stacks = append(stacks, result.Stacks...)
-
-		if paginateAll && result.PageInfo.HasNextPage {
-			input.After = new(graphql.String(result.PageInfo.EndCursor))
-		} else {
-			break
-		}
-	}
-
-	if filter != nil {
-		filteredStacks := []T{}
-		for _, s := range stacks {
-			if filter(&s) {
-				filteredStacks = append(filteredStacks, s)
-			}
+		if filter == nil || filter(&s) {
+			stacks = append(stacks, s)
 		}
-		return filteredStacks, nil
 	}
 
 	return stacks, nil
@@ -4,13 +4,12 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"net/http"
 	"os"
 	"path/filepath"
 
-	"github.com/shurcooL/graphql"
 	"github.com/urfave/cli/v3"
 
+	"github.com/spacelift-io/spacectl/client/stacks"
 	"github.com/spacelift-io/spacectl/internal/cmd/authenticated"
 )
 
@@ -27,35 +26,11 @@ func statePull() cli.ActionFunc {
 			return err
 		}
 
-		var mutation struct {
-			StateDownloadURL struct {
-				URL string `graphql:"url"`
-			} `graphql:"stateDownloadUrl(input: $input)"`
-		}
-
-		variables := map[string]any{
-			"input": StateDownloadUrlInput{StackID: graphql.ID(stackID)},
-		}
-
-		if err := authenticated.Client().Mutate(ctx, &mutation, variables); err != nil {
-			return fmt.Errorf("failed to get state download URL for stack %q: %w", stackID, err)
-		}
-
-		req, err := http.NewRequestWithContext(ctx, http.MethodGet, mutation.StateDownloadURL.URL, nil)
-		if err != nil {
-			return fmt.Errorf("failed to create download request: %w", err)
-		}
-
-		resp, err := http.DefaultClient.Do(req)
+		state, err := stacks.New(authenticated.Client(ctx)).DownloadState(ctx, stackID)
 		if err != nil {
-			return fmt.Errorf("failed to download state: %w", err)
-		}
-		defer resp.Body.Close()
-
-		if resp.StatusCode != http.StatusOK {
-			body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
-			return fmt.Errorf("failed to download state: HTTP %d: %s", resp.StatusCode, string(body))
+			return err
 		}
+		defer state.Close()
 
 		outputPath := cliCmd.String(flagOutputFile.Name)
 		outputWriter := io.WriteCloser(os.Stdout)
@@ -67,14 +42,10 @@ func statePull() cli.ActionFunc {
 		}
 		defer outputWriter.Close()
 
-		if _, err := io.Copy(outputWriter, resp.Body); err != nil {
+		if _, err := io.Copy(outputWriter, state); err != nil {
 			return fmt.Errorf("failed to write state: %w", err)
 		}
 
 		return nil
 	}
 }
-
-type StateDownloadUrlInput struct { //nolint:staticcheck // type name must match GraphQL schema exactly
-	StackID graphql.ID `json:"stackId"`
-}
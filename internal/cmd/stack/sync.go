@@ -29,5 +29,5 @@ func syncCommit(ctx context.Context, cliCmd *cli.Command) error {
 		"stack": graphql.ID(stackID),
 	}
 
-	return authenticated.Client().Mutate(ctx, &mutation, variables)
+	return authenticated.Client(ctx).Mutate(ctx, &mutation, variables)
 }
@@ -14,7 +14,7 @@ import (
 )
 
 func taskList(ctx context.Context, cliCmd *cli.Command) error {
-	outputFormat, err := cmd.GetOutputFormat(cliCmd)
+	outputFormat, formatter, err := cmd.GetOutputFormat(cliCmd)
 	if err != nil {
 		return err
 	}
@@ -28,11 +28,9 @@ func taskList(ctx context.Context, cliCmd *cli.Command) error {
 	switch outputFormat {
 	case cmd.OutputFormatTable:
 		return listTasksTable(ctx, stackID, maxResults)
-	case cmd.OutputFormatJSON:
-		return listTasksJSON(ctx, stackID, maxResults)
+	default:
+		return listTasksJSON(ctx, formatter, stackID, maxResults)
 	}
-
-	return fmt.Errorf("unknown output format: %v", outputFormat)
 }
 
 type tasksJSONQuery struct {
@@ -74,7 +72,7 @@ func queryTasks[T any](ctx context.Context, stackID string, before *string) ([]T
 		} `graphql:"stack(id: $stackId)"`
 	}
 
-	if err := authenticated.Client().Query(ctx, &query, map[string]any{"stackId": stackID, "before": before}); err != nil {
+	if err := authenticated.Client(ctx).Query(ctx, &query, map[string]any{"stackId": stackID, "before": before}); err != nil {
 		return nil, errors.Wrap(err, "failed to query task list")
 	}
 
@@ -85,7 +83,7 @@ func queryTasks[T any](ctx context.Context, stackID string, before *string) ([]T
 	return query.Stack.Tasks, nil
 }
 
-func listTasksJSON(ctx context.Context, stackID string, maxResults int) error {
+func listTasksJSON(ctx context.Context, formatter cmd.OutputFormatter, stackID string, maxResults int) error {
 	results, err := fetchRuns(ctx, maxResults, func(ctx context.Context, before *string) ([]tasksJSONQuery, error) {
 		return queryTasks[tasksJSONQuery](ctx, stackID, before)
 	})
@@ -93,7 +91,7 @@ func listTasksJSON(ctx context.Context, stackID string, maxResults int) error {
 		return err
 	}
 
-	return cmd.OutputJSON(results)
+	return cmd.Output(formatter, results)
 }
 
 func listTasksTable(ctx context.Context, stackID string, maxResults int) error {
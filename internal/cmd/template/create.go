@@ -0,0 +1,44 @@
+package template
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/shurcooL/graphql"
+	"github.com/urfave/cli/v3"
+
+	"github.com/spacelift-io/spacectl/internal/cmd/authenticated"
+)
+
+func createTemplate(ctx context.Context, cliCmd *cli.Command) error {
+	name := cliCmd.String(flagRequiredName.Name)
+
+	input := TemplateCreateInput{
+		Name:   graphql.String(name),
+		Space:  graphql.ID(cliCmd.String(flagRequiredSpace.Name)),
+		Labels: []graphql.String{},
+	}
+
+	if cliCmd.IsSet(flagDescription.Name) {
+		input.Description = graphql.NewString(graphql.String(cliCmd.String(flagDescription.Name)))
+	}
+
+	for _, label := range cliCmd.StringSlice(flagLabels.Name) {
+		input.Labels = append(input.Labels, graphql.String(label))
+	}
+
+	var mutation struct {
+		TemplateCreate struct {
+			ID string `graphql:"id"`
+		} `graphql:"templateCreate(input: $input)"`
+	}
+
+	if err := authenticated.Client(ctx).Mutate(ctx, &mutation, map[string]any{"input": input}); err != nil {
+		return errors.Wrapf(err, "failed to create template %q", name)
+	}
+
+	fmt.Println(mutation.TemplateCreate.ID)
+
+	return nil
+}
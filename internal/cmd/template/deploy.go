@@ -0,0 +1,102 @@
+package template
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/shurcooL/graphql"
+	"github.com/urfave/cli/v3"
+
+	"github.com/spacelift-io/spacectl/internal/cmd/authenticated"
+	"github.com/spacelift-io/spacectl/internal/templateinputs"
+)
+
+func deployTemplate(ctx context.Context, cliCmd *cli.Command) error {
+	templateID := cliCmd.String(flagRequiredTemplateID.Name)
+	dryRun := cliCmd.Bool(flagDryRun.Name)
+
+	// Rendering the template needs neither, only deploying it does.
+	if !dryRun {
+		for _, flag := range []*cli.StringFlag{flagDeploymentName, flagDeploymentSpace} {
+			if cliCmd.String(flag.Name) == "" {
+				return fmt.Errorf("--%s is required unless --%s is set", flag.Name, flagDryRun.Name)
+			}
+		}
+	}
+
+	version, err := getTemplateVersion(ctx, templateID, cliCmd.String(flagRequiredVersion.Name))
+	if err != nil {
+		return err
+	}
+
+	inputs, err := readDeploymentInputs(cliCmd.String(flagInputFile.Name), cliCmd.StringSlice(flagSet.Name), version.Inputs)
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		rendered, unresolved := templateinputs.Render(version.Template, version.Inputs, inputs)
+		if len(unresolved) > 0 {
+			fmt.Fprintf(os.Stderr, "Warning: the following expressions are evaluated by Spacelift and were left as-is:\n  - %s\n\n", strings.Join(unresolved, "\n  - "))
+		}
+
+		_, err := fmt.Println(rendered)
+		return err
+	}
+
+	name := cliCmd.String(flagDeploymentName.Name)
+
+	var mutation struct {
+		TemplateDeploymentCreate struct {
+			ID string `graphql:"id"`
+		} `graphql:"templateDeploymentCreate(input: $input)"`
+	}
+
+	variables := map[string]any{
+		"input": TemplateDeploymentCreateInput{
+			TemplateVersionID: graphql.ID(version.ID),
+			Name:              graphql.String(name),
+			Space:             graphql.ID(cliCmd.String(flagDeploymentSpace.Name)),
+			Inputs:            inputs,
+		},
+	}
+
+	if err := authenticated.Client(ctx).Mutate(ctx, &mutation, variables); err != nil {
+		return errors.Wrapf(err, "failed to deploy version %s of template %q", version.Version, templateID)
+	}
+
+	fmt.Printf("Created deployment %q (%s) from version %s of template %q\n", name, mutation.TemplateDeploymentCreate.ID, version.Version, templateID)
+
+	return nil
+}
+
+// readDeploymentInputs reads template inputs from an optional file, applies the
+// key=value overrides on top of it and validates them against the definitions
+// of the template version.
+func readDeploymentInputs(filePath string, overrides []string, definitions []templateinputs.Definition) ([]templateinputs.Pair, error) {
+	values := make(map[string]json.RawMessage)
+	if filePath != "" {
+		var err error
+		if values, err = templateinputs.ReadFile(filePath); err != nil {
+			return nil, err
+		}
+	}
+
+	overrideValues, err := templateinputs.ParseOverrides(overrides)
+	if err != nil {
+		return nil, err
+	}
+	maps.Copy(values, overrideValues)
+
+	result, errs := templateinputs.Validate(values, definitions, "template")
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("inputs validation failed:\n%s", strings.Join(errs, "\n"))
+	}
+
+	return result, nil
+}
@@ -0,0 +1,40 @@
+package template
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+
+	"github.com/spacelift-io/spacectl/internal/templateinputs"
+)
+
+func TestDeployTemplateRequiresNameAndSpace(t *testing.T) {
+	command := &cli.Command{
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: flagRequiredTemplateID.Name},
+			&cli.StringFlag{Name: flagDeploymentName.Name},
+			&cli.StringFlag{Name: flagDeploymentSpace.Name},
+			&cli.BoolFlag{Name: flagDryRun.Name},
+		},
+		Action: deployTemplate,
+	}
+
+	err := command.Run(t.Context(), []string{"deploy", "--template-id", "tpl", "--space", "root"})
+	assert.EqualError(t, err, "--name is required unless --dry-run is set")
+
+	err = command.Run(t.Context(), []string{"deploy", "--template-id", "tpl", "--name", "prod"})
+	assert.EqualError(t, err, "--space is required unless --dry-run is set")
+}
+
+func TestReadDeploymentInputsNullFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "inputs.json")
+	require.NoError(t, os.WriteFile(path, []byte("null"), 0600))
+
+	got, err := readDeploymentInputs(path, []string{"env=prod"}, []templateinputs.Definition{{ID: "env", Name: "Environment", Type: "short_text"}})
+	require.NoError(t, err)
+	assert.Equal(t, []templateinputs.Pair{{ID: "env", Value: "prod"}}, got)
+}
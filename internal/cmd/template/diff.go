@@ -0,0 +1,106 @@
+package template
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/urfave/cli/v3"
+)
+
+func diffTemplateVersions(ctx context.Context, cliCmd *cli.Command) error {
+	templateID := cliCmd.String(flagRequiredTemplateID.Name)
+
+	if cliCmd.IsSet(flagTo.Name) && cliCmd.IsSet(flagToFile.Name) {
+		return fmt.Errorf("only one of --%s and --%s can be provided", flagTo.Name, flagToFile.Name)
+	}
+
+	from, err := getTemplateVersion(ctx, templateID, cliCmd.String(flagRequiredFrom.Name))
+	if err != nil {
+		return err
+	}
+
+	fromLabel := fmt.Sprintf("%s@%s", templateID, from.Version)
+
+	var toBody, toLabel string
+	if path := cliCmd.String(flagToFile.Name); path != "" {
+		if toBody, err = readTemplateBody(path); err != nil {
+			return err
+		}
+		toLabel = path
+	} else {
+		ref := cliCmd.String(flagTo.Name)
+		if ref == "" {
+			t, found, err := getTemplateByID(ctx, templateID)
+			if err != nil {
+				return err
+			}
+
+			if !found {
+				return fmt.Errorf("template with ID %q not found", templateID)
+			}
+
+			if t.LatestVersion == nil {
+				return fmt.Errorf("template %q has no versions", templateID)
+			}
+
+			ref = t.LatestVersion.ID
+		}
+
+		to, err := getTemplateVersion(ctx, templateID, ref)
+		if err != nil {
+			return err
+		}
+
+		toBody, toLabel = to.Template, fmt.Sprintf("%s@%s", templateID, to.Version)
+	}
+
+	diff, err := unifiedDiff(from.Template, toBody, fromLabel, toLabel)
+	if err != nil {
+		return err
+	}
+
+	if diff == "" {
+		fmt.Println("No differences found")
+		return nil
+	}
+
+	_, err = fmt.Print(diff)
+	return err
+}
+
+// unifiedDiff returns the unified diff between two template bodies, or an empty
+// string if they are identical.
+func unifiedDiff(from, to, fromLabel, toLabel string) (string, error) {
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitLines(from),
+		B:        splitLines(to),
+		FromFile: fromLabel,
+		ToFile:   toLabel,
+		Context:  3,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to compute the diff: %w", err)
+	}
+
+	return diff, nil
+}
+
+// splitLines splits the text into lines keeping their line endings. Unlike
+// difflib.SplitLines, it does not produce an extra empty line when the text
+// ends with a newline.
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	} else {
+		lines[len(lines)-1] += "\n"
+	}
+
+	return lines
+}
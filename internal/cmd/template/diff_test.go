@@ -0,0 +1,22 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	t.Run("identical bodies", func(t *testing.T) {
+		diff, err := unifiedDiff("a: 1\n", "a: 1\n", "from", "to")
+		require.NoError(t, err)
+		assert.Empty(t, diff)
+	})
+
+	t.Run("changed line", func(t *testing.T) {
+		diff, err := unifiedDiff("a: 1\nb: 2\n", "a: 1\nb: 3\n", "tpl@1.0.0", "tpl@1.1.0")
+		require.NoError(t, err)
+		assert.Equal(t, "--- tpl@1.0.0\n+++ tpl@1.1.0\n@@ -1,2 +1,2 @@\n a: 1\n-b: 2\n+b: 3\n", diff)
+	})
+}
@@ -8,3 +8,94 @@ var flagRequiredTemplateID = &cli.StringFlag{
 	Usage:    "[Required] `ID` of the template",
 	Required: true,
 }
+
+var flagRequiredName = &cli.StringFlag{
+	Name:     "name",
+	Usage:    "[Required] `NAME` of the template",
+	Required: true,
+}
+
+var flagRequiredSpace = &cli.StringFlag{
+	Name:     "space",
+	Usage:    "[Required] `ID` of the space",
+	Required: true,
+}
+
+var flagDescription = &cli.StringFlag{
+	Name:  "description",
+	Usage: "[Optional] Description of the template",
+}
+
+var flagLabels = &cli.StringSliceFlag{
+	Name:  "label",
+	Usage: "[Optional] Label to attach, can be specified multiple times",
+}
+
+var flagRequiredFile = &cli.StringFlag{
+	Name:      "file",
+	Aliases:   []string{"f"},
+	Usage:     "[Required] `PATH` to the template body file",
+	Required:  true,
+	TakesFile: true,
+}
+
+var flagRequiredVersion = &cli.StringFlag{
+	Name:     "version",
+	Usage:    "[Required] `VERSION` of the template, either its ID or its version number",
+	Required: true,
+}
+
+var flagRequiredVersionNumber = &cli.StringFlag{
+	Name:     "version-number",
+	Usage:    "[Required] Version number of the new template version, example: 1.2.0",
+	Required: true,
+}
+
+var flagPublish = &cli.BoolFlag{
+	Name:  "publish",
+	Usage: "[Optional] Publish the version right after creating it",
+}
+
+var flagRequiredFrom = &cli.StringFlag{
+	Name:     "from",
+	Usage:    "[Required] `VERSION` to compare from, either its ID or its version number",
+	Required: true,
+}
+
+var flagTo = &cli.StringFlag{
+	Name:  "to",
+	Usage: "[Optional] `VERSION` to compare to, either its ID or its version number (defaults to the latest version)",
+}
+
+var flagToFile = &cli.StringFlag{
+	Name:      "to-file",
+	Usage:     "[Optional] `PATH` to a local template body to compare to, instead of a version",
+	TakesFile: true,
+}
+
+var flagDeploymentName = &cli.StringFlag{
+	Name:  "name",
+	Usage: "[Required unless --dry-run] `NAME` of the deployment",
+}
+
+var flagDeploymentSpace = &cli.StringFlag{
+	Name:  "space",
+	Usage: "[Required unless --dry-run] `ID` of the space to deploy to",
+}
+
+var flagInputFile = &cli.StringFlag{
+	Name:      "input-file",
+	Aliases:   []string{"if"},
+	Usage:     "[Optional] Load template inputs from the JSON or YAML `FILE`",
+	TakesFile: true,
+}
+
+var flagSet = &cli.StringSliceFlag{
+	Name:  "set",
+	Usage: "[Optional] Set a template input, overriding the input file, example: --set environment=prod --set region=eu-west-1",
+}
+
+var flagDryRun = &cli.BoolFlag{
+	Name:  "dry-run",
+	Usage: "[Optional] Validate the inputs and print the rendered template without deploying it",
+}
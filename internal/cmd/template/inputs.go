@@ -0,0 +1,35 @@
+package template
+
+import (
+	"github.com/shurcooL/graphql"
+
+	"github.com/spacelift-io/spacectl/internal/templateinputs"
+)
+
+// TemplateCreateInput represents the input for creating a new template.
+type TemplateCreateInput struct {
+	Name        graphql.String   `json:"name"`
+	Space       graphql.ID       `json:"space"`
+	Description *graphql.String  `json:"description"`
+	Labels      []graphql.String `json:"labels"`
+}
+
+// TemplateVersionCreateInput represents the input for creating a new template version.
+type TemplateVersionCreateInput struct {
+	TemplateID graphql.ID     `json:"templateId"`
+	Version    graphql.String `json:"version"`
+	Template   graphql.String `json:"template"`
+}
+
+// TemplateVersionUpdateInput represents the input for updating a draft template version.
+type TemplateVersionUpdateInput struct {
+	Template graphql.String `json:"template"`
+}
+
+// TemplateDeploymentCreateInput represents the input for deploying a template version.
+type TemplateDeploymentCreateInput struct {
+	TemplateVersionID graphql.ID            `json:"templateVersionId"`
+	Name              graphql.String        `json:"name"`
+	Space             graphql.ID            `json:"space"`
+	Inputs            []templateinputs.Pair `json:"inputs"`
+}
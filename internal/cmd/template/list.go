@@ -2,7 +2,7 @@ package template
 
 import (
 	"context"
-	"fmt"
+	"iter"
 	"strconv"
 	"strings"
 
@@ -10,6 +10,7 @@ import (
 	"github.com/shurcooL/graphql"
 	"github.com/urfave/cli/v3"
 
+	"github.com/spacelift-io/spacectl/client"
 	"github.com/spacelift-io/spacectl/client/structs"
 	"github.com/spacelift-io/spacectl/internal/cmd"
 	"github.com/spacelift-io/spacectl/internal/cmd/authenticated"
@@ -47,79 +48,41 @@ type searchTemplatesOutput struct {
 }
 
 func listTemplates(ctx context.Context, cliCmd *cli.Command) error {
-	outputFormat, err := cmd.GetOutputFormat(cliCmd)
+	outputFormat, formatter, err := cmd.GetOutputFormat(cliCmd)
 	if err != nil {
 		return err
 	}
 
-	input := structs.SearchInput{
-		OrderBy: &structs.QueryOrder{
-			Field:     "name",
-			Direction: "DESC",
-		},
-	}
-
-	if cliCmd.IsSet(cmd.FlagLimit.Name) {
-		//nolint:gosec // Flag value has been pre-validated.
-		input.First = new(graphql.Int(cliCmd.Uint(cmd.FlagLimit.Name)))
+	opts, err := cmd.GetPaginateOptions(cliCmd)
+	if err != nil {
+		return err
 	}
 
+	var input structs.SearchInput
 	if cliCmd.IsSet(cmd.FlagSearch.Name) {
 		input.FullTextSearch = new(graphql.String(cliCmd.String(cmd.FlagSearch.Name)))
 	}
 
-	templates, err := searchAllTemplates(ctx, input)
-	if err != nil {
-		return err
-	}
-
 	switch outputFormat {
 	case cmd.OutputFormatTable:
-		return printTemplateTable(templates, cliCmd.Bool(cmd.FlagShowLabels.Name))
-	case cmd.OutputFormatJSON:
-		return cmd.OutputJSON(templates)
-	default: // Shouldn't happen as outputFormat is pre-validated.
-		return fmt.Errorf("unknown output format: %v", outputFormat)
-	}
-}
-
-// searchAllTemplates returns a list of templates based on the provided search input.
-// input.First limits the total number of returned templates, if not provided all templates are returned.
-func searchAllTemplates(ctx context.Context, input structs.SearchInput) ([]templateNode, error) {
-	limit := 0
-	if input.First != nil {
-		limit = int(*input.First)
-	}
-	fetchAll := limit == 0
-
-	out := make([]templateNode, 0, maxPageSize)
-	pageInput := structs.SearchInput{
-		First:          new(graphql.Int(maxPageSize)),
-		FullTextSearch: input.FullTextSearch,
-	}
-
-	for {
-		if !fetchAll {
-			// Fetch exactly the number of items requested
-			//nolint:gosec // Flag value has been pre-validated.
-			pageInput.First = new(graphql.Int(min(maxPageSize, limit-len(out))))
-		}
-
-		result, err := searchTemplates(ctx, pageInput)
-		if err != nil {
-			return nil, err
+		input.OrderBy = &structs.QueryOrder{
+			Field:     "name",
+			Direction: "DESC",
 		}
 
-		out = append(out, result.Templates...)
-
-		if result.PageInfo.HasNextPage && (fetchAll || limit > len(out)) {
-			pageInput.After = new(graphql.String(result.PageInfo.EndCursor))
-		} else {
-			break
-		}
+		return printTemplateTable(paginateTemplates(ctx, input, opts), cliCmd.Bool(cmd.FlagShowLabels.Name))
+	default:
+		return cmd.OutputStream(outputFormat, formatter, paginateTemplates(ctx, input, opts))
 	}
+}
 
-	return out, nil
+// paginateTemplates iterates over the templates matching the input, fetching
+// pages as they are consumed.
+func paginateTemplates(ctx context.Context, input structs.SearchInput, opts client.PaginateOptions) iter.Seq2[templateNode, error] {
+	return client.Paginate(ctx, input, opts, func(ctx context.Context, input structs.SearchInput) (client.Page[templateNode], error) {
+		result, err := searchTemplates(ctx, input)
+		return client.Page[templateNode]{Items: result.Templates, PageInfo: result.PageInfo}, err
+	})
 }
 
 func searchTemplates(
@@ -129,7 +92,7 @@ func searchTemplates(
 		SearchTemplatesOutput searchTemplatesOutput `graphql:"searchTemplates(input: $input)"`
 	}
 
-	if err := authenticated.Client().Query(
+	if err := authenticated.Client(ctx).Query(
 		ctx, &query, map[string]any{"input": input},
 	); err != nil {
 		return searchTemplatesResult{}, errors.Wrap(err, "failed search for templates")
@@ -146,14 +109,18 @@ func searchTemplates(
 	}, nil
 }
 
-func printTemplateTable(templates []templateNode, showLabels bool) error {
+func printTemplateTable(templates iter.Seq2[templateNode, error], showLabels bool) error {
 	columns := []string{"Name", "ID", "Description", "Space", "Deployments", "Updated At"}
 	if showLabels {
 		columns = append(columns, "Labels")
 	}
 
 	tableData := [][]string{columns}
-	for _, t := range templates {
+	for t, err := range templates {
+		if err != nil {
+			return err
+		}
+
 		row := []string{
 			t.Name,
 			t.ID,
@@ -27,7 +27,7 @@ type templateDetail struct {
 func showTemplate(ctx context.Context, cliCmd *cli.Command) error {
 	templateID := cliCmd.String(flagRequiredTemplateID.Name)
 
-	outputFormat, err := cmd.GetOutputFormat(cliCmd)
+	outputFormat, formatter, err := cmd.GetOutputFormat(cliCmd)
 	if err != nil {
 		return err
 	}
@@ -44,11 +44,9 @@ func showTemplate(ctx context.Context, cliCmd *cli.Command) error {
 	switch outputFormat {
 	case cmd.OutputFormatTable:
 		return showTemplateTable(t)
-	case cmd.OutputFormatJSON:
-		return cmd.OutputJSON(t)
+	default:
+		return cmd.Output(formatter, t)
 	}
-
-	return fmt.Errorf("unknown output format: %v", outputFormat)
 }
 
 func showTemplateTable(t templateDetail) error {
@@ -115,7 +113,7 @@ func getTemplateByID(ctx context.Context, templateID string) (templateDetail, bo
 		"templateId": graphql.ID(templateID),
 	}
 
-	if err := authenticated.Client().Query(ctx, &query, variables); err != nil {
+	if err := authenticated.Client(ctx).Query(ctx, &query, variables); err != nil {
 		return templateDetail{}, false, errors.Wrapf(err, "failed to query for template ID %q", templateID)
 	}
 
@@ -35,6 +35,7 @@ func Command() cmd.Command {
 								cmd.FlagOutputFormat,
 								cmd.FlagNoColor,
 								cmd.FlagLimit,
+								cmd.FlagPageSize,
 								cmd.FlagSearch,
 							},
 							Action: listTemplates,
@@ -68,6 +69,157 @@ func Command() cmd.Command {
 					},
 				},
 			},
+			{
+				Name:  "create",
+				Usage: "Create a new template and print its ID",
+				Versions: []cmd.VersionedCommand{
+					{
+						EarliestVersion: cmd.SupportedVersionLatest,
+						Command: &cli.Command{
+							Flags: []cli.Flag{
+								flagRequiredName,
+								flagRequiredSpace,
+								flagDescription,
+								flagLabels,
+							},
+							Action:    createTemplate,
+							Before:    authenticated.Ensure,
+							ArgsUsage: cmd.EmptyArgsUsage,
+						},
+					},
+				},
+			},
+			{
+				Name:  "version",
+				Usage: "Manage the versions of a template",
+				Versions: []cmd.VersionedCommand{
+					{
+						EarliestVersion: cmd.SupportedVersionLatest,
+						Command:         &cli.Command{},
+					},
+				},
+				Subcommands: []cmd.Command{
+					{
+						Name:  "list",
+						Usage: "List the versions of a template",
+						Versions: []cmd.VersionedCommand{
+							{
+								EarliestVersion: cmd.SupportedVersionLatest,
+								Command: &cli.Command{
+									Flags: []cli.Flag{
+										flagRequiredTemplateID,
+										cmd.FlagOutputFormat,
+										cmd.FlagNoColor,
+									},
+									Action:    listTemplateVersions,
+									Before:    cmd.PerformAllBefore(cmd.HandleNoColor, authenticated.Ensure),
+									ArgsUsage: cmd.EmptyArgsUsage,
+								},
+							},
+						},
+					},
+					{
+						Name:  "create",
+						Usage: "Create a new version of a template from a local file",
+						Versions: []cmd.VersionedCommand{
+							{
+								EarliestVersion: cmd.SupportedVersionLatest,
+								Command: &cli.Command{
+									Flags: []cli.Flag{
+										flagRequiredTemplateID,
+										flagRequiredVersionNumber,
+										flagRequiredFile,
+										flagPublish,
+									},
+									Action:    createTemplateVersion,
+									Before:    authenticated.Ensure,
+									ArgsUsage: cmd.EmptyArgsUsage,
+								},
+							},
+						},
+					},
+					{
+						Name:  "update",
+						Usage: "Replace the body of a draft template version with a local file",
+						Versions: []cmd.VersionedCommand{
+							{
+								EarliestVersion: cmd.SupportedVersionLatest,
+								Command: &cli.Command{
+									Flags: []cli.Flag{
+										flagRequiredTemplateID,
+										flagRequiredVersion,
+										flagRequiredFile,
+									},
+									Action:    updateTemplateVersion,
+									Before:    authenticated.Ensure,
+									ArgsUsage: cmd.EmptyArgsUsage,
+								},
+							},
+						},
+					},
+					{
+						Name:  "publish",
+						Usage: "Publish a draft template version, making it available for deployments",
+						Versions: []cmd.VersionedCommand{
+							{
+								EarliestVersion: cmd.SupportedVersionLatest,
+								Command: &cli.Command{
+									Flags: []cli.Flag{
+										flagRequiredTemplateID,
+										flagRequiredVersion,
+									},
+									Action:    publishTemplateVersion,
+									Before:    authenticated.Ensure,
+									ArgsUsage: cmd.EmptyArgsUsage,
+								},
+							},
+						},
+					},
+				},
+			},
+			{
+				Name:  "diff",
+				Usage: "Show the differences between two versions of a template, or a version and a local file",
+				Versions: []cmd.VersionedCommand{
+					{
+						EarliestVersion: cmd.SupportedVersionLatest,
+						Command: &cli.Command{
+							Flags: []cli.Flag{
+								flagRequiredTemplateID,
+								flagRequiredFrom,
+								flagTo,
+								flagToFile,
+							},
+							Action:    diffTemplateVersions,
+							Before:    authenticated.Ensure,
+							ArgsUsage: cmd.EmptyArgsUsage,
+						},
+					},
+				},
+			},
+			{
+				Name:  "deploy",
+				Usage: "Deploy a version of a template",
+				Versions: []cmd.VersionedCommand{
+					{
+						EarliestVersion: cmd.SupportedVersionLatest,
+						Command: &cli.Command{
+							Flags: []cli.Flag{
+								flagRequiredTemplateID,
+								flagRequiredVersion,
+								flagDeploymentName,
+								flagDeploymentSpace,
+								flagInputFile,
+								flagSet,
+								flagDryRun,
+							},
+							Action:    deployTemplate,
+							Before:    authenticated.Ensure,
+							ArgsUsage: cmd.EmptyArgsUsage,
+						},
+					},
+				},
+			},
 		},
 	}
 }
@@ -0,0 +1,219 @@
+package template
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/shurcooL/graphql"
+	"github.com/urfave/cli/v3"
+
+	"github.com/spacelift-io/spacectl/internal/cmd"
+	"github.com/spacelift-io/spacectl/internal/cmd/authenticated"
+	"github.com/spacelift-io/spacectl/internal/templateinputs"
+)
+
+type templateVersionNode struct {
+	ID        string `graphql:"id" json:"id,omitempty"`
+	Version   string `graphql:"version" json:"version,omitempty"`
+	State     string `graphql:"state" json:"state,omitempty"`
+	CreatedAt int    `graphql:"createdAt" json:"createdAt,omitempty"`
+	UpdatedAt int    `graphql:"updatedAt" json:"updatedAt,omitempty"`
+}
+
+type templateVersionDetail struct {
+	templateVersionNode
+	Template string                      `graphql:"template" json:"template,omitempty"`
+	Inputs   []templateinputs.Definition `graphql:"inputs" json:"inputs,omitempty"`
+}
+
+func listTemplateVersions(ctx context.Context, cliCmd *cli.Command) error {
+	templateID := cliCmd.String(flagRequiredTemplateID.Name)
+
+	outputFormat, formatter, err := cmd.GetOutputFormat(cliCmd)
+	if err != nil {
+		return err
+	}
+
+	versions, err := getTemplateVersions(ctx, templateID)
+	if err != nil {
+		return err
+	}
+
+	switch outputFormat {
+	case cmd.OutputFormatTable:
+		tableData := [][]string{{"Version", "ID", "State", "Created At", "Updated At"}}
+		for _, v := range versions {
+			tableData = append(tableData, []string{
+				v.Version,
+				v.ID,
+				v.State,
+				cmd.HumanizeUnixSeconds(v.CreatedAt),
+				cmd.HumanizeUnixSeconds(v.UpdatedAt),
+			})
+		}
+
+		return cmd.OutputTable(tableData, true)
+	default:
+		return cmd.Output(formatter, versions)
+	}
+}
+
+func createTemplateVersion(ctx context.Context, cliCmd *cli.Command) error {
+	templateID := cliCmd.String(flagRequiredTemplateID.Name)
+
+	body, err := readTemplateBody(cliCmd.String(flagRequiredFile.Name))
+	if err != nil {
+		return err
+	}
+
+	var mutation struct {
+		TemplateVersionCreate templateVersionNode `graphql:"templateVersionCreate(input: $input)"`
+	}
+
+	variables := map[string]any{
+		"input": TemplateVersionCreateInput{
+			TemplateID: graphql.ID(templateID),
+			Version:    graphql.String(cliCmd.String(flagRequiredVersionNumber.Name)),
+			Template:   graphql.String(body),
+		},
+	}
+
+	if err := authenticated.Client(ctx).Mutate(ctx, &mutation, variables); err != nil {
+		return errors.Wrapf(err, "failed to create a version of template %q", templateID)
+	}
+
+	version := mutation.TemplateVersionCreate
+	fmt.Printf("Created version %s (%s) of template %q\n", version.Version, version.ID, templateID)
+
+	if !cliCmd.Bool(flagPublish.Name) {
+		return nil
+	}
+
+	return publishVersion(ctx, templateID, version.ID)
+}
+
+func updateTemplateVersion(ctx context.Context, cliCmd *cli.Command) error {
+	templateID := cliCmd.String(flagRequiredTemplateID.Name)
+
+	version, err := findTemplateVersion(ctx, templateID, cliCmd.String(flagRequiredVersion.Name))
+	if err != nil {
+		return err
+	}
+
+	body, err := readTemplateBody(cliCmd.String(flagRequiredFile.Name))
+	if err != nil {
+		return err
+	}
+
+	var mutation struct {
+		TemplateVersionUpdate templateVersionNode `graphql:"templateVersionUpdate(id: $id, input: $input)"`
+	}
+
+	variables := map[string]any{
+		"id":    graphql.ID(version.ID),
+		"input": TemplateVersionUpdateInput{Template: graphql.String(body)},
+	}
+
+	if err := authenticated.Client(ctx).Mutate(ctx, &mutation, variables); err != nil {
+		return errors.Wrapf(err, "failed to update version %s of template %q", version.Version, templateID)
+	}
+
+	fmt.Printf("Updated version %s (%s) of template %q\n", version.Version, version.ID, templateID)
+
+	return nil
+}
+
+func publishTemplateVersion(ctx context.Context, cliCmd *cli.Command) error {
+	templateID := cliCmd.String(flagRequiredTemplateID.Name)
+
+	version, err := findTemplateVersion(ctx, templateID, cliCmd.String(flagRequiredVersion.Name))
+	if err != nil {
+		return err
+	}
+
+	return publishVersion(ctx, templateID, version.ID)
+}
+
+func publishVersion(ctx context.Context, templateID, versionID string) error {
+	var mutation struct {
+		TemplateVersionPublish templateVersionNode `graphql:"templateVersionPublish(id: $id)"`
+	}
+
+	if err := authenticated.Client(ctx).Mutate(ctx, &mutation, map[string]any{"id": graphql.ID(versionID)}); err != nil {
+		return errors.Wrapf(err, "failed to publish version %q of template %q", versionID, templateID)
+	}
+
+	version := mutation.TemplateVersionPublish
+	fmt.Printf("Published version %s (%s) of template %q\n", version.Version, version.ID, templateID)
+
+	return nil
+}
+
+func getTemplateVersions(ctx context.Context, templateID string) ([]templateVersionNode, error) {
+	var query struct {
+		Template *struct {
+			Versions []templateVersionNode `graphql:"versions"`
+		} `graphql:"template(id: $templateId)"`
+	}
+
+	if err := authenticated.Client(ctx).Query(ctx, &query, map[string]any{"templateId": graphql.ID(templateID)}); err != nil {
+		return nil, errors.Wrapf(err, "failed to query versions of template %q", templateID)
+	}
+
+	if query.Template == nil {
+		return nil, fmt.Errorf("template with ID %q not found", templateID)
+	}
+
+	return query.Template.Versions, nil
+}
+
+// findTemplateVersion finds a version of the template by its ID or its
+// version number.
+func findTemplateVersion(ctx context.Context, templateID, ref string) (templateVersionNode, error) {
+	versions, err := getTemplateVersions(ctx, templateID)
+	if err != nil {
+		return templateVersionNode{}, err
+	}
+
+	for _, v := range versions {
+		if v.ID == ref || v.Version == ref {
+			return v, nil
+		}
+	}
+
+	return templateVersionNode{}, fmt.Errorf("version %q of template %q not found", ref, templateID)
+}
+
+// getTemplateVersion returns the full details of a template version, found by
+// its ID or its version number.
+func getTemplateVersion(ctx context.Context, templateID, ref string) (templateVersionDetail, error) {
+	version, err := findTemplateVersion(ctx, templateID, ref)
+	if err != nil {
+		return templateVersionDetail{}, err
+	}
+
+	var query struct {
+		TemplateVersion *templateVersionDetail `graphql:"templateVersion(id: $id)"`
+	}
+
+	if err := authenticated.Client(ctx).Query(ctx, &query, map[string]any{"id": graphql.ID(version.ID)}); err != nil {
+		return templateVersionDetail{}, errors.Wrapf(err, "failed to query version %s of template %q", version.Version, templateID)
+	}
+
+	if query.TemplateVersion == nil {
+		return templateVersionDetail{}, fmt.Errorf("version %q of template %q not found", ref, templateID)
+	}
+
+	return *query.TemplateVersion, nil
+}
+
+func readTemplateBody(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read template file %q: %w", path, err)
+	}
+
+	return string(data), nil
+}
@@ -71,7 +71,7 @@ func searchWorkerPools(ctx context.Context, input structs.SearchInput) (*searchW
 
 	variables := map[string]any{"input": input}
 
-	if err := authenticated.Client().Query(ctx, &query, variables); err != nil {
+	if err := authenticated.Client(ctx).Query(ctx, &query, variables); err != nil {
 		return nil, errors.Wrap(err, "failed to execute worker pools search query")
 	}
 
@@ -123,6 +123,11 @@ func registerListWorkerPoolsTool(s *server.MCPServer) {
 			return nil, errors.Wrap(err, "failed to search worker pools")
 		}
 
+		result.WorkerPools, err = authenticated.FilterBySpace(ctx, result.WorkerPools, func(p workerPoolNode) string { return p.Space.ID })
+		if err != nil {
+			return nil, err
+		}
+
 		poolsJSON, err := json.Marshal(result.WorkerPools)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to marshal worker pools to JSON")
@@ -171,7 +176,7 @@ func registerGetWorkerPoolTool(s *server.MCPServer) {
 			"workerPoolId": graphql.ID(poolID),
 		}
 
-		if err := authenticated.Client().Query(ctx, &query, variables); err != nil {
+		if err := authenticated.Client(ctx).Query(ctx, &query, variables); err != nil {
 			return nil, errors.Wrapf(err, "failed to query for worker pool ID %q", poolID)
 		}
 
@@ -39,7 +39,7 @@ type listPoolsQuery struct {
 type listPoolsCommand struct{}
 
 func (c *listPoolsCommand) listPools(ctx context.Context, cliCmd *cli.Command) error {
-	outputFormat, err := cmd.GetOutputFormat(cliCmd)
+	outputFormat, formatter, err := cmd.GetOutputFormat(cliCmd)
 
 	if err != nil {
 		return err
@@ -47,17 +47,15 @@ func (c *listPoolsCommand) listPools(ctx context.Context, cliCmd *cli.Command) e
 
 	var query listPoolsQuery
 
-	if err := authenticated.Client().Query(ctx, &query, map[string]any{}); err != nil {
+	if err := authenticated.Client(ctx).Query(ctx, &query, map[string]any{}); err != nil {
 		return err
 	}
 
 	switch outputFormat {
 	case cmd.OutputFormatTable:
 		return c.showOutputsTable(query.Pools)
-	case cmd.OutputFormatJSON:
-		return c.showOutputsJSON(query.Pools)
 	default:
-		return fmt.Errorf("unknown output format: %v", outputFormat)
+		return c.showOutputsJSON(formatter, query.Pools)
 	}
 }
 
@@ -90,13 +88,13 @@ func (c *listPoolsCommand) showOutputsTable(pools []pool) error {
 	return cmd.OutputTable(tableData, true)
 }
 
-func (c *listPoolsCommand) showOutputsJSON(pools []pool) error {
+func (c *listPoolsCommand) showOutputsJSON(formatter cmd.OutputFormatter, pools []pool) error {
 	var output []poolJSONOutput
 	for _, pool := range pools {
 		row := pool.toJSONOutput()
 		output = append(output, row)
 	}
-	return cmd.OutputJSON(output)
+	return cmd.Output(formatter, output)
 }
 
 func (p *pool) toJSONOutput() poolJSONOutput {
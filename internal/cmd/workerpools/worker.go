@@ -50,7 +50,7 @@ type drainWorkerCommand struct{}
 type undrainWorkerCommand struct{}
 
 func (c *listWorkersCommand) listWorkers(ctx context.Context, cliCmd *cli.Command) error {
-	outputFormat, err := cmd.GetOutputFormat(cliCmd)
+	outputFormat, formatter, err := cmd.GetOutputFormat(cliCmd)
 
 	if err != nil {
 		return err
@@ -63,7 +63,7 @@ func (c *listWorkersCommand) listWorkers(ctx context.Context, cliCmd *cli.Comman
 		"workerPool": workerPoolID,
 	}
 
-	if err := authenticated.Client().Query(ctx, &query, variables); err != nil {
+	if err := authenticated.Client(ctx).Query(ctx, &query, variables); err != nil {
 		return err
 	}
 
@@ -74,14 +74,12 @@ func (c *listWorkersCommand) listWorkers(ctx context.Context, cliCmd *cli.Comman
 	switch outputFormat {
 	case cmd.OutputFormatTable:
 		return c.showOutputsTable(query.Pool.Workers)
-	case cmd.OutputFormatJSON:
-		return c.showOutputsJSON(query.Pool.Workers)
 	default:
-		return fmt.Errorf("unknown output format: %v", outputFormat)
+		return c.showOutputsJSON(formatter, query.Pool.Workers)
 	}
 }
 
-func (c *listWorkersCommand) showOutputsJSON(workers []worker) error {
+func (c *listWorkersCommand) showOutputsJSON(formatter cmd.OutputFormatter, workers []worker) error {
 	var output []any
 	for _, worker := range workers {
 		var parsedMetadata map[string]any
@@ -98,7 +96,7 @@ func (c *listWorkersCommand) showOutputsJSON(workers []worker) error {
 		}
 		output = append(output, row)
 	}
-	return cmd.OutputJSON(output)
+	return cmd.Output(formatter, output)
 }
 
 func (c *listWorkersCommand) showOutputsTable(workers []worker) error {
@@ -126,7 +124,7 @@ func (c *drainWorkerCommand) drainWorker(ctx context.Context, cliCmd *cli.Comman
 		"drain":      graphql.Boolean(true),
 	}
 
-	if err := authenticated.Client().Mutate(ctx, &mutation, variables); err != nil {
+	if err := authenticated.Client(ctx).Mutate(ctx, &mutation, variables); err != nil {
 		return err
 	}
 
@@ -180,7 +178,7 @@ func (c *drainWorkerCommand) drainedWorkerIsIdle(ctx context.Context, workerID s
 		"workerPool": graphql.ID(workerPoolID),
 	}
 
-	if err := authenticated.Client().Query(ctx, &query, variables); err != nil {
+	if err := authenticated.Client(ctx).Query(ctx, &query, variables); err != nil {
 		return false, err
 	}
 
@@ -215,7 +213,7 @@ func (c *undrainWorkerCommand) undrainWorker(ctx context.Context, cliCmd *cli.Co
 		"drain":      graphql.Boolean(false),
 	}
 
-	err := authenticated.Client().Mutate(ctx, &mutation, variables)
+	err := authenticated.Client(ctx).Mutate(ctx, &mutation, variables)
 
 	if err != nil {
 		return err
@@ -232,7 +230,7 @@ func (c *cycleWorkersCommand) cycleWorkers(ctx context.Context, cliCmd *cli.Comm
 		"workerPoolId": graphql.ID(cliCmd.String(flagPoolIDNamed.Name)),
 	}
 
-	err := authenticated.Client().Mutate(ctx, &mutation, variables)
+	err := authenticated.Client(ctx).Mutate(ctx, &mutation, variables)
 
 	if err != nil {
 		return err
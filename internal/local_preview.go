@@ -11,6 +11,8 @@ import (
 
 	"github.com/cheggaaa/pb/v3"
 	ignore "github.com/sabhiram/go-gitignore"
+
+	"github.com/spacelift-io/spacectl/client"
 )
 
 // MoveToRepositoryRoot moves the current workdir to the git repository root.
@@ -190,7 +192,7 @@ func UploadArchive(ctx context.Context, uploadURL, path string, uploadHeaders ma
 		req.Header.Set(k, v)
 	}
 
-	response, err := http.DefaultClient.Do(req)
+	response, err := client.GetTransferHTTPClient().Do(req)
 	if err != nil {
 		return fmt.Errorf("couldn't upload workspace: %w", err)
 	}
@@ -7,8 +7,7 @@ import (
 	"slices"
 	"time"
 
-	"github.com/shurcooL/graphql"
-
+	"github.com/spacelift-io/spacectl/client/runs"
 	"github.com/spacelift-io/spacectl/client/structs"
 	"github.com/spacelift-io/spacectl/internal/cmd/authenticated"
 )
@@ -91,32 +90,7 @@ func (e *Explorer) RunFilteredStates(ctx context.Context, sink chan<- string) (*
 }
 
 func (e *Explorer) getHistory(ctx context.Context) ([]structs.RunStateTransition, error) {
-	var query struct {
-		Stack *struct {
-			Run *struct {
-				History []structs.RunStateTransition `graphql:"history"`
-			} `graphql:"run(id: $run)"`
-		} `graphql:"stack(id: $stack)"`
-	}
-
-	variables := map[string]any{
-		"stack": graphql.ID(e.stack),
-		"run":   graphql.ID(e.run),
-	}
-
-	if err := authenticated.Client().Query(ctx, &query, variables); err != nil {
-		return nil, err
-	}
-
-	if query.Stack == nil {
-		return nil, fmt.Errorf("stack %q not found", e.stack)
-	}
-
-	if query.Stack.Run == nil {
-		return nil, fmt.Errorf("run %q in stack %q not found", e.run, e.stack)
-	}
-
-	return query.Stack.Run.History, nil
+	return runs.New(authenticated.Client(ctx)).History(ctx, e.stack, e.run)
 }
 
 func (e *Explorer) processHistory(ctx context.Context, sink chan<- string, history []structs.RunStateTransition, reportedStates map[structs.RunState]struct{}) (*structs.RunStateTransition, bool, error) {
@@ -2,65 +2,30 @@ package logs
 
 import (
 	"context"
-	"fmt"
+	"strings"
 	"time"
 
-	"github.com/shurcooL/graphql"
-
+	"github.com/spacelift-io/spacectl/client/runs"
 	"github.com/spacelift-io/spacectl/client/structs"
 	"github.com/spacelift-io/spacectl/internal/cmd/authenticated"
 )
 
 func runStateLogs(ctx context.Context, stack, run string, state structs.RunState, version int, sink chan<- string, stateTerminal bool) error {
-	var query struct {
-		Stack *struct {
-			Run *struct {
-				Logs *struct {
-					Exists   bool `graphql:"exists"`
-					Finished bool `graphql:"finished"`
-					HasMore  bool `graphql:"hasMore"`
-					Messages []struct {
-						Body string `graphql:"message"`
-					} `graphql:"messages"`
-					NextToken *graphql.String `graphql:"nextToken"`
-				} `graphql:"logs(state: $state, token: $token, stateVersion: $stateVersion)"`
-			} `graphql:"run(id: $run)"`
-		} `graphql:"stack(id: $stack)"`
-	}
-
-	var token *graphql.String
-	variables := map[string]any{
-		"stack":        graphql.ID(stack),
-		"run":          graphql.ID(run),
-		"state":        state,
-		"token":        token,
-		"stateVersion": graphql.Int(version), //nolint: gosec
-	}
+	service := runs.New(authenticated.Client(ctx))
 
+	var token *string
 	var backOff time.Duration
 
 	for {
-		if err := authenticated.Client().Query(ctx, &query, variables); err != nil {
+		logs, err := service.Logs(ctx, stack, run, state, version, token)
+		if err != nil {
 			return err
 		}
 
-		if query.Stack == nil {
-			return fmt.Errorf("stack %q not found", stack)
-		}
-
-		if query.Stack.Run == nil {
-			return fmt.Errorf("run %q in stack %q not found", run, stack)
-		}
-
-		if query.Stack.Run.Logs == nil {
-			return fmt.Errorf("logs for run %q in stack %q not found", run, stack)
-		}
-
-		logs := query.Stack.Run.Logs
-		variables["token"] = logs.NextToken
+		token = logs.NextToken
 
 		for _, message := range logs.Messages {
-			sink <- message.Body
+			sink <- message
 		}
 
 		if logs.Finished || (!logs.HasMore && stateTerminal) {
@@ -78,3 +43,24 @@ func runStateLogs(ctx context.Context, stack, run string, state structs.RunState
 
 	return nil
 }
+
+// StateLogs returns the logs of a single run state, as they are at the time of
+// the call, without waiting for more logs to be written.
+func StateLogs(ctx context.Context, stack, run string, state structs.RunState, version int) (string, error) {
+	sink := make(chan string)
+	done := make(chan struct{})
+
+	var logs strings.Builder
+	go func() {
+		for message := range sink {
+			logs.WriteString(message)
+		}
+		close(done)
+	}()
+
+	err := runStateLogs(ctx, stack, run, state, version, sink, true)
+	close(sink)
+	<-done
+
+	return logs.String(), err
+}
@@ -0,0 +1,62 @@
+package templateinputs
+
+import (
+	"regexp"
+	"strings"
+)
+
+// MaskedSecretValue replaces the values of secret inputs in rendered templates.
+const MaskedSecretValue = "********"
+
+var (
+	templateExpressionRegex = regexp.MustCompile(`\$\{\{\s*(.*?)\s*\}\}`)
+	inputReferenceRegex     = regexp.MustCompile(`^inputs\.([A-Za-z0-9_-]+)$`)
+)
+
+// Render substitutes the input references (${{ inputs.id }}) in the template with the provided values, masking secret inputs.
+//
+// Any other expressions (functions, conditionals, context variables) can only
+// be evaluated by Spacelift, so they are left untouched and returned in the
+// order they first appear in the template.
+func Render(rawTemplate string, definitions []Definition, values []Pair) (string, []string) {
+	secrets := make(map[string]bool, len(definitions))
+	for _, input := range definitions {
+		secrets[input.ID] = strings.EqualFold(input.Type, "secret")
+	}
+
+	valuesByID := make(map[string]string, len(values))
+	for _, pair := range values {
+		valuesByID[pair.ID] = pair.Value
+	}
+
+	var unresolved []string
+	seen := make(map[string]bool)
+
+	rendered := templateExpressionRegex.ReplaceAllStringFunc(rawTemplate, func(match string) string {
+		expression := templateExpressionRegex.FindStringSubmatch(match)[1]
+
+		if ref := inputReferenceRegex.FindStringSubmatch(expression); ref != nil {
+			if value, ok := valuesByID[ref[1]]; ok {
+				if secrets[ref[1]] {
+					return MaskedSecretValue
+				}
+				return value
+			}
+		}
+
+		if !seen[expression] {
+			seen[expression] = true
+			unresolved = append(unresolved, expression)
+		}
+
+		return match
+	})
+
+	return rendered, unresolved
+}
+
+// ReplaceExpressions replaces all the expressions remaining in a rendered
+// template with the replacement.
+func ReplaceExpressions(rendered, replacement string) string {
+	return templateExpressionRegex.ReplaceAllString(rendered, replacement)
+}
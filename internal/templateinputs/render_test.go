@@ -0,0 +1,47 @@
+package templateinputs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	rawTemplate := `stack:
+  name: ${{ inputs.env }}-network
+  space: root
+  labels:
+    - ${{inputs.env}}
+  environment:
+    variables:
+      - name: TOKEN
+        value: ${{ inputs.token }}
+  description: Created at ${{ context.time }} by ${{ context.user.login }}
+  branch: ${{ inputs.env == 'prod' ? 'main' : 'develop' }}
+  administrative: ${{ inputs.missing }}
+  autodeploy: ${{ context.time }}
+`
+
+	rendered, unresolved := Render(rawTemplate,
+		[]Definition{
+			{ID: "env", Type: "short_text"},
+			{ID: "token", Type: "secret"},
+		},
+		[]Pair{
+			{ID: "env", Value: "prod"},
+			{ID: "token", Value: "s3cr3t"},
+		},
+	)
+
+	assert.Contains(t, rendered, "name: prod-network")
+	assert.Contains(t, rendered, "- prod\n")
+	assert.Contains(t, rendered, "value: "+MaskedSecretValue)
+	assert.NotContains(t, rendered, "s3cr3t")
+	assert.Contains(t, rendered, "description: Created at ${{ context.time }} by ${{ context.user.login }}")
+	assert.Equal(t, []string{
+		"context.time",
+		"context.user.login",
+		"inputs.env == 'prod' ? 'main' : 'develop'",
+		"inputs.missing",
+	}, unresolved)
+}
@@ -0,0 +1,212 @@
+// Package templateinputs reads and validates the values of blueprint and
+// template inputs, and renders templates referencing them.
+package templateinputs
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Definition describes a single input of a blueprint or a template version.
+type Definition struct {
+	ID          string   `graphql:"id" json:"id,omitempty"`
+	Name        string   `graphql:"name" json:"name,omitempty"`
+	Default     string   `graphql:"default" json:"default,omitempty"`
+	Description string   `graphql:"description" json:"description,omitempty"`
+	Options     []string `graphql:"options" json:"options,omitempty"`
+	Type        string   `graphql:"type" json:"type,omitempty"`
+}
+
+// Pair represents a key-value pair for an input.
+type Pair struct {
+	ID    string `json:"id"`
+	Value string `json:"value"`
+}
+
+// ParseOverrides parses KEY=VALUE pairs into input values. Values are always
+// strings, which the validation converts to the type of the input.
+func ParseOverrides(overrides []string) (map[string]json.RawMessage, error) {
+	values := make(map[string]json.RawMessage, len(overrides))
+
+	for _, override := range overrides {
+		id, value, found := strings.Cut(override, "=")
+		if !found || id == "" {
+			return nil, fmt.Errorf("invalid --set value %q, expected KEY=VALUE", override)
+		}
+
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode value of input %q: %w", id, err)
+		}
+
+		values[id] = raw
+	}
+
+	return values, nil
+}
+
+// ReadFile reads a file mapping input IDs to values. Files with a .yaml
+// or .yml extension are parsed as YAML, everything else as JSON. A file
+// holding null yields an empty map, so callers can add overrides to it.
+func ReadFile(filePath string) (map[string]json.RawMessage, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read input file %q: %w", filePath, err)
+	}
+
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".yaml", ".yml":
+		var yamlInputs map[string]any
+		if err = yaml.Unmarshal(data, &yamlInputs); err != nil {
+			return nil, fmt.Errorf("failed to parse input file %q as YAML object: %w", filePath, err)
+		}
+
+		fileInputs := make(map[string]json.RawMessage, len(yamlInputs))
+		for id, value := range yamlInputs {
+			raw, err := json.Marshal(value)
+			if err != nil {
+				return nil, fmt.Errorf("failed to convert input %q from input file %q: %w", id, filePath, err)
+			}
+			fileInputs[id] = raw
+		}
+
+		return fileInputs, nil
+
+	default:
+		var fileInputs map[string]json.RawMessage
+		if err = json.Unmarshal(data, &fileInputs); err != nil {
+			return nil, fmt.Errorf("failed to parse input file %q as JSON object: %w", filePath, err)
+		}
+		if fileInputs == nil {
+			fileInputs = make(map[string]json.RawMessage)
+		}
+
+		return fileInputs, nil
+	}
+}
+
+// Validate checks the provided values against the input definitions of the
+// owner (a blueprint or a template) and converts them to the representation
+// expected by the API. It returns one error line per problem found.
+func Validate(values map[string]json.RawMessage, definitions []Definition, owner string) ([]Pair, []string) {
+	definitionsByID := make(map[string]Definition, len(definitions))
+	for _, input := range definitions {
+		definitionsByID[input.ID] = input
+	}
+
+	var errs []string
+
+	for id := range values {
+		if _, ok := definitionsByID[id]; !ok {
+			errs = append(errs, fmt.Sprintf("  - extra input %q is not defined in the %s", id, owner))
+		}
+	}
+
+	result := make([]Pair, 0, len(definitions))
+	for _, input := range definitions {
+		raw, ok := values[input.ID]
+		if !ok {
+			if input.Default != "" { // If omitted and has a default, use it.
+				raw = []byte(fmt.Sprintf("%q", input.Default))
+			} else {
+				errs = append(errs, fmt.Sprintf("  - missing required input %q (%s)", input.ID, input.Name))
+				continue
+			}
+		}
+
+		value, parseErr := parseValue(input, raw)
+		if parseErr != nil {
+			errs = append(errs, fmt.Sprintf("  - input %q (%s): %s", input.ID, input.Name, parseErr))
+			continue
+		}
+
+		result = append(result, Pair{ID: input.ID, Value: value})
+	}
+
+	return result, errs
+}
+
+// parseValue converts a raw JSON value to the string representation expected
+// by the API. Text and select types require a JSON string.
+// Number, float, and boolean accept either their native JSON type or a JSON
+// string that parses to the correct type (e.g. "42", "3.14", "true").
+func parseValue(input Definition, raw json.RawMessage) (string, error) {
+	switch strings.ToLower(input.Type) {
+	case "", "short_text", "long_text", "secret":
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return "", fmt.Errorf("must be a string")
+		}
+		return s, nil
+
+	case "number":
+		// Accept a JSON integer directly, or a JSON string that holds an integer.
+		// Use *int64 so JSON null yields nil (json.Unmarshal(null, &int64) is a silent no-op).
+		var n *int64
+		if err := json.Unmarshal(raw, &n); err == nil && n != nil {
+			return strconv.FormatInt(*n, 10), nil
+		}
+		var s string
+		if err := json.Unmarshal(raw, &s); err == nil {
+			if v, err := strconv.ParseInt(s, 10, 64); err == nil {
+				return strconv.FormatInt(v, 10), nil
+			}
+		}
+		return "", fmt.Errorf("must be an integer")
+
+	case "float":
+		// Accept a JSON number directly, or a JSON string that holds a float.
+		// Use *float64 so JSON null yields nil.
+		var f *float64
+		if err := json.Unmarshal(raw, &f); err == nil && f != nil {
+			return strconv.FormatFloat(*f, 'f', -1, 64), nil
+		}
+		var s string
+		if err := json.Unmarshal(raw, &s); err == nil {
+			if v, err := strconv.ParseFloat(s, 64); err == nil {
+				return strconv.FormatFloat(v, 'f', -1, 64), nil
+			}
+		}
+		return "", fmt.Errorf("must be a float")
+
+	case "boolean":
+		// Accept a JSON boolean directly, or a JSON string that holds a boolean.
+		// Use *bool so JSON null yields nil instead of the silent no-op that
+		// json.Unmarshal(null, &bool) produces.
+		var b *bool
+		if err := json.Unmarshal(raw, &b); err == nil && b != nil {
+			return strconv.FormatBool(*b), nil
+		}
+		var s string
+		if err := json.Unmarshal(raw, &s); err == nil {
+			if v, err := strconv.ParseBool(s); err == nil {
+				return strconv.FormatBool(v), nil
+			}
+		}
+		return "", fmt.Errorf("must be a boolean")
+
+	case "select":
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return "", fmt.Errorf("must be a string")
+		}
+		if !slices.Contains(input.Options, s) {
+			return "", fmt.Errorf("must be one of [%s], got %q", strings.Join(input.Options, ", "), s)
+		}
+		return s, nil
+
+	default:
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return "", fmt.Errorf("must be a string")
+		}
+		return s, nil
+	}
+}
@@ -0,0 +1,75 @@
+package templateinputs
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		fileName string
+		content  string
+		want     map[string]json.RawMessage
+		wantErr  string
+	}{
+		{
+			name:     "json object",
+			fileName: "inputs.json",
+			content:  `{"env": "prod", "count": 3}`,
+			want:     map[string]json.RawMessage{"env": json.RawMessage(`"prod"`), "count": json.RawMessage(`3`)},
+		},
+		{
+			name:     "yaml object",
+			fileName: "inputs.yaml",
+			content:  "env: prod\ncount: 3\n",
+			want:     map[string]json.RawMessage{"env": json.RawMessage(`"prod"`), "count": json.RawMessage(`3`)},
+		},
+		{
+			name:     "json null",
+			fileName: "inputs.json",
+			content:  "null",
+			want:     map[string]json.RawMessage{},
+		},
+		{
+			name:     "yaml null",
+			fileName: "inputs.yml",
+			content:  "~\n",
+			want:     map[string]json.RawMessage{},
+		},
+		{
+			name:     "json array",
+			fileName: "inputs.json",
+			content:  `["prod"]`,
+			wantErr:  "as JSON object",
+		},
+		{
+			name:     "yaml list",
+			fileName: "inputs.yaml",
+			content:  "- prod\n",
+			wantErr:  "as YAML object",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.fileName)
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0600))
+
+			got, err := ReadFile(path)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.NotNil(t, got)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
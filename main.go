@@ -13,7 +13,9 @@ import (
 	"github.com/spacelift-io/spacectl/internal/cmd"
 	"github.com/spacelift-io/spacectl/internal/cmd/api"
 	"github.com/spacelift-io/spacectl/internal/cmd/audittrail"
+	"github.com/spacelift-io/spacectl/internal/cmd/authenticated"
 	"github.com/spacelift-io/spacectl/internal/cmd/blueprint"
+	"github.com/spacelift-io/spacectl/internal/cmd/dev"
 	"github.com/spacelift-io/spacectl/internal/cmd/mcp"
 	"github.com/spacelift-io/spacectl/internal/cmd/module"
 	"github.com/spacelift-io/spacectl/internal/cmd/policy"
@@ -42,6 +44,9 @@ func getSpaceliftInstanceVersion(ctx context.Context) cmd.SpaceliftInstanceVersi
 	}
 
 	httpClient := client.GetHTTPClient()
+	if err := authenticated.ConfigureHTTPClient(httpClient, session.NetworkSettingsFromCurrentProfile()); err != nil {
+		return instanceVersion
+	}
 
 	// Create a new session - this may fail if the user doesn't have valid credentials.
 	// In that case we just treat the version as unknown.
@@ -108,6 +113,7 @@ func main() {
 			policy.Command(),
 			audittrail.Command(),
 			mcp.Command(),
+			dev.Command(),
 		})...),
 	}
 